@@ -149,6 +149,8 @@ const (
 	LeastAllocated ScoringStrategyType = "LeastAllocated"
 	// LeastNUMANodes strategy favors nodes which requires least amount of NUMA nodes to satisfy resource requests for given pod
 	LeastNUMANodes ScoringStrategyType = "LeastNUMANodes"
+	// InterPodNUMAAffinity strategy favors nodes where the pod can be placed on the same NUMA node as the running pods it references
+	InterPodNUMAAffinity ScoringStrategyType = "InterPodNUMAAffinity"
 )
 
 type ScoringStrategy struct {
@@ -182,6 +184,161 @@ const (
 	CacheInformerDedicated CacheInformerMode = "Dedicated"
 )
 
+// ResourceAliasSpec makes the NUMA alignment treat a resource name as another one, like a device resource renamed
+// between versions of its device plugin.
+type ResourceAliasSpec struct {
+	// Name of the resource requested by the pods.
+	Name string `json:"name"`
+	// Alias is the other name under which the NodeResourceTopology objects may report the resource.
+	Alias string `json:"alias"`
+}
+
+// ResourceRoundingSpec describes the allocation unit of a resource.
+type ResourceRoundingSpec struct {
+	// Name of the resource.
+	Name string `json:"name"`
+	// Multiple is the allocation unit of the resource. Must be greater than zero.
+	Multiple int64 `json:"multiple"`
+}
+
+// NodeQuarantine sets when the nodes repeatedly reporting invalid topology data are excluded from scheduling.
+type NodeQuarantine struct {
+	// Threshold is the number of consecutive invalid NodeResourceTopology updates after which the node is quarantined.
+	// Must be greater than zero.
+	Threshold int64 `json:"threshold"`
+	// RecoveryThreshold is the number of consecutive valid NodeResourceTopology updates after which a quarantined
+	// node is considered again. Must be greater than zero.
+	RecoveryThreshold int64 `json:"recoveryThreshold"`
+}
+
+// TopologyManagerOverlay describes a topology manager configuration to be assumed on all the nodes.
+type TopologyManagerOverlay struct {
+	// Policy is the topology manager policy, as in the kubelet configuration, e.g. "single-numa-node".
+	Policy string `json:"policy"`
+	// Scope is the topology manager scope, as in the kubelet configuration, e.g. "pod".
+	Scope string `json:"scope"`
+}
+
+// DeviceAllocationMode is a "string" type
+type DeviceAllocationMode string
+
+const (
+	// DeviceAllocationExclusive devices are allocated to one container each, and counted as such.
+	DeviceAllocationExclusive DeviceAllocationMode = "Exclusive"
+	// DeviceAllocationShared devices can be used by many containers at once.
+	DeviceAllocationShared DeviceAllocationMode = "Shared"
+)
+
+// DeviceAllocationSpec describes how the devices of a resource are allocated to the containers.
+type DeviceAllocationSpec struct {
+	// Name of the resource.
+	Name string `json:"name"`
+	// Mode is the allocation mode of the devices. "Exclusive" devices are checked against the quantity
+	// available on the NUMA nodes. "Shared" devices are available on every NUMA node exposing them,
+	// and the requests don't reduce their availability.
+	Mode DeviceAllocationMode `json:"mode"`
+	// SharingCap is the maximum quantity a container can request of a "Shared" resource.
+	// Zero means no limit. Must be zero for "Exclusive" resources.
+	SharingCap int64 `json:"sharingCap,omitempty"`
+}
+
+// NUMASpanMode is a "string" type
+type NUMASpanMode string
+
+const (
+	// NUMASpanSingleNUMA resources must be allocated from a single NUMA node.
+	NUMASpanSingleNUMA NUMASpanMode = "SingleNUMA"
+	// NUMASpanMaySpan resources can be allocated from many NUMA nodes, at the cost of a performance penalty.
+	NUMASpanMaySpan NUMASpanMode = "MaySpan"
+)
+
+// NUMASpanSpec describes whether a resource must be allocated from a single NUMA node.
+type NUMASpanSpec struct {
+	// Name of the resource.
+	Name string `json:"name"`
+	// Mode is the NUMA span mode of the resource. "SingleNUMA" resources constrain the NUMA node the pod
+	// is aligned on. "MaySpan" resources are checked against, and consumed from, all the NUMA nodes the
+	// pod can use, the NUMA node the pod is aligned on first.
+	Mode NUMASpanMode `json:"mode"`
+}
+
+// DeviceCPUBalance sets how the nodes keeping the remaining devices of their NUMA nodes usable are favored.
+type DeviceCPUBalance struct {
+	// Resource is the device resource, e.g. "nvidia.com/gpu".
+	Resource string `json:"resource"`
+	// CPUsPerDevice is the number of cpus the pods need alongside each device. Must be greater than zero.
+	CPUsPerDevice int64 `json:"cpusPerDevice"`
+	// Weight is the percentage, from 1 to 100, of the score of the nodes given by the balance between the devices
+	// and the cpus left on the NUMA node fitting the pod, the rest being given by the scoring strategy.
+	Weight int64 `json:"weight"`
+}
+
+// OrphanCapacityMode is a "string" type
+type OrphanCapacityMode string
+
+const (
+	// OrphanCapacityNUMANode attributes all the orphan capacity to a single NUMA node.
+	OrphanCapacityNUMANode OrphanCapacityMode = "NUMANode"
+	// OrphanCapacityDistribute spreads the orphan capacity evenly across the NUMA nodes.
+	OrphanCapacityDistribute OrphanCapacityMode = "Distribute"
+)
+
+// OrphanCapacityAttribution sets how the capacity of the nodes not attributed to any of their NUMA nodes is attributed to them.
+type OrphanCapacityAttribution struct {
+	// Mode is either "NUMANode", attributing all the orphan capacity to the NUMA node set by NUMANode, or "Distribute",
+	// spreading it evenly across the NUMA nodes, the lowest IDs getting the remainder.
+	Mode OrphanCapacityMode `json:"mode"`
+	// NUMANode is the ID of the NUMA node the orphan capacity is attributed to in the "NUMANode" mode.
+	NUMANode int `json:"numaNode,omitempty"`
+}
+
+// SchedulabilityReport sets the periodic report of the NUMA-aligned capacity left on the nodes.
+type SchedulabilityReport struct {
+	// PeriodSeconds is the interval between two reports. Must be greater than zero.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty"`
+	// ReferencePodRequests are the requests of the guaranteed pod used as unit of capacity: the report tells
+	// how many such pods could still be aligned on each node. Must not be empty.
+	ReferencePodRequests v1.ResourceList `json:"referencePodRequests,omitempty"`
+}
+
+// DecisionLog sets the in-memory log of the recent filter decisions, served as JSON by the /configz endpoint of the
+// scheduler to debug the plugin without raising the log verbosity.
+type DecisionLog struct {
+	// Size is the number of recent decisions kept, the oldest ones being dropped. Must be greater than zero.
+	Size int64 `json:"size,omitempty"`
+}
+
+// DecisionWebhookFailurePolicy is a "string" type
+type DecisionWebhookFailurePolicy string
+
+const (
+	// DecisionWebhookIgnore allows the placements the endpoint could not review.
+	DecisionWebhookIgnore DecisionWebhookFailurePolicy = "Ignore"
+	// DecisionWebhookFail rejects the placements the endpoint could not review.
+	DecisionWebhookFail DecisionWebhookFailurePolicy = "Fail"
+)
+
+// DecisionWebhook sets the external endpoint reviewing the NUMA placements of the pods. The plugin POSTs the decision,
+// with the pod, the node, the NUMA node of each container and the topology manager policy and scope, as JSON, and
+// expects a JSON response with an "allowed" boolean and an optional "reason".
+type DecisionWebhook struct {
+	// URL is the http or https URL of the endpoint. Must not be empty.
+	URL string `json:"url"`
+	// TimeoutSeconds is the time the endpoint is given to respond. Must be greater than zero. Defaults to 10.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// FailurePolicy is either "Ignore", allowing the placements on errors and timeouts of the endpoint, or "Fail",
+	// rejecting them. Defaults to "Ignore".
+	FailurePolicy DecisionWebhookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// QoSResourcePolicy tunes how the QoS class of the pods changes the NUMA capacity checks. By default the NUMA capacity
+// of the cpu, memory and hugepages is checked only for the Guaranteed pods, the only ones the kubelet pins to NUMA nodes.
+type QoSResourcePolicy struct {
+	// BurstableEnforcedResources lists the resources among cpu, memory and hugepages-<size> whose NUMA capacity is
+	// checked for the Burstable pods as well, e.g. to keep them off the NUMA nodes whose memory is exhausted.
+	BurstableEnforcedResources []string `json:"burstableEnforcedResources,omitempty"`
+}
+
 // NodeResourceTopologyCache define configuration details for the NodeResourceTopology cache.
 type NodeResourceTopologyCache struct {
 	// ForeignPodsDetect sets how foreign pods should be handled.
@@ -203,6 +360,11 @@ type NodeResourceTopologyCache struct {
 	// guaranteed to best suit the cache needs, at cost of one extra connection.
 	// If unspecified, default is "Dedicated"
 	InformerMode *CacheInformerMode `json:"informerMode,omitempty"`
+	// FullResyncPeriodSeconds, if > 0, sets the period of the full resync, which lists all the NodeResourceTopology
+	// objects at once and reconciles in bulk against them the nodes marked dirty, complementing the regular resync
+	// which checks the dirty nodes one at a time. Has no effect if caching is disabled (CacheResyncPeriod is zero)
+	// or if DiscardReservedNodes is enabled. If unspecified, the full resync is disabled.
+	FullResyncPeriodSeconds *int64 `json:"fullResyncPeriodSeconds,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -221,6 +383,238 @@ type NodeResourceTopologyMatchArgs struct {
 	DiscardReservedNodes bool `json:"discardReservedNodes,omitempty"`
 	// Cache enables to fine tune the caching behavior
 	Cache *NodeResourceTopologyCache `json:"cache,omitempty"`
+	// ResourceRounding sets the allocation unit of resources allocatable only in fixed chunks, typically devices.
+	// When checking the NUMA capacity, the requests of these resources are rounded up to a multiple of the unit.
+	// Resources not listed are not rounded.
+	ResourceRounding []ResourceRoundingSpec `json:"resourceRounding,omitempty"`
+	// MemoryAlignAgainstLimits makes the filter check the NUMA memory alignment of burstable pods
+	// against their memory limits rather than their requests, to be conservative about the runtime pressure.
+	MemoryAlignAgainstLimits bool `json:"memoryAlignAgainstLimits,omitempty"`
+	// ContainerCPUExclusivity makes the filter, at container scope, check the NUMA CPU capacity for the containers
+	// whose CPU and memory requests equal their limits, even if the pod as a whole is not guaranteed.
+	ContainerCPUExclusivity bool `json:"containerCPUExclusivity,omitempty"`
+	// DeviceAvoidanceResources lists device resources, typically GPUs, whose NUMA nodes should be kept available
+	// for the pods requesting them. When scoring, nodes on which a pod not requesting any of these resources
+	// fits only on NUMA nodes having some of them available are penalized.
+	DeviceAvoidanceResources []string `json:"deviceAvoidanceResources,omitempty"`
+	// PCIeGroupAlignment makes the filter require the requests of more than one device to fit in a single PCIe group,
+	// like the devices sharing a PCIe switch, on the chosen NUMA node. Applies only to the resources for which the NUMA
+	// zones report the PCIe groups; the other resources are only checked for the NUMA alignment.
+	PCIeGroupAlignment bool `json:"pcieGroupAlignment,omitempty"`
+	// Quarantine enables excluding from scheduling the nodes whose NodeResourceTopology objects repeatedly fail
+	// the validation or lead to over-reservations. If unspecified, the nodes are never quarantined.
+	Quarantine *NodeQuarantine `json:"quarantine,omitempty"`
+	// NodeHeadroomWeight is the percentage, from 0 to 100, of the score of the nodes given by their overall remaining
+	// allocatable resources once the pod is placed, the rest being given by the scoring strategy. Among the nodes aligning
+	// the pod, this favors the ones with more headroom, preserving the small nodes for the small pods. Zero disables it.
+	NodeHeadroomWeight int64 `json:"nodeHeadroomWeight,omitempty"`
+	// TopologyManagerOverlay makes the plugin assume the given topology manager configuration on all the nodes,
+	// ignoring the one reported in their NodeResourceTopology objects. Meant for shadow or test scheduler profiles
+	// evaluating the effect of a configuration change before rolling it out; not meant for production profiles.
+	TopologyManagerOverlay *TopologyManagerOverlay `json:"topologyManagerOverlay,omitempty"`
+	// DeviceAllocation sets the allocation mode of device resources. Resources not listed are "Exclusive".
+	DeviceAllocation []DeviceAllocationSpec `json:"deviceAllocation,omitempty"`
+	// PlacementWaste makes the filter compute the NUMA-local resources stranded by the placement of the pod on each node,
+	// that is the resources left on the chosen NUMA nodes which can't be used by guaranteed pods anymore because the cpu
+	// or the memory of the same NUMA node is exhausted. The result is stored in the CycleState and exposed as metric.
+	PlacementWaste bool `json:"placementWaste,omitempty"`
+	// StabilityWeight is the percentage, from 0 to 100, of the score of the nodes given by the stability of the placement
+	// of the pod, the rest being given by the scoring strategy. A placement on a NUMA node is more stable the more headroom
+	// the NUMA node keeps once the pod is placed and if another pod of the same size still fits, making the later
+	// disruptions of long-running pods less likely. Zero disables it.
+	StabilityWeight int64 `json:"stabilityWeight,omitempty"`
+	// StrictTopologyPolicies makes the plugin consider misconfigured the nodes listing more than one distinct policy
+	// in the deprecated TopologyPolicies field of their NodeResourceTopology objects, instead of using the first one.
+	// Misconfigured nodes are handled like the nodes without NodeResourceTopology objects. The nodes whose Attributes
+	// provide both the topology manager scope and policy don't use the TopologyPolicies field, so are not checked.
+	StrictTopologyPolicies bool `json:"strictTopologyPolicies,omitempty"`
+	// DeviceCPUBalance makes the score favor the nodes on which the NUMA node fitting the pod keeps enough cpus
+	// to use the devices it has left, so the devices are not stranded by the exhaustion of the cpus.
+	// If unspecified, the balance is not considered.
+	DeviceCPUBalance *DeviceCPUBalance `json:"deviceCPUBalance,omitempty"`
+	// ResourceNameHints makes the filter, when rejecting a node lacking a requested resource, look for a resource
+	// the node reports with a similar name, like the same device from another vendor, and report it in the status
+	// to help catching misconfigured workloads.
+	ResourceNameHints bool `json:"resourceNameHints,omitempty"`
+	// ContainerScopeForceSameNUMA makes the filter, at container scope, require all the containers of the pod to share
+	// a single NUMA node fitting the sum of their requests, like at pod scope. This is stricter than the kubelet, which
+	// at container scope can spread the containers across NUMA nodes.
+	ContainerScopeForceSameNUMA bool `json:"containerScopeForceSameNUMA,omitempty"`
+	// StickyNUMAWeight is the percentage, from 0 to 100, of the score of the nodes given by the match with the NUMA placement
+	// recorded in the sticky-numa-placement annotation of the pod, the rest being given by the scoring strategy, to let pods
+	// return to the same NUMA node across restarts. Pods without the annotation are not affected. Zero disables it.
+	StickyNUMAWeight int64 `json:"stickyNUMAWeight,omitempty"`
+	// SchedulabilityReport enables the periodic report, off the scheduling path, of how many reference pods could still
+	// be aligned on each node given the cached NodeResourceTopology data. If unspecified, no report is made.
+	SchedulabilityReport *SchedulabilityReport `json:"schedulabilityReport,omitempty"`
+	// NUMAAffinityMemorySeconds, if > 0, makes the filter remember for this long the resources a node reported in its NUMA
+	// zones, and keep considering them NUMA-affine, with nothing available on the NUMA nodes, when the following updates
+	// report them only at node level. This dampens the verdict flapping caused by producers reporting the NUMA affinity
+	// inconsistently. Zero disables it.
+	NUMAAffinityMemorySeconds int64 `json:"numaAffinityMemorySeconds,omitempty"`
+	// SocketFreenessWeight is the percentage, from 0 to 100, of the score of the nodes given by the share of their sockets
+	// left free once the pod is placed, the rest being given by the scoring strategy, to keep whole sockets free for the
+	// workloads needing them while still preferring a tight NUMA fit. The sockets are the parents of the NUMA zones; the
+	// nodes not reporting them are not affected. Zero disables it.
+	SocketFreenessWeight int64 `json:"socketFreenessWeight,omitempty"`
+	// RestrictedAsSingleNUMA makes the plugin handle the nodes reporting the restricted topology manager policy like the
+	// ones reporting the single-numa-node policy, requiring the pods to fit in a single NUMA node, instead of not filtering
+	// them. This is stricter than the kubelet, which admits the pods spanning the fewest NUMA nodes possible.
+	RestrictedAsSingleNUMA bool `json:"restrictedAsSingleNUMA,omitempty"`
+	// ExcludedResources lists the resources whose NUMA alignment is never checked by the filter, like the devices the
+	// producers report per NUMA node but the kubelet doesn't align. Pods can override it with the excluded-resources
+	// annotation, and re-include a resource listing it in the required-resources annotation.
+	ExcludedResources []string `json:"excludedResources,omitempty"`
+	// RequiredAlignmentResources lists the resources whose NUMA alignment is checked regardless of the pod QoS class,
+	// like the required-resources annotation for all the pods. Pods can override it with the excluded-resources annotation.
+	RequiredAlignmentResources []string `json:"requiredAlignmentResources,omitempty"`
+	// SidecarOverheadEstimate is the estimated overhead of the sidecars injected into the pods after the scheduling, like
+	// the service mesh proxies, accounted as an additional container of each pod when checking the NUMA alignment.
+	// It is a blunt safety margin, not the actual sidecar requests. Pods can opt out with the ignore-sidecar-overhead
+	// annotation. If unspecified, no overhead is added.
+	SidecarOverheadEstimate v1.ResourceList `json:"sidecarOverheadEstimate,omitempty"`
+	// FractionalCPUShared makes the filter consider the CPUs of the containers of Guaranteed pods requesting a
+	// non-integer amount of CPUs as shared, like the static CPU manager policy does, so they don't constrain the NUMA
+	// node choice. By default the CPUs of all the containers of Guaranteed pods are checked against the NUMA nodes.
+	FractionalCPUShared bool `json:"fractionalCPUShared,omitempty"`
+	// ResourceAliases lists the resources the NodeResourceTopology objects may report under another name, like during
+	// the migration to a renamed device resource. The filter matches the requests of a resource to the per-NUMA
+	// availability reported under its alias when the NUMA nodes don't report it under its name. Several aliases of
+	// the same resource are listed as several entries.
+	ResourceAliases []ResourceAliasSpec `json:"resourceAliases,omitempty"`
+	// NUMASpreadWeight is the percentage, from 0 to 100, of the score of the nodes given by the chance to place the pod
+	// on a NUMA node not hosting any of its siblings, the running pods with the same controller, the rest being given by
+	// the scoring strategy, to spread the replicas of the HA-sensitive workloads across NUMA nodes. The nodes not running
+	// any sibling get a neutral score. Zero disables it.
+	NUMASpreadWeight int64 `json:"numaSpreadWeight,omitempty"`
+	// AllocatableMismatchPercent, if > 0, makes the filter compare, on each update of the NodeResourceTopology objects, the
+	// allocatable of each resource the NUMA zones report with the allocatable of the node, and log and count as metric the
+	// resources whose sum over the NUMA zones diverges by more than this percentage of the node allocatable, which usually
+	// means a producer bug or a resource not mapped to the NUMA nodes. This is observability only: the verdicts are not
+	// affected. Zero disables it.
+	AllocatableMismatchPercent int64 `json:"allocatableMismatchPercent,omitempty"`
+	// NUMAPowerHintAttribute is the name of the attribute of the NUMA zones of the NodeResourceTopology objects carrying
+	// a power or thermal hint, an integer lower for the more efficient NUMA nodes. If set, the filter picks, among the
+	// NUMA nodes fitting the pod, the one with the lowest hint instead of the lowest ID, the NUMA nodes not reporting
+	// a hint coming last. It affects the NUMA nodes the plugin records for the pod, not the choice of the kubelet.
+	// If unspecified, or when the NUMA nodes report no hint, the lowest ID is picked.
+	NUMAPowerHintAttribute string `json:"numaPowerHintAttribute,omitempty"`
+	// OrphanCapacityAttribution makes the filter attribute to the NUMA nodes the capacity a node reports as allocatable
+	// but its NUMA zones don't, so the resources of the producers under-reporting the NUMA capacity aren't unusable for
+	// the alignment. The orphan capacity is assumed to be unused. If unspecified, it is not attributed.
+	OrphanCapacityAttribution *OrphanCapacityAttribution `json:"orphanCapacityAttribution,omitempty"`
+	// ChosenNUMAMetric makes the plugin count, once the node of a pod is reserved, the pod on each NUMA node the filter
+	// chose for its containers, in the nrt_chosen_numa_total metric labeled by node and NUMA node, to audit the balance
+	// of the placements across the NUMA nodes. The cardinality of the metric grows with the number of nodes.
+	ChosenNUMAMetric bool `json:"chosenNUMAMetric,omitempty"`
+	// BurstFactorPercent, if > 0, makes the filter account the CPU and memory requests of the Burstable pods multiplied
+	// by this percentage, e.g. 125 for a 1.25 factor, when checking them against the NUMA nodes, so the placements leave
+	// room for the pods to burst within their NUMA node. It only matters when the NUMA capacity is checked for the
+	// Burstable pods, e.g. when they require the alignment of their CPUs or memory. Guaranteed pods are not affected.
+	// Must be zero or at least 100.
+	BurstFactorPercent int64 `json:"burstFactorPercent,omitempty"`
+	// MaxCandidateNUMASets, if > 0, caps the number of candidate sets of NUMA nodes the LeastNUMANodes scoring strategy
+	// evaluates per node and per pod or container, to bound the scoring cost on the nodes with many NUMA nodes. The sets
+	// with fewer NUMA nodes are evaluated first and, among the sets of the same size, the ones with the lower average
+	// distance; the rest are truncated, and the nodes on which no evaluated set fits get the minimum score.
+	// Zero evaluates all the sets.
+	MaxCandidateNUMASets int64 `json:"maxCandidateNUMASets,omitempty"`
+	// ExemptNamespaces lists the namespaces whose pods are never NUMA-constrained by the filter, like kube-system, to keep
+	// the critical system pods schedulable on the nodes whose NUMA nodes can't align them. Their NUMA alignment is
+	// not checked at all.
+	ExemptNamespaces []string `json:"exemptNamespaces,omitempty"`
+	// OwnerNUMALocalityWeight is the percentage, from 0 to 100, of the score of the nodes given by the chance to place the
+	// pod on the NUMA node hosting most of its siblings, the running pods with the same controller, the rest being given by
+	// the scoring strategy, for the workloads benefiting from the data a prior replica cached on a NUMA node. The nodes
+	// not running any sibling get a neutral score. Zero disables it.
+	OwnerNUMALocalityWeight int64 `json:"ownerNUMALocalityWeight,omitempty"`
+	// DecisionLog enables the in-memory log of the recent filter decisions, with the pod, the node, the verdict and the
+	// reason, served by the /configz endpoint of the scheduler regardless of the log verbosity. If unspecified, no decision is kept.
+	DecisionLog *DecisionLog `json:"decisionLog,omitempty"`
+	// ContainerScopeInitSameNUMA makes the filter, at container scope, require the init containers of the pod to be aligned on
+	// a NUMA node chosen for its app containers, for the init containers warming NUMA-local caches. The app containers are still
+	// aligned independently. This is stricter than the kubelet, which aligns the init containers independently.
+	ContainerScopeInitSameNUMA bool `json:"containerScopeInitSameNUMA,omitempty"`
+	// DefaultPolicyWhenMissing makes the filter, on the nodes without NodeResourceTopology object, assume the given topology
+	// manager configuration and handle the node as a single NUMA node holding its allocatable resources, so the pods are still
+	// checked against a reasonable approximation. If unspecified, the nodes without NodeResourceTopology object are not filtered.
+	DefaultPolicyWhenMissing *TopologyManagerOverlay `json:"defaultPolicyWhenMissing,omitempty"`
+	// PersistentInvalidTopologyThreshold is the number of consecutive filter calls finding invalid NodeResourceTopology data
+	// for a node from which the invalidity is deemed persistent rather than transient, like while the cache resyncs. The filter
+	// runs once per pod and per scheduling attempt, so the threshold should grow with the scheduling load. A single filter call
+	// finding valid data resets the count. Defaults to 30.
+	PersistentInvalidTopologyThreshold int64 `json:"persistentInvalidTopologyThreshold,omitempty"`
+	// DeviceHintConflictWeight is the percentage, from 0 to 100, of the score of the nodes given by the agreement between
+	// the NUMA nodes able to provide the devices the pod requests and the NUMA nodes able to host the whole pod, the rest
+	// being given by the scoring strategy. The device manager may hint the NUMA nodes having the devices but not the other
+	// resources, making the admission fail, so this favors the nodes where the devices are available where the pod fits.
+	// Pods not requesting devices are not affected. Zero disables it.
+	DeviceHintConflictWeight int64 `json:"deviceHintConflictWeight,omitempty"`
+	// NUMASpan sets whether the resources must be allocated from a single NUMA node, like the devices, or may span many
+	// NUMA nodes, like the memory can with a performance penalty. Resources not listed are "SingleNUMA".
+	NUMASpan []NUMASpanSpec `json:"numaSpan,omitempty"`
+	// LogTopologyAge makes the filter log, at verbosity 4, each decision along with the age of the NodeResourceTopology
+	// data of the node, to correlate the questionable placements with stale data. The age is derived from the "updateTime"
+	// attribute of the NodeResourceTopology object, in RFC 3339 format, or else from the time of its last update.
+	LogTopologyAge bool `json:"logTopologyAge,omitempty"`
+	// BestEffortNodeLevelDevices makes the filter count, for the BestEffort pods, the device instances the node reports
+	// only at node level, with unknown NUMA affinity, when the instances reported on the NUMA nodes are insufficient.
+	// The BestEffort pods get no NUMA guarantee anyway, so they can use the instances of partially reported topologies.
+	// It has no effect if the orphan capacity is attributed to the NUMA nodes.
+	BestEffortNodeLevelDevices bool `json:"bestEffortNodeLevelDevices,omitempty"`
+	// CapacityNormalizedScoring makes the LeastAllocated, MostAllocated and BalancedAllocation strategies normalize the
+	// contribution of each resource against its capacity on the NUMA node, to a 0-1 range computed in milli-units, before
+	// weighting it, instead of against its available quantity in whole units. This way the devices, counted in few units,
+	// and the CPUs, counted in millicores, contribute comparably.
+	CapacityNormalizedScoring bool `json:"capacityNormalizedScoring,omitempty"`
+	// FailFastWithoutEnforcingNodes makes the plugin reject upfront, with a "no topology-enforcing nodes available"
+	// reason, the pods requiring the NUMA alignment of a resource they request when no node of the cluster enforces
+	// a topology manager policy other than "none", instead of letting the pods fail on each node, or land unaligned.
+	FailFastWithoutEnforcingNodes bool `json:"failFastWithoutEnforcingNodes,omitempty"`
+	// GatedNUMAPreferenceWeight is the percentage, from 0 to 100, of the score of the nodes given by the fit of the pod on the
+	// NUMA nodes listed in its gated-numa-preference annotation, typically set by the controller removing its scheduling
+	// gate, the rest being given by the scoring strategy. Pods without the annotation are not affected. Zero disables it.
+	GatedNUMAPreferenceWeight int64 `json:"gatedNUMAPreferenceWeight,omitempty"`
+	// NUMALocalEphemeralStorage makes the filter align the ephemeral-storage like the other per-NUMA resources on the nodes
+	// whose NodeResourceTopology reports it per NUMA node, e.g. on NUMA-local NVMe disks. Otherwise the ephemeral-storage
+	// is handled at node level, like the kubelet does, unless the pod requires its alignment.
+	NUMALocalEphemeralStorage bool `json:"numaLocalEphemeralStorage,omitempty"`
+	// QoSResourcePolicy, if set, tunes which resources have their NUMA capacity checked for the pods which are not
+	// Guaranteed. Otherwise only the NUMA capacity of the resources the kubelet does not pin, like the devices, is checked.
+	QoSResourcePolicy *QoSResourcePolicy `json:"qosResourcePolicy,omitempty"`
+	// SameSocketResourcesWeight is the percentage, from 0 to 100, of the score of the nodes given by the ability to place the
+	// resources listed in the same-socket-resources annotation of the pod, like a GPU and a NIC, on NUMA nodes of the same socket,
+	// the rest being given by the scoring strategy. The nodes not reporting the sockets get a neutral score. Pods without the
+	// annotation are not affected. Zero disables it.
+	SameSocketResourcesWeight int64 `json:"sameSocketResourcesWeight,omitempty"`
+	// EnabledPolicies lists the topology manager policies, like "single-numa-node", the plugin acts on. The nodes reporting
+	// another policy are handled like the nodes reporting the none policy, letting all the pods pass, e.g. to roll out the
+	// enforcement one policy at a time. This is applied before RestrictedAsSingleNUMA. If empty, all the policies are acted on.
+	EnabledPolicies []string `json:"enabledPolicies,omitempty"`
+	// NUMAOccupancyFromPods makes the filter compute the resources available on the NUMA nodes subtracting from the allocatable
+	// resources reported in the NodeResourceTopology the requests of the pods running on the node, on the NUMA nodes recorded
+	// in their assigned-numa-nodes annotation, instead of trusting the reported availability, which lags behind the placements.
+	// The plugin records the NUMA nodes of the pods it places in the annotation when binding them, which needs the permission
+	// to patch the pods. The pods without the annotation are not accounted for.
+	NUMAOccupancyFromPods bool `json:"numaOccupancyFromPods,omitempty"`
+	// DecisionWebhook makes the plugin submit the NUMA placement of each pod, before binding it, to an external endpoint
+	// which can veto it, for the external governance of the placements. If unspecified, the placements are not submitted.
+	DecisionWebhook *DecisionWebhook `json:"decisionWebhook,omitempty"`
+	// DegradedNUMAWeight is the percentage, from 0 to 100, of the score of the nodes given by the health of the NUMA node
+	// expected to host the pod, as reported by the "numaDegradation" attribute of its zone, the rest being given by the scoring
+	// strategy. This keeps the pods off the NUMA nodes with flaky hardware. Zero disables it.
+	DegradedNUMAWeight int64 `json:"degradedNUMAWeight,omitempty"`
+	// DegradedNUMAThreshold is the degradation, from 1 to 100, from which the filter excludes the NUMA nodes, as reported by
+	// the "numaDegradation" attribute of their zone. Zero disables it: the degraded NUMA nodes are only scored lower.
+	DegradedNUMAThreshold int64 `json:"degradedNUMAThreshold,omitempty"`
+	// StaticCPUManagerPolicy tells the nodes run the static CPU manager policy, which allocates exclusive CPUs only for the
+	// integer CPU requests, the fractional ones running on the shared pool. The filter then checks, for the Guaranteed pods,
+	// the integer CPU requests against the whole CPUs available on the NUMA nodes, and the fractional ones against the millicores.
+	StaticCPUManagerPolicy bool `json:"staticCPUManagerPolicy,omitempty"`
+	// GuaranteedPodsRejectedOnNonePolicy makes the filter reject the Guaranteed pods on the nodes reporting the "none" topology
+	// manager policy, which align nothing, so that these nodes host only the BestEffort and Burstable pods. The nodes handled as
+	// having the "none" policy only because their policy is not in EnabledPolicies are not affected.
+	GuaranteedPodsRejectedOnNonePolicy bool `json:"guaranteedPodsRejectedOnNonePolicy,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
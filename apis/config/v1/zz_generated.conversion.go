@@ -50,6 +50,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DecisionLog)(nil), (*config.DecisionLog)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DecisionLog_To_config_DecisionLog(a.(*DecisionLog), b.(*config.DecisionLog), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DecisionLog)(nil), (*DecisionLog)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DecisionLog_To_v1_DecisionLog(a.(*config.DecisionLog), b.(*DecisionLog), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DecisionWebhook)(nil), (*config.DecisionWebhook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DecisionWebhook_To_config_DecisionWebhook(a.(*DecisionWebhook), b.(*config.DecisionWebhook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DecisionWebhook)(nil), (*DecisionWebhook)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DecisionWebhook_To_v1_DecisionWebhook(a.(*config.DecisionWebhook), b.(*DecisionWebhook), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeviceAllocationSpec)(nil), (*config.DeviceAllocationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeviceAllocationSpec_To_config_DeviceAllocationSpec(a.(*DeviceAllocationSpec), b.(*config.DeviceAllocationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DeviceAllocationSpec)(nil), (*DeviceAllocationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DeviceAllocationSpec_To_v1_DeviceAllocationSpec(a.(*config.DeviceAllocationSpec), b.(*DeviceAllocationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeviceCPUBalance)(nil), (*config.DeviceCPUBalance)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeviceCPUBalance_To_config_DeviceCPUBalance(a.(*DeviceCPUBalance), b.(*config.DeviceCPUBalance), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DeviceCPUBalance)(nil), (*DeviceCPUBalance)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DeviceCPUBalance_To_v1_DeviceCPUBalance(a.(*config.DeviceCPUBalance), b.(*DeviceCPUBalance), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadVariationRiskBalancingArgs)(nil), (*config.LoadVariationRiskBalancingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(a.(*LoadVariationRiskBalancingArgs), b.(*config.LoadVariationRiskBalancingArgs), scope)
 	}); err != nil {
@@ -80,6 +120,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NUMASpanSpec)(nil), (*config.NUMASpanSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NUMASpanSpec_To_config_NUMASpanSpec(a.(*NUMASpanSpec), b.(*config.NUMASpanSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NUMASpanSpec)(nil), (*NUMASpanSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NUMASpanSpec_To_v1_NUMASpanSpec(a.(*config.NUMASpanSpec), b.(*NUMASpanSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NetworkOverheadArgs)(nil), (*config.NetworkOverheadArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_NetworkOverheadArgs_To_config_NetworkOverheadArgs(a.(*NetworkOverheadArgs), b.(*config.NetworkOverheadArgs), scope)
 	}); err != nil {
@@ -90,6 +140,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeQuarantine)(nil), (*config.NodeQuarantine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NodeQuarantine_To_config_NodeQuarantine(a.(*NodeQuarantine), b.(*config.NodeQuarantine), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodeQuarantine)(nil), (*NodeQuarantine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodeQuarantine_To_v1_NodeQuarantine(a.(*config.NodeQuarantine), b.(*NodeQuarantine), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeResourceTopologyCache)(nil), (*config.NodeResourceTopologyCache)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_NodeResourceTopologyCache_To_config_NodeResourceTopologyCache(a.(*NodeResourceTopologyCache), b.(*config.NodeResourceTopologyCache), scope)
 	}); err != nil {
@@ -110,6 +170,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*OrphanCapacityAttribution)(nil), (*config.OrphanCapacityAttribution)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_OrphanCapacityAttribution_To_config_OrphanCapacityAttribution(a.(*OrphanCapacityAttribution), b.(*config.OrphanCapacityAttribution), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.OrphanCapacityAttribution)(nil), (*OrphanCapacityAttribution)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_OrphanCapacityAttribution_To_v1_OrphanCapacityAttribution(a.(*config.OrphanCapacityAttribution), b.(*OrphanCapacityAttribution), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*PreemptionTolerationArgs)(nil), (*config.PreemptionTolerationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_PreemptionTolerationArgs_To_config_PreemptionTolerationArgs(a.(*PreemptionTolerationArgs), b.(*config.PreemptionTolerationArgs), scope)
 	}); err != nil {
@@ -120,6 +190,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*QoSResourcePolicy)(nil), (*config.QoSResourcePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_QoSResourcePolicy_To_config_QoSResourcePolicy(a.(*QoSResourcePolicy), b.(*config.QoSResourcePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.QoSResourcePolicy)(nil), (*QoSResourcePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_QoSResourcePolicy_To_v1_QoSResourcePolicy(a.(*config.QoSResourcePolicy), b.(*QoSResourcePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ResourceAliasSpec)(nil), (*config.ResourceAliasSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ResourceAliasSpec_To_config_ResourceAliasSpec(a.(*ResourceAliasSpec), b.(*config.ResourceAliasSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ResourceAliasSpec)(nil), (*ResourceAliasSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ResourceAliasSpec_To_v1_ResourceAliasSpec(a.(*config.ResourceAliasSpec), b.(*ResourceAliasSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ResourceRoundingSpec)(nil), (*config.ResourceRoundingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ResourceRoundingSpec_To_config_ResourceRoundingSpec(a.(*ResourceRoundingSpec), b.(*config.ResourceRoundingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ResourceRoundingSpec)(nil), (*ResourceRoundingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ResourceRoundingSpec_To_v1_ResourceRoundingSpec(a.(*config.ResourceRoundingSpec), b.(*ResourceRoundingSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulabilityReport)(nil), (*config.SchedulabilityReport)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_SchedulabilityReport_To_config_SchedulabilityReport(a.(*SchedulabilityReport), b.(*config.SchedulabilityReport), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SchedulabilityReport)(nil), (*SchedulabilityReport)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SchedulabilityReport_To_v1_SchedulabilityReport(a.(*config.SchedulabilityReport), b.(*SchedulabilityReport), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ScoringStrategy)(nil), (*config.ScoringStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ScoringStrategy_To_config_ScoringStrategy(a.(*ScoringStrategy), b.(*config.ScoringStrategy), scope)
 	}); err != nil {
@@ -160,6 +270,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TopologyManagerOverlay)(nil), (*config.TopologyManagerOverlay)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_TopologyManagerOverlay_To_config_TopologyManagerOverlay(a.(*TopologyManagerOverlay), b.(*config.TopologyManagerOverlay), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TopologyManagerOverlay)(nil), (*TopologyManagerOverlay)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TopologyManagerOverlay_To_v1_TopologyManagerOverlay(a.(*config.TopologyManagerOverlay), b.(*TopologyManagerOverlay), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TrimaranSpec)(nil), (*config.TrimaranSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_TrimaranSpec_To_config_TrimaranSpec(a.(*TrimaranSpec), b.(*config.TrimaranSpec), scope)
 	}); err != nil {
@@ -213,6 +333,98 @@ func Convert_config_CoschedulingArgs_To_v1_CoschedulingArgs(in *config.Coschedul
 	return autoConvert_config_CoschedulingArgs_To_v1_CoschedulingArgs(in, out, s)
 }
 
+func autoConvert_v1_DecisionLog_To_config_DecisionLog(in *DecisionLog, out *config.DecisionLog, s conversion.Scope) error {
+	out.Size = in.Size
+	return nil
+}
+
+// Convert_v1_DecisionLog_To_config_DecisionLog is an autogenerated conversion function.
+func Convert_v1_DecisionLog_To_config_DecisionLog(in *DecisionLog, out *config.DecisionLog, s conversion.Scope) error {
+	return autoConvert_v1_DecisionLog_To_config_DecisionLog(in, out, s)
+}
+
+func autoConvert_config_DecisionLog_To_v1_DecisionLog(in *config.DecisionLog, out *DecisionLog, s conversion.Scope) error {
+	out.Size = in.Size
+	return nil
+}
+
+// Convert_config_DecisionLog_To_v1_DecisionLog is an autogenerated conversion function.
+func Convert_config_DecisionLog_To_v1_DecisionLog(in *config.DecisionLog, out *DecisionLog, s conversion.Scope) error {
+	return autoConvert_config_DecisionLog_To_v1_DecisionLog(in, out, s)
+}
+
+func autoConvert_v1_DecisionWebhook_To_config_DecisionWebhook(in *DecisionWebhook, out *config.DecisionWebhook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.TimeoutSeconds = in.TimeoutSeconds
+	out.FailurePolicy = config.DecisionWebhookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_v1_DecisionWebhook_To_config_DecisionWebhook is an autogenerated conversion function.
+func Convert_v1_DecisionWebhook_To_config_DecisionWebhook(in *DecisionWebhook, out *config.DecisionWebhook, s conversion.Scope) error {
+	return autoConvert_v1_DecisionWebhook_To_config_DecisionWebhook(in, out, s)
+}
+
+func autoConvert_config_DecisionWebhook_To_v1_DecisionWebhook(in *config.DecisionWebhook, out *DecisionWebhook, s conversion.Scope) error {
+	out.URL = in.URL
+	out.TimeoutSeconds = in.TimeoutSeconds
+	out.FailurePolicy = DecisionWebhookFailurePolicy(in.FailurePolicy)
+	return nil
+}
+
+// Convert_config_DecisionWebhook_To_v1_DecisionWebhook is an autogenerated conversion function.
+func Convert_config_DecisionWebhook_To_v1_DecisionWebhook(in *config.DecisionWebhook, out *DecisionWebhook, s conversion.Scope) error {
+	return autoConvert_config_DecisionWebhook_To_v1_DecisionWebhook(in, out, s)
+}
+
+func autoConvert_v1_DeviceAllocationSpec_To_config_DeviceAllocationSpec(in *DeviceAllocationSpec, out *config.DeviceAllocationSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Mode = config.DeviceAllocationMode(in.Mode)
+	out.SharingCap = in.SharingCap
+	return nil
+}
+
+// Convert_v1_DeviceAllocationSpec_To_config_DeviceAllocationSpec is an autogenerated conversion function.
+func Convert_v1_DeviceAllocationSpec_To_config_DeviceAllocationSpec(in *DeviceAllocationSpec, out *config.DeviceAllocationSpec, s conversion.Scope) error {
+	return autoConvert_v1_DeviceAllocationSpec_To_config_DeviceAllocationSpec(in, out, s)
+}
+
+func autoConvert_config_DeviceAllocationSpec_To_v1_DeviceAllocationSpec(in *config.DeviceAllocationSpec, out *DeviceAllocationSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Mode = DeviceAllocationMode(in.Mode)
+	out.SharingCap = in.SharingCap
+	return nil
+}
+
+// Convert_config_DeviceAllocationSpec_To_v1_DeviceAllocationSpec is an autogenerated conversion function.
+func Convert_config_DeviceAllocationSpec_To_v1_DeviceAllocationSpec(in *config.DeviceAllocationSpec, out *DeviceAllocationSpec, s conversion.Scope) error {
+	return autoConvert_config_DeviceAllocationSpec_To_v1_DeviceAllocationSpec(in, out, s)
+}
+
+func autoConvert_v1_DeviceCPUBalance_To_config_DeviceCPUBalance(in *DeviceCPUBalance, out *config.DeviceCPUBalance, s conversion.Scope) error {
+	out.Resource = in.Resource
+	out.CPUsPerDevice = in.CPUsPerDevice
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_v1_DeviceCPUBalance_To_config_DeviceCPUBalance is an autogenerated conversion function.
+func Convert_v1_DeviceCPUBalance_To_config_DeviceCPUBalance(in *DeviceCPUBalance, out *config.DeviceCPUBalance, s conversion.Scope) error {
+	return autoConvert_v1_DeviceCPUBalance_To_config_DeviceCPUBalance(in, out, s)
+}
+
+func autoConvert_config_DeviceCPUBalance_To_v1_DeviceCPUBalance(in *config.DeviceCPUBalance, out *DeviceCPUBalance, s conversion.Scope) error {
+	out.Resource = in.Resource
+	out.CPUsPerDevice = in.CPUsPerDevice
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_config_DeviceCPUBalance_To_v1_DeviceCPUBalance is an autogenerated conversion function.
+func Convert_config_DeviceCPUBalance_To_v1_DeviceCPUBalance(in *config.DeviceCPUBalance, out *DeviceCPUBalance, s conversion.Scope) error {
+	return autoConvert_config_DeviceCPUBalance_To_v1_DeviceCPUBalance(in, out, s)
+}
+
 func autoConvert_v1_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(in *LoadVariationRiskBalancingArgs, out *config.LoadVariationRiskBalancingArgs, s conversion.Scope) error {
 	if err := Convert_v1_TrimaranSpec_To_config_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
 		return err
@@ -319,6 +531,28 @@ func Convert_config_MetricProviderSpec_To_v1_MetricProviderSpec(in *config.Metri
 	return autoConvert_config_MetricProviderSpec_To_v1_MetricProviderSpec(in, out, s)
 }
 
+func autoConvert_v1_NUMASpanSpec_To_config_NUMASpanSpec(in *NUMASpanSpec, out *config.NUMASpanSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Mode = config.NUMASpanMode(in.Mode)
+	return nil
+}
+
+// Convert_v1_NUMASpanSpec_To_config_NUMASpanSpec is an autogenerated conversion function.
+func Convert_v1_NUMASpanSpec_To_config_NUMASpanSpec(in *NUMASpanSpec, out *config.NUMASpanSpec, s conversion.Scope) error {
+	return autoConvert_v1_NUMASpanSpec_To_config_NUMASpanSpec(in, out, s)
+}
+
+func autoConvert_config_NUMASpanSpec_To_v1_NUMASpanSpec(in *config.NUMASpanSpec, out *NUMASpanSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Mode = NUMASpanMode(in.Mode)
+	return nil
+}
+
+// Convert_config_NUMASpanSpec_To_v1_NUMASpanSpec is an autogenerated conversion function.
+func Convert_config_NUMASpanSpec_To_v1_NUMASpanSpec(in *config.NUMASpanSpec, out *NUMASpanSpec, s conversion.Scope) error {
+	return autoConvert_config_NUMASpanSpec_To_v1_NUMASpanSpec(in, out, s)
+}
+
 func autoConvert_v1_NetworkOverheadArgs_To_config_NetworkOverheadArgs(in *NetworkOverheadArgs, out *config.NetworkOverheadArgs, s conversion.Scope) error {
 	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
 	if err := metav1.Convert_Pointer_string_To_string(&in.WeightsName, &out.WeightsName, s); err != nil {
@@ -351,10 +585,33 @@ func Convert_config_NetworkOverheadArgs_To_v1_NetworkOverheadArgs(in *config.Net
 	return autoConvert_config_NetworkOverheadArgs_To_v1_NetworkOverheadArgs(in, out, s)
 }
 
+func autoConvert_v1_NodeQuarantine_To_config_NodeQuarantine(in *NodeQuarantine, out *config.NodeQuarantine, s conversion.Scope) error {
+	out.Threshold = in.Threshold
+	out.RecoveryThreshold = in.RecoveryThreshold
+	return nil
+}
+
+// Convert_v1_NodeQuarantine_To_config_NodeQuarantine is an autogenerated conversion function.
+func Convert_v1_NodeQuarantine_To_config_NodeQuarantine(in *NodeQuarantine, out *config.NodeQuarantine, s conversion.Scope) error {
+	return autoConvert_v1_NodeQuarantine_To_config_NodeQuarantine(in, out, s)
+}
+
+func autoConvert_config_NodeQuarantine_To_v1_NodeQuarantine(in *config.NodeQuarantine, out *NodeQuarantine, s conversion.Scope) error {
+	out.Threshold = in.Threshold
+	out.RecoveryThreshold = in.RecoveryThreshold
+	return nil
+}
+
+// Convert_config_NodeQuarantine_To_v1_NodeQuarantine is an autogenerated conversion function.
+func Convert_config_NodeQuarantine_To_v1_NodeQuarantine(in *config.NodeQuarantine, out *NodeQuarantine, s conversion.Scope) error {
+	return autoConvert_config_NodeQuarantine_To_v1_NodeQuarantine(in, out, s)
+}
+
 func autoConvert_v1_NodeResourceTopologyCache_To_config_NodeResourceTopologyCache(in *NodeResourceTopologyCache, out *config.NodeResourceTopologyCache, s conversion.Scope) error {
 	out.ForeignPodsDetect = (*config.ForeignPodsDetectMode)(unsafe.Pointer(in.ForeignPodsDetect))
 	out.ResyncMethod = (*config.CacheResyncMethod)(unsafe.Pointer(in.ResyncMethod))
 	out.InformerMode = (*config.CacheInformerMode)(unsafe.Pointer(in.InformerMode))
+	out.FullResyncPeriodSeconds = (*int64)(unsafe.Pointer(in.FullResyncPeriodSeconds))
 	return nil
 }
 
@@ -367,6 +624,7 @@ func autoConvert_config_NodeResourceTopologyCache_To_v1_NodeResourceTopologyCach
 	out.ForeignPodsDetect = (*ForeignPodsDetectMode)(unsafe.Pointer(in.ForeignPodsDetect))
 	out.ResyncMethod = (*CacheResyncMethod)(unsafe.Pointer(in.ResyncMethod))
 	out.InformerMode = (*CacheInformerMode)(unsafe.Pointer(in.InformerMode))
+	out.FullResyncPeriodSeconds = (*int64)(unsafe.Pointer(in.FullResyncPeriodSeconds))
 	return nil
 }
 
@@ -382,6 +640,61 @@ func autoConvert_v1_NodeResourceTopologyMatchArgs_To_config_NodeResourceTopology
 	}
 	out.DiscardReservedNodes = in.DiscardReservedNodes
 	out.Cache = (*config.NodeResourceTopologyCache)(unsafe.Pointer(in.Cache))
+	out.ResourceRounding = *(*[]config.ResourceRoundingSpec)(unsafe.Pointer(&in.ResourceRounding))
+	out.MemoryAlignAgainstLimits = in.MemoryAlignAgainstLimits
+	out.ContainerCPUExclusivity = in.ContainerCPUExclusivity
+	out.DeviceAvoidanceResources = *(*[]string)(unsafe.Pointer(&in.DeviceAvoidanceResources))
+	out.PCIeGroupAlignment = in.PCIeGroupAlignment
+	out.Quarantine = (*config.NodeQuarantine)(unsafe.Pointer(in.Quarantine))
+	out.NodeHeadroomWeight = in.NodeHeadroomWeight
+	out.TopologyManagerOverlay = (*config.TopologyManagerOverlay)(unsafe.Pointer(in.TopologyManagerOverlay))
+	out.DeviceAllocation = *(*[]config.DeviceAllocationSpec)(unsafe.Pointer(&in.DeviceAllocation))
+	out.PlacementWaste = in.PlacementWaste
+	out.StabilityWeight = in.StabilityWeight
+	out.StrictTopologyPolicies = in.StrictTopologyPolicies
+	out.DeviceCPUBalance = (*config.DeviceCPUBalance)(unsafe.Pointer(in.DeviceCPUBalance))
+	out.ResourceNameHints = in.ResourceNameHints
+	out.ContainerScopeForceSameNUMA = in.ContainerScopeForceSameNUMA
+	out.StickyNUMAWeight = in.StickyNUMAWeight
+	out.SchedulabilityReport = (*config.SchedulabilityReport)(unsafe.Pointer(in.SchedulabilityReport))
+	out.NUMAAffinityMemorySeconds = in.NUMAAffinityMemorySeconds
+	out.SocketFreenessWeight = in.SocketFreenessWeight
+	out.RestrictedAsSingleNUMA = in.RestrictedAsSingleNUMA
+	out.ExcludedResources = *(*[]string)(unsafe.Pointer(&in.ExcludedResources))
+	out.RequiredAlignmentResources = *(*[]string)(unsafe.Pointer(&in.RequiredAlignmentResources))
+	out.SidecarOverheadEstimate = *(*corev1.ResourceList)(unsafe.Pointer(&in.SidecarOverheadEstimate))
+	out.FractionalCPUShared = in.FractionalCPUShared
+	out.ResourceAliases = *(*[]config.ResourceAliasSpec)(unsafe.Pointer(&in.ResourceAliases))
+	out.NUMASpreadWeight = in.NUMASpreadWeight
+	out.AllocatableMismatchPercent = in.AllocatableMismatchPercent
+	out.NUMAPowerHintAttribute = in.NUMAPowerHintAttribute
+	out.OrphanCapacityAttribution = (*config.OrphanCapacityAttribution)(unsafe.Pointer(in.OrphanCapacityAttribution))
+	out.ChosenNUMAMetric = in.ChosenNUMAMetric
+	out.BurstFactorPercent = in.BurstFactorPercent
+	out.MaxCandidateNUMASets = in.MaxCandidateNUMASets
+	out.ExemptNamespaces = *(*[]string)(unsafe.Pointer(&in.ExemptNamespaces))
+	out.OwnerNUMALocalityWeight = in.OwnerNUMALocalityWeight
+	out.DecisionLog = (*config.DecisionLog)(unsafe.Pointer(in.DecisionLog))
+	out.ContainerScopeInitSameNUMA = in.ContainerScopeInitSameNUMA
+	out.DefaultPolicyWhenMissing = (*config.TopologyManagerOverlay)(unsafe.Pointer(in.DefaultPolicyWhenMissing))
+	out.PersistentInvalidTopologyThreshold = in.PersistentInvalidTopologyThreshold
+	out.DeviceHintConflictWeight = in.DeviceHintConflictWeight
+	out.NUMASpan = *(*[]config.NUMASpanSpec)(unsafe.Pointer(&in.NUMASpan))
+	out.LogTopologyAge = in.LogTopologyAge
+	out.BestEffortNodeLevelDevices = in.BestEffortNodeLevelDevices
+	out.CapacityNormalizedScoring = in.CapacityNormalizedScoring
+	out.FailFastWithoutEnforcingNodes = in.FailFastWithoutEnforcingNodes
+	out.GatedNUMAPreferenceWeight = in.GatedNUMAPreferenceWeight
+	out.NUMALocalEphemeralStorage = in.NUMALocalEphemeralStorage
+	out.QoSResourcePolicy = (*config.QoSResourcePolicy)(unsafe.Pointer(in.QoSResourcePolicy))
+	out.SameSocketResourcesWeight = in.SameSocketResourcesWeight
+	out.EnabledPolicies = *(*[]string)(unsafe.Pointer(&in.EnabledPolicies))
+	out.NUMAOccupancyFromPods = in.NUMAOccupancyFromPods
+	out.DecisionWebhook = (*config.DecisionWebhook)(unsafe.Pointer(in.DecisionWebhook))
+	out.DegradedNUMAWeight = in.DegradedNUMAWeight
+	out.DegradedNUMAThreshold = in.DegradedNUMAThreshold
+	out.StaticCPUManagerPolicy = in.StaticCPUManagerPolicy
+	out.GuaranteedPodsRejectedOnNonePolicy = in.GuaranteedPodsRejectedOnNonePolicy
 	return nil
 }
 
@@ -392,6 +705,61 @@ func autoConvert_config_NodeResourceTopologyMatchArgs_To_v1_NodeResourceTopology
 	}
 	out.DiscardReservedNodes = in.DiscardReservedNodes
 	out.Cache = (*NodeResourceTopologyCache)(unsafe.Pointer(in.Cache))
+	out.ResourceRounding = *(*[]ResourceRoundingSpec)(unsafe.Pointer(&in.ResourceRounding))
+	out.MemoryAlignAgainstLimits = in.MemoryAlignAgainstLimits
+	out.ContainerCPUExclusivity = in.ContainerCPUExclusivity
+	out.DeviceAvoidanceResources = *(*[]string)(unsafe.Pointer(&in.DeviceAvoidanceResources))
+	out.PCIeGroupAlignment = in.PCIeGroupAlignment
+	out.Quarantine = (*NodeQuarantine)(unsafe.Pointer(in.Quarantine))
+	out.NodeHeadroomWeight = in.NodeHeadroomWeight
+	out.TopologyManagerOverlay = (*TopologyManagerOverlay)(unsafe.Pointer(in.TopologyManagerOverlay))
+	out.DeviceAllocation = *(*[]DeviceAllocationSpec)(unsafe.Pointer(&in.DeviceAllocation))
+	out.PlacementWaste = in.PlacementWaste
+	out.StabilityWeight = in.StabilityWeight
+	out.StrictTopologyPolicies = in.StrictTopologyPolicies
+	out.DeviceCPUBalance = (*DeviceCPUBalance)(unsafe.Pointer(in.DeviceCPUBalance))
+	out.ResourceNameHints = in.ResourceNameHints
+	out.ContainerScopeForceSameNUMA = in.ContainerScopeForceSameNUMA
+	out.StickyNUMAWeight = in.StickyNUMAWeight
+	out.SchedulabilityReport = (*SchedulabilityReport)(unsafe.Pointer(in.SchedulabilityReport))
+	out.NUMAAffinityMemorySeconds = in.NUMAAffinityMemorySeconds
+	out.SocketFreenessWeight = in.SocketFreenessWeight
+	out.RestrictedAsSingleNUMA = in.RestrictedAsSingleNUMA
+	out.ExcludedResources = *(*[]string)(unsafe.Pointer(&in.ExcludedResources))
+	out.RequiredAlignmentResources = *(*[]string)(unsafe.Pointer(&in.RequiredAlignmentResources))
+	out.SidecarOverheadEstimate = *(*corev1.ResourceList)(unsafe.Pointer(&in.SidecarOverheadEstimate))
+	out.FractionalCPUShared = in.FractionalCPUShared
+	out.ResourceAliases = *(*[]ResourceAliasSpec)(unsafe.Pointer(&in.ResourceAliases))
+	out.NUMASpreadWeight = in.NUMASpreadWeight
+	out.AllocatableMismatchPercent = in.AllocatableMismatchPercent
+	out.NUMAPowerHintAttribute = in.NUMAPowerHintAttribute
+	out.OrphanCapacityAttribution = (*OrphanCapacityAttribution)(unsafe.Pointer(in.OrphanCapacityAttribution))
+	out.ChosenNUMAMetric = in.ChosenNUMAMetric
+	out.BurstFactorPercent = in.BurstFactorPercent
+	out.MaxCandidateNUMASets = in.MaxCandidateNUMASets
+	out.ExemptNamespaces = *(*[]string)(unsafe.Pointer(&in.ExemptNamespaces))
+	out.OwnerNUMALocalityWeight = in.OwnerNUMALocalityWeight
+	out.DecisionLog = (*DecisionLog)(unsafe.Pointer(in.DecisionLog))
+	out.ContainerScopeInitSameNUMA = in.ContainerScopeInitSameNUMA
+	out.DefaultPolicyWhenMissing = (*TopologyManagerOverlay)(unsafe.Pointer(in.DefaultPolicyWhenMissing))
+	out.PersistentInvalidTopologyThreshold = in.PersistentInvalidTopologyThreshold
+	out.DeviceHintConflictWeight = in.DeviceHintConflictWeight
+	out.NUMASpan = *(*[]NUMASpanSpec)(unsafe.Pointer(&in.NUMASpan))
+	out.LogTopologyAge = in.LogTopologyAge
+	out.BestEffortNodeLevelDevices = in.BestEffortNodeLevelDevices
+	out.CapacityNormalizedScoring = in.CapacityNormalizedScoring
+	out.FailFastWithoutEnforcingNodes = in.FailFastWithoutEnforcingNodes
+	out.GatedNUMAPreferenceWeight = in.GatedNUMAPreferenceWeight
+	out.NUMALocalEphemeralStorage = in.NUMALocalEphemeralStorage
+	out.QoSResourcePolicy = (*QoSResourcePolicy)(unsafe.Pointer(in.QoSResourcePolicy))
+	out.SameSocketResourcesWeight = in.SameSocketResourcesWeight
+	out.EnabledPolicies = *(*[]string)(unsafe.Pointer(&in.EnabledPolicies))
+	out.NUMAOccupancyFromPods = in.NUMAOccupancyFromPods
+	out.DecisionWebhook = (*DecisionWebhook)(unsafe.Pointer(in.DecisionWebhook))
+	out.DegradedNUMAWeight = in.DegradedNUMAWeight
+	out.DegradedNUMAThreshold = in.DegradedNUMAThreshold
+	out.StaticCPUManagerPolicy = in.StaticCPUManagerPolicy
+	out.GuaranteedPodsRejectedOnNonePolicy = in.GuaranteedPodsRejectedOnNonePolicy
 	return nil
 }
 
@@ -417,6 +785,28 @@ func Convert_config_NodeResourcesAllocatableArgs_To_v1_NodeResourcesAllocatableA
 	return autoConvert_config_NodeResourcesAllocatableArgs_To_v1_NodeResourcesAllocatableArgs(in, out, s)
 }
 
+func autoConvert_v1_OrphanCapacityAttribution_To_config_OrphanCapacityAttribution(in *OrphanCapacityAttribution, out *config.OrphanCapacityAttribution, s conversion.Scope) error {
+	out.Mode = config.OrphanCapacityMode(in.Mode)
+	out.NUMANode = in.NUMANode
+	return nil
+}
+
+// Convert_v1_OrphanCapacityAttribution_To_config_OrphanCapacityAttribution is an autogenerated conversion function.
+func Convert_v1_OrphanCapacityAttribution_To_config_OrphanCapacityAttribution(in *OrphanCapacityAttribution, out *config.OrphanCapacityAttribution, s conversion.Scope) error {
+	return autoConvert_v1_OrphanCapacityAttribution_To_config_OrphanCapacityAttribution(in, out, s)
+}
+
+func autoConvert_config_OrphanCapacityAttribution_To_v1_OrphanCapacityAttribution(in *config.OrphanCapacityAttribution, out *OrphanCapacityAttribution, s conversion.Scope) error {
+	out.Mode = OrphanCapacityMode(in.Mode)
+	out.NUMANode = in.NUMANode
+	return nil
+}
+
+// Convert_config_OrphanCapacityAttribution_To_v1_OrphanCapacityAttribution is an autogenerated conversion function.
+func Convert_config_OrphanCapacityAttribution_To_v1_OrphanCapacityAttribution(in *config.OrphanCapacityAttribution, out *OrphanCapacityAttribution, s conversion.Scope) error {
+	return autoConvert_config_OrphanCapacityAttribution_To_v1_OrphanCapacityAttribution(in, out, s)
+}
+
 func autoConvert_v1_PreemptionTolerationArgs_To_config_PreemptionTolerationArgs(in *PreemptionTolerationArgs, out *config.PreemptionTolerationArgs, s conversion.Scope) error {
 	if err := metav1.Convert_Pointer_int32_To_int32(&in.MinCandidateNodesPercentage, &out.MinCandidateNodesPercentage, s); err != nil {
 		return err
@@ -447,6 +837,92 @@ func Convert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in *
 	return autoConvert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in, out, s)
 }
 
+func autoConvert_v1_QoSResourcePolicy_To_config_QoSResourcePolicy(in *QoSResourcePolicy, out *config.QoSResourcePolicy, s conversion.Scope) error {
+	out.BurstableEnforcedResources = *(*[]string)(unsafe.Pointer(&in.BurstableEnforcedResources))
+	return nil
+}
+
+// Convert_v1_QoSResourcePolicy_To_config_QoSResourcePolicy is an autogenerated conversion function.
+func Convert_v1_QoSResourcePolicy_To_config_QoSResourcePolicy(in *QoSResourcePolicy, out *config.QoSResourcePolicy, s conversion.Scope) error {
+	return autoConvert_v1_QoSResourcePolicy_To_config_QoSResourcePolicy(in, out, s)
+}
+
+func autoConvert_config_QoSResourcePolicy_To_v1_QoSResourcePolicy(in *config.QoSResourcePolicy, out *QoSResourcePolicy, s conversion.Scope) error {
+	out.BurstableEnforcedResources = *(*[]string)(unsafe.Pointer(&in.BurstableEnforcedResources))
+	return nil
+}
+
+// Convert_config_QoSResourcePolicy_To_v1_QoSResourcePolicy is an autogenerated conversion function.
+func Convert_config_QoSResourcePolicy_To_v1_QoSResourcePolicy(in *config.QoSResourcePolicy, out *QoSResourcePolicy, s conversion.Scope) error {
+	return autoConvert_config_QoSResourcePolicy_To_v1_QoSResourcePolicy(in, out, s)
+}
+
+func autoConvert_v1_ResourceAliasSpec_To_config_ResourceAliasSpec(in *ResourceAliasSpec, out *config.ResourceAliasSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Alias = in.Alias
+	return nil
+}
+
+// Convert_v1_ResourceAliasSpec_To_config_ResourceAliasSpec is an autogenerated conversion function.
+func Convert_v1_ResourceAliasSpec_To_config_ResourceAliasSpec(in *ResourceAliasSpec, out *config.ResourceAliasSpec, s conversion.Scope) error {
+	return autoConvert_v1_ResourceAliasSpec_To_config_ResourceAliasSpec(in, out, s)
+}
+
+func autoConvert_config_ResourceAliasSpec_To_v1_ResourceAliasSpec(in *config.ResourceAliasSpec, out *ResourceAliasSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Alias = in.Alias
+	return nil
+}
+
+// Convert_config_ResourceAliasSpec_To_v1_ResourceAliasSpec is an autogenerated conversion function.
+func Convert_config_ResourceAliasSpec_To_v1_ResourceAliasSpec(in *config.ResourceAliasSpec, out *ResourceAliasSpec, s conversion.Scope) error {
+	return autoConvert_config_ResourceAliasSpec_To_v1_ResourceAliasSpec(in, out, s)
+}
+
+func autoConvert_v1_ResourceRoundingSpec_To_config_ResourceRoundingSpec(in *ResourceRoundingSpec, out *config.ResourceRoundingSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Multiple = in.Multiple
+	return nil
+}
+
+// Convert_v1_ResourceRoundingSpec_To_config_ResourceRoundingSpec is an autogenerated conversion function.
+func Convert_v1_ResourceRoundingSpec_To_config_ResourceRoundingSpec(in *ResourceRoundingSpec, out *config.ResourceRoundingSpec, s conversion.Scope) error {
+	return autoConvert_v1_ResourceRoundingSpec_To_config_ResourceRoundingSpec(in, out, s)
+}
+
+func autoConvert_config_ResourceRoundingSpec_To_v1_ResourceRoundingSpec(in *config.ResourceRoundingSpec, out *ResourceRoundingSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Multiple = in.Multiple
+	return nil
+}
+
+// Convert_config_ResourceRoundingSpec_To_v1_ResourceRoundingSpec is an autogenerated conversion function.
+func Convert_config_ResourceRoundingSpec_To_v1_ResourceRoundingSpec(in *config.ResourceRoundingSpec, out *ResourceRoundingSpec, s conversion.Scope) error {
+	return autoConvert_config_ResourceRoundingSpec_To_v1_ResourceRoundingSpec(in, out, s)
+}
+
+func autoConvert_v1_SchedulabilityReport_To_config_SchedulabilityReport(in *SchedulabilityReport, out *config.SchedulabilityReport, s conversion.Scope) error {
+	out.PeriodSeconds = in.PeriodSeconds
+	out.ReferencePodRequests = *(*corev1.ResourceList)(unsafe.Pointer(&in.ReferencePodRequests))
+	return nil
+}
+
+// Convert_v1_SchedulabilityReport_To_config_SchedulabilityReport is an autogenerated conversion function.
+func Convert_v1_SchedulabilityReport_To_config_SchedulabilityReport(in *SchedulabilityReport, out *config.SchedulabilityReport, s conversion.Scope) error {
+	return autoConvert_v1_SchedulabilityReport_To_config_SchedulabilityReport(in, out, s)
+}
+
+func autoConvert_config_SchedulabilityReport_To_v1_SchedulabilityReport(in *config.SchedulabilityReport, out *SchedulabilityReport, s conversion.Scope) error {
+	out.PeriodSeconds = in.PeriodSeconds
+	out.ReferencePodRequests = *(*corev1.ResourceList)(unsafe.Pointer(&in.ReferencePodRequests))
+	return nil
+}
+
+// Convert_config_SchedulabilityReport_To_v1_SchedulabilityReport is an autogenerated conversion function.
+func Convert_config_SchedulabilityReport_To_v1_SchedulabilityReport(in *config.SchedulabilityReport, out *SchedulabilityReport, s conversion.Scope) error {
+	return autoConvert_config_SchedulabilityReport_To_v1_SchedulabilityReport(in, out, s)
+}
+
 func autoConvert_v1_ScoringStrategy_To_config_ScoringStrategy(in *ScoringStrategy, out *config.ScoringStrategy, s conversion.Scope) error {
 	out.Type = config.ScoringStrategyType(in.Type)
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
@@ -557,6 +1033,28 @@ func Convert_config_TopologicalSortArgs_To_v1_TopologicalSortArgs(in *config.Top
 	return autoConvert_config_TopologicalSortArgs_To_v1_TopologicalSortArgs(in, out, s)
 }
 
+func autoConvert_v1_TopologyManagerOverlay_To_config_TopologyManagerOverlay(in *TopologyManagerOverlay, out *config.TopologyManagerOverlay, s conversion.Scope) error {
+	out.Policy = in.Policy
+	out.Scope = in.Scope
+	return nil
+}
+
+// Convert_v1_TopologyManagerOverlay_To_config_TopologyManagerOverlay is an autogenerated conversion function.
+func Convert_v1_TopologyManagerOverlay_To_config_TopologyManagerOverlay(in *TopologyManagerOverlay, out *config.TopologyManagerOverlay, s conversion.Scope) error {
+	return autoConvert_v1_TopologyManagerOverlay_To_config_TopologyManagerOverlay(in, out, s)
+}
+
+func autoConvert_config_TopologyManagerOverlay_To_v1_TopologyManagerOverlay(in *config.TopologyManagerOverlay, out *TopologyManagerOverlay, s conversion.Scope) error {
+	out.Policy = in.Policy
+	out.Scope = in.Scope
+	return nil
+}
+
+// Convert_config_TopologyManagerOverlay_To_v1_TopologyManagerOverlay is an autogenerated conversion function.
+func Convert_config_TopologyManagerOverlay_To_v1_TopologyManagerOverlay(in *config.TopologyManagerOverlay, out *TopologyManagerOverlay, s conversion.Scope) error {
+	return autoConvert_config_TopologyManagerOverlay_To_v1_TopologyManagerOverlay(in, out, s)
+}
+
 func autoConvert_v1_TrimaranSpec_To_config_TrimaranSpec(in *TrimaranSpec, out *config.TrimaranSpec, s conversion.Scope) error {
 	if err := Convert_v1_MetricProviderSpec_To_config_MetricProviderSpec(&in.MetricProvider, &out.MetricProvider, s); err != nil {
 		return err
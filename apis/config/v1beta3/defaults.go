@@ -91,6 +91,12 @@ var (
 
 	defaultInformerMode = CacheInformerDedicated
 
+	defaultPersistentInvalidTopologyThreshold int64 = 30
+
+	defaultDecisionWebhookTimeoutSeconds int64 = 10
+
+	defaultDecisionWebhookFailurePolicy = DecisionWebhookIgnore
+
 	// Defaults for NetworkOverhead
 	// DefaultWeightsName contains the default costs to be used by networkAware plugins
 	DefaultWeightsName = "UserDefined"
@@ -211,6 +217,19 @@ func SetDefaults_NodeResourceTopologyMatchArgs(obj *NodeResourceTopologyMatchArg
 	if obj.Cache.InformerMode == nil {
 		obj.Cache.InformerMode = &defaultInformerMode
 	}
+
+	if obj.PersistentInvalidTopologyThreshold == 0 {
+		obj.PersistentInvalidTopologyThreshold = defaultPersistentInvalidTopologyThreshold
+	}
+
+	if obj.DecisionWebhook != nil {
+		if obj.DecisionWebhook.TimeoutSeconds == 0 {
+			obj.DecisionWebhook.TimeoutSeconds = defaultDecisionWebhookTimeoutSeconds
+		}
+		if obj.DecisionWebhook.FailurePolicy == "" {
+			obj.DecisionWebhook.FailurePolicy = defaultDecisionWebhookFailurePolicy
+		}
+	}
 }
 
 // SetDefaults_PreemptionTolerationArgs reuses SetDefaults_DefaultPreemptionArgs
@@ -207,6 +207,32 @@ func TestSchedulingDefaults(t *testing.T) {
 					ResyncMethod:      &defaultResyncMethod,
 					InformerMode:      &defaultInformerMode,
 				},
+				PersistentInvalidTopologyThreshold: 30,
+			},
+		},
+		{
+			name: "empty decision webhook NodeResourceTopologyMatchArgs",
+			config: &NodeResourceTopologyMatchArgs{
+				DecisionWebhook: &DecisionWebhook{
+					URL: "https://numa-policy.example.com/review",
+				},
+			},
+			expect: &NodeResourceTopologyMatchArgs{
+				ScoringStrategy: &ScoringStrategy{
+					Type:      LeastAllocated,
+					Resources: defaultResourceSpec,
+				},
+				Cache: &NodeResourceTopologyCache{
+					ForeignPodsDetect: &defaultForeignPodsDetect,
+					ResyncMethod:      &defaultResyncMethod,
+					InformerMode:      &defaultInformerMode,
+				},
+				PersistentInvalidTopologyThreshold: 30,
+				DecisionWebhook: &DecisionWebhook{
+					URL:            "https://numa-policy.example.com/review",
+					TimeoutSeconds: 10,
+					FailurePolicy:  DecisionWebhookIgnore,
+				},
 			},
 		},
 		{
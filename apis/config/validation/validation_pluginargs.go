@@ -17,8 +17,14 @@ limitations under the License.
 package validation
 
 import (
+	"net/url"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
 
 	"sigs.k8s.io/scheduler-plugins/apis/config"
 )
@@ -28,6 +34,19 @@ var validScoringStrategy = sets.NewString(
 	string(config.BalancedAllocation),
 	string(config.LeastAllocated),
 	string(config.LeastNUMANodes),
+	string(config.InterPodNUMAAffinity),
+)
+
+var validTopologyManagerPolicy = sets.NewString(
+	kubeletconfig.NoneTopologyManagerPolicy,
+	kubeletconfig.BestEffortTopologyManagerPolicy,
+	kubeletconfig.RestrictedTopologyManagerPolicy,
+	kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+)
+
+var validTopologyManagerScope = sets.NewString(
+	kubeletconfig.ContainerTopologyManagerScope,
+	kubeletconfig.PodTopologyManagerScope,
 )
 
 func ValidateNodeResourceTopologyMatchArgs(path *field.Path, args *config.NodeResourceTopologyMatchArgs) error {
@@ -36,6 +55,78 @@ func ValidateNodeResourceTopologyMatchArgs(path *field.Path, args *config.NodeRe
 	if err := validateScoringStrategyType(args.ScoringStrategy.Type, scoringStrategyTypePath); err != nil {
 		allErrs = append(allErrs, err)
 	}
+	allErrs = append(allErrs, validateResourceRounding(args.ResourceRounding, path.Child("resourceRounding"))...)
+	allErrs = append(allErrs, validateResourceAliases(args.ResourceAliases, path.Child("resourceAliases"))...)
+	allErrs = append(allErrs, validateResourceNames(args.DeviceAvoidanceResources, path.Child("deviceAvoidanceResources"))...)
+	allErrs = append(allErrs, validateResourceNames(args.ExcludedResources, path.Child("excludedResources"))...)
+	allErrs = append(allErrs, validateResourceNames(args.RequiredAlignmentResources, path.Child("requiredAlignmentResources"))...)
+	allErrs = append(allErrs, validateNoOverlap(args.ExcludedResources, args.RequiredAlignmentResources, path.Child("requiredAlignmentResources"))...)
+	allErrs = append(allErrs, validateNodeQuarantine(args.Quarantine, path.Child("quarantine"))...)
+	allErrs = append(allErrs, validateTopologyManagerOverlay(args.TopologyManagerOverlay, path.Child("topologyManagerOverlay"))...)
+	allErrs = append(allErrs, validateDeviceAllocation(args.DeviceAllocation, path.Child("deviceAllocation"))...)
+	allErrs = append(allErrs, validateDeviceCPUBalance(args.DeviceCPUBalance, path.Child("deviceCPUBalance"))...)
+	allErrs = append(allErrs, validateSchedulabilityReport(args.SchedulabilityReport, path.Child("schedulabilityReport"))...)
+	allErrs = append(allErrs, validateOrphanCapacityAttribution(args.OrphanCapacityAttribution, path.Child("orphanCapacityAttribution"))...)
+	allErrs = append(allErrs, validateNamespaceNames(args.ExemptNamespaces, path.Child("exemptNamespaces"))...)
+	allErrs = append(allErrs, validateDecisionLog(args.DecisionLog, path.Child("decisionLog"))...)
+	allErrs = append(allErrs, validateDecisionWebhook(args.DecisionWebhook, path.Child("decisionWebhook"))...)
+	allErrs = append(allErrs, validateTopologyManagerOverlay(args.DefaultPolicyWhenMissing, path.Child("defaultPolicyWhenMissing"))...)
+	allErrs = append(allErrs, validateNUMASpan(args.NUMASpan, path.Child("numaSpan"))...)
+	allErrs = append(allErrs, validateQoSResourcePolicy(args.QoSResourcePolicy, path.Child("qosResourcePolicy"))...)
+	allErrs = append(allErrs, validateEnabledPolicies(args.EnabledPolicies, path.Child("enabledPolicies"))...)
+	for resName, quantity := range args.SidecarOverheadEstimate {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("sidecarOverheadEstimate").Key(string(resName)), quantity.String(), "must be greater than or equal to zero"))
+		}
+	}
+	if args.NodeHeadroomWeight < 0 || args.NodeHeadroomWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("nodeHeadroomWeight"), args.NodeHeadroomWeight, "must be between 0 and 100"))
+	}
+	if args.StabilityWeight < 0 || args.StabilityWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("stabilityWeight"), args.StabilityWeight, "must be between 0 and 100"))
+	}
+	if args.StickyNUMAWeight < 0 || args.StickyNUMAWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("stickyNUMAWeight"), args.StickyNUMAWeight, "must be between 0 and 100"))
+	}
+	if args.NUMAAffinityMemorySeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("numaAffinityMemorySeconds"), args.NUMAAffinityMemorySeconds, "must be greater than or equal to zero"))
+	}
+	if args.SocketFreenessWeight < 0 || args.SocketFreenessWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("socketFreenessWeight"), args.SocketFreenessWeight, "must be between 0 and 100"))
+	}
+	if args.NUMASpreadWeight < 0 || args.NUMASpreadWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("numaSpreadWeight"), args.NUMASpreadWeight, "must be between 0 and 100"))
+	}
+	if args.AllocatableMismatchPercent < 0 || args.AllocatableMismatchPercent > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("allocatableMismatchPercent"), args.AllocatableMismatchPercent, "must be between 0 and 100"))
+	}
+	if args.BurstFactorPercent != 0 && args.BurstFactorPercent < 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("burstFactorPercent"), args.BurstFactorPercent, "must be zero or greater than or equal to 100"))
+	}
+	if args.OwnerNUMALocalityWeight < 0 || args.OwnerNUMALocalityWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("ownerNUMALocalityWeight"), args.OwnerNUMALocalityWeight, "must be between 0 and 100"))
+	}
+	if args.PersistentInvalidTopologyThreshold < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("persistentInvalidTopologyThreshold"), args.PersistentInvalidTopologyThreshold, "must be greater than or equal to zero"))
+	}
+	if args.MaxCandidateNUMASets < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxCandidateNUMASets"), args.MaxCandidateNUMASets, "must be greater than or equal to zero"))
+	}
+	if args.DeviceHintConflictWeight < 0 || args.DeviceHintConflictWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("deviceHintConflictWeight"), args.DeviceHintConflictWeight, "must be between 0 and 100"))
+	}
+	if args.GatedNUMAPreferenceWeight < 0 || args.GatedNUMAPreferenceWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("gatedNUMAPreferenceWeight"), args.GatedNUMAPreferenceWeight, "must be between 0 and 100"))
+	}
+	if args.SameSocketResourcesWeight < 0 || args.SameSocketResourcesWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("sameSocketResourcesWeight"), args.SameSocketResourcesWeight, "must be between 0 and 100"))
+	}
+	if args.DegradedNUMAWeight < 0 || args.DegradedNUMAWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("degradedNUMAWeight"), args.DegradedNUMAWeight, "must be between 0 and 100"))
+	}
+	if args.DegradedNUMAThreshold < 0 || args.DegradedNUMAThreshold > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("degradedNUMAThreshold"), args.DegradedNUMAThreshold, "must be between 0 and 100"))
+	}
 
 	return allErrs.ToAggregate()
 }
@@ -46,3 +137,283 @@ func validateScoringStrategyType(scoringStrategy config.ScoringStrategyType, pat
 	}
 	return nil
 }
+
+func validateResourceRounding(specs []config.ResourceRoundingSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.NewString()
+	for i, spec := range specs {
+		if spec.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("name"), "resource name is required"))
+		} else if seen.Has(spec.Name) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("name"), spec.Name))
+		}
+		seen.Insert(spec.Name)
+		if spec.Multiple <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("multiple"), spec.Multiple, "must be greater than zero"))
+		}
+	}
+	return allErrs
+}
+
+// validateResourceAliases rejects the aliases listed twice, and the chained ones: a resource can't be both aliased and an alias.
+func validateResourceAliases(specs []config.ResourceAliasSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := sets.NewString()
+	for _, spec := range specs {
+		names.Insert(spec.Name)
+	}
+	seen := sets.NewString()
+	for i, spec := range specs {
+		if spec.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("name"), "resource name is required"))
+		}
+		if spec.Alias == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("alias"), "alias is required"))
+			continue
+		}
+		if seen.Has(spec.Alias) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("alias"), spec.Alias))
+		} else if names.Has(spec.Alias) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("alias"), spec.Alias, "resource cannot be both aliased and an alias"))
+		}
+		seen.Insert(spec.Alias)
+	}
+	return allErrs
+}
+
+func validateResourceNames(names []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.NewString()
+	for i, name := range names {
+		if name == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i), "resource name is required"))
+		} else if seen.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i), name))
+		}
+		seen.Insert(name)
+	}
+	return allErrs
+}
+
+func validateNamespaceNames(names []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.NewString()
+	for i, name := range names {
+		if name == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i), "namespace name is required"))
+		} else if seen.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i), name))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(name) {
+				allErrs = append(allErrs, field.Invalid(path.Index(i), name, msg))
+			}
+		}
+		seen.Insert(name)
+	}
+	return allErrs
+}
+
+// validateNoOverlap rejects the resource names of the second list also in the first one.
+func validateNoOverlap(first, second []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := sets.NewString(first...)
+	for i, name := range second {
+		if names.Has(name) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), name, "resource cannot be both excluded and required"))
+		}
+	}
+	return allErrs
+}
+
+func validateNodeQuarantine(quarantine *config.NodeQuarantine, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if quarantine == nil {
+		return allErrs
+	}
+	if quarantine.Threshold <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("threshold"), quarantine.Threshold, "must be greater than zero"))
+	}
+	if quarantine.RecoveryThreshold <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("recoveryThreshold"), quarantine.RecoveryThreshold, "must be greater than zero"))
+	}
+	return allErrs
+}
+
+func validateOrphanCapacityAttribution(attribution *config.OrphanCapacityAttribution, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if attribution == nil {
+		return allErrs
+	}
+	switch attribution.Mode {
+	case config.OrphanCapacityNUMANode:
+		if attribution.NUMANode < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("numaNode"), attribution.NUMANode, "must be greater than or equal to zero"))
+		}
+	case config.OrphanCapacityDistribute:
+		if attribution.NUMANode != 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("numaNode"), attribution.NUMANode, "must be zero in the Distribute mode"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("mode"), attribution.Mode, []string{string(config.OrphanCapacityNUMANode), string(config.OrphanCapacityDistribute)}))
+	}
+	return allErrs
+}
+
+func validateDeviceCPUBalance(balance *config.DeviceCPUBalance, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if balance == nil {
+		return allErrs
+	}
+	if balance.Resource == "" {
+		allErrs = append(allErrs, field.Required(path.Child("resource"), "resource name is required"))
+	}
+	if balance.CPUsPerDevice <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("cpusPerDevice"), balance.CPUsPerDevice, "must be greater than zero"))
+	}
+	if balance.Weight < 1 || balance.Weight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("weight"), balance.Weight, "must be between 1 and 100"))
+	}
+	return allErrs
+}
+
+func validateSchedulabilityReport(report *config.SchedulabilityReport, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if report == nil {
+		return allErrs
+	}
+	if report.PeriodSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("periodSeconds"), report.PeriodSeconds, "must be greater than zero"))
+	}
+	if len(report.ReferencePodRequests) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("referencePodRequests"), "reference pod requests are required"))
+	}
+	for resName, quantity := range report.ReferencePodRequests {
+		if quantity.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("referencePodRequests").Key(string(resName)), quantity.String(), "must be greater than zero"))
+		}
+	}
+	return allErrs
+}
+
+func validateDecisionLog(decisionLog *config.DecisionLog, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if decisionLog == nil {
+		return allErrs
+	}
+	if decisionLog.Size <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("size"), decisionLog.Size, "must be greater than zero"))
+	}
+	return allErrs
+}
+
+func validateDecisionWebhook(webhook *config.DecisionWebhook, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if webhook == nil {
+		return allErrs
+	}
+	if webhook.URL == "" {
+		allErrs = append(allErrs, field.Required(path.Child("url"), "url is required"))
+	} else if u, err := url.Parse(webhook.URL); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("url"), webhook.URL, err.Error()))
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("url"), webhook.URL, "must be an absolute http or https URL"))
+	}
+	if webhook.TimeoutSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeoutSeconds"), webhook.TimeoutSeconds, "must be greater than zero"))
+	}
+	switch webhook.FailurePolicy {
+	case config.DecisionWebhookIgnore, config.DecisionWebhookFail:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("failurePolicy"), webhook.FailurePolicy, []string{string(config.DecisionWebhookIgnore), string(config.DecisionWebhookFail)}))
+	}
+	return allErrs
+}
+
+func validateEnabledPolicies(policies []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.NewString()
+	for i, policy := range policies {
+		if !validTopologyManagerPolicy.Has(policy) {
+			allErrs = append(allErrs, field.NotSupported(path.Index(i), policy, validTopologyManagerPolicy.List()))
+		} else if seen.Has(policy) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i), policy))
+		}
+		seen.Insert(policy)
+	}
+	return allErrs
+}
+
+func validateQoSResourcePolicy(policy *config.QoSResourcePolicy, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if policy == nil {
+		return allErrs
+	}
+	path = path.Child("burstableEnforcedResources")
+	allErrs = append(allErrs, validateResourceNames(policy.BurstableEnforcedResources, path)...)
+	for i, name := range policy.BurstableEnforcedResources {
+		if name == "" || name == string(v1.ResourceCPU) || name == string(v1.ResourceMemory) || strings.HasPrefix(name, v1.ResourceHugePagesPrefix) {
+			continue
+		}
+		allErrs = append(allErrs, field.Invalid(path.Index(i), name, "must be cpu, memory or a hugepages resource"))
+	}
+	return allErrs
+}
+
+func validateTopologyManagerOverlay(overlay *config.TopologyManagerOverlay, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if overlay == nil {
+		return allErrs
+	}
+	if !validTopologyManagerPolicy.Has(overlay.Policy) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("policy"), overlay.Policy, validTopologyManagerPolicy.List()))
+	}
+	if !validTopologyManagerScope.Has(overlay.Scope) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("scope"), overlay.Scope, validTopologyManagerScope.List()))
+	}
+	return allErrs
+}
+
+func validateNUMASpan(specs []config.NUMASpanSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.NewString()
+	for i, spec := range specs {
+		if spec.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("name"), "resource name is required"))
+		} else if seen.Has(spec.Name) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("name"), spec.Name))
+		}
+		seen.Insert(spec.Name)
+		if spec.Mode != config.NUMASpanSingleNUMA && spec.Mode != config.NUMASpanMaySpan {
+			allErrs = append(allErrs, field.NotSupported(path.Index(i).Child("mode"), spec.Mode,
+				[]string{string(config.NUMASpanSingleNUMA), string(config.NUMASpanMaySpan)}))
+		}
+	}
+	return allErrs
+}
+
+func validateDeviceAllocation(specs []config.DeviceAllocationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := sets.NewString()
+	for i, spec := range specs {
+		if spec.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("name"), "resource name is required"))
+		} else if seen.Has(spec.Name) {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("name"), spec.Name))
+		}
+		seen.Insert(spec.Name)
+		switch spec.Mode {
+		case config.DeviceAllocationExclusive:
+			if spec.SharingCap != 0 {
+				allErrs = append(allErrs, field.Invalid(path.Index(i).Child("sharingCap"), spec.SharingCap, "must be zero for exclusive devices"))
+			}
+		case config.DeviceAllocationShared:
+			if spec.SharingCap < 0 {
+				allErrs = append(allErrs, field.Invalid(path.Index(i).Child("sharingCap"), spec.SharingCap, "must not be negative"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(path.Index(i).Child("mode"), spec.Mode,
+				[]string{string(config.DeviceAllocationExclusive), string(config.DeviceAllocationShared)}))
+		}
+	}
+	return allErrs
+}
@@ -21,6 +21,9 @@ import (
 	"strings"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	"sigs.k8s.io/scheduler-plugins/apis/config"
 )
 
@@ -47,6 +50,594 @@ func TestValidateNodeResourceTopologyMatchArgs(t *testing.T) {
 			},
 			expectedErr: fmt.Errorf("scoringStrategy.type: Invalid value:"),
 		},
+		{
+			description: "correct config, resource rounding",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ResourceRounding: []config.ResourceRoundingSpec{
+					{Name: "vendor.com/device", Multiple: 2},
+				},
+			},
+		},
+		{
+			description: "incorrect config, resource rounding with non-positive multiple",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ResourceRounding: []config.ResourceRoundingSpec{
+					{Name: "vendor.com/device", Multiple: 0},
+				},
+			},
+			expectedErr: fmt.Errorf("resourceRounding[0].multiple: Invalid value:"),
+		},
+		{
+			description: "incorrect config, resource rounding with duplicate resource",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ResourceRounding: []config.ResourceRoundingSpec{
+					{Name: "vendor.com/device", Multiple: 2},
+					{Name: "vendor.com/device", Multiple: 4},
+				},
+			},
+			expectedErr: fmt.Errorf("resourceRounding[1].name: Duplicate value:"),
+		},
+		{
+			description: "correct config, resource aliases",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ResourceAliases: []config.ResourceAliasSpec{
+					{Name: "vendor.com/dev", Alias: "vendor.com/dev-v1"},
+					{Name: "vendor.com/dev", Alias: "vendor.com/dev-v0"},
+				},
+			},
+		},
+		{
+			description: "incorrect config, resource alias without alias",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ResourceAliases: []config.ResourceAliasSpec{
+					{Name: "vendor.com/dev"},
+				},
+			},
+			expectedErr: fmt.Errorf("resourceAliases[0].alias: Required value:"),
+		},
+		{
+			description: "incorrect config, resource alias of two resources",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ResourceAliases: []config.ResourceAliasSpec{
+					{Name: "vendor.com/dev", Alias: "vendor.com/dev-v1"},
+					{Name: "vendor.com/other", Alias: "vendor.com/dev-v1"},
+				},
+			},
+			expectedErr: fmt.Errorf("resourceAliases[1].alias: Duplicate value:"),
+		},
+		{
+			description: "incorrect config, chained resource aliases",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ResourceAliases: []config.ResourceAliasSpec{
+					{Name: "vendor.com/dev", Alias: "vendor.com/dev-v1"},
+					{Name: "vendor.com/dev-v1", Alias: "vendor.com/dev-v0"},
+				},
+			},
+			expectedErr: fmt.Errorf("resourceAliases[0].alias: Invalid value:"),
+		},
+		{
+			description: "incorrect config, device avoidance with empty resource",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DeviceAvoidanceResources: []string{"nvidia.com/gpu", ""},
+			},
+			expectedErr: fmt.Errorf("deviceAvoidanceResources[1]: Required value:"),
+		},
+		{
+			description: "incorrect config, quarantine without recovery threshold",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				Quarantine: &config.NodeQuarantine{
+					Threshold: 3,
+				},
+			},
+			expectedErr: fmt.Errorf("quarantine.recoveryThreshold: Invalid value:"),
+		},
+		{
+			description: "incorrect config, node headroom weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				NodeHeadroomWeight: 101,
+			},
+			expectedErr: fmt.Errorf("nodeHeadroomWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, stability weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				StabilityWeight: -1,
+			},
+			expectedErr: fmt.Errorf("stabilityWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, sticky NUMA weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				StickyNUMAWeight: 101,
+			},
+			expectedErr: fmt.Errorf("stickyNUMAWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, negative NUMA affinity memory",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				NUMAAffinityMemorySeconds: -1,
+			},
+			expectedErr: fmt.Errorf("numaAffinityMemorySeconds: Invalid value:"),
+		},
+		{
+			description: "incorrect config, socket freeness weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				SocketFreenessWeight: 101,
+			},
+			expectedErr: fmt.Errorf("socketFreenessWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, NUMA spread weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				NUMASpreadWeight: -1,
+			},
+			expectedErr: fmt.Errorf("numaSpreadWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, allocatable mismatch percent out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				AllocatableMismatchPercent: -1,
+			},
+			expectedErr: fmt.Errorf("allocatableMismatchPercent: Invalid value:"),
+		},
+		{
+			description: "incorrect config, burst factor shrinking the requests",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				BurstFactorPercent: 80,
+			},
+			expectedErr: fmt.Errorf("burstFactorPercent: Invalid value:"),
+		},
+		{
+			description: "incorrect config, negative persistent invalid topology threshold",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				PersistentInvalidTopologyThreshold: -1,
+			},
+			expectedErr: fmt.Errorf("persistentInvalidTopologyThreshold: Invalid value:"),
+		},
+		{
+			description: "incorrect config, negative max candidate NUMA sets",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastNUMANodes,
+				},
+				MaxCandidateNUMASets: -1,
+			},
+			expectedErr: fmt.Errorf("maxCandidateNUMASets: Invalid value:"),
+		},
+		{
+			description: "incorrect config, duplicate exempt namespace",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ExemptNamespaces: []string{"kube-system", "kube-system"},
+			},
+			expectedErr: fmt.Errorf("exemptNamespaces[1]: Duplicate value:"),
+		},
+		{
+			description: "incorrect config, malformed exempt namespace",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ExemptNamespaces: []string{"Kube_System"},
+			},
+			expectedErr: fmt.Errorf("exemptNamespaces[0]: Invalid value:"),
+		},
+		{
+			description: "incorrect config, owner NUMA locality weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				OwnerNUMALocalityWeight: 101,
+			},
+			expectedErr: fmt.Errorf("ownerNUMALocalityWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, device hint conflict weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DeviceHintConflictWeight: -1,
+			},
+			expectedErr: fmt.Errorf("deviceHintConflictWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, gated NUMA preference weight out of range",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				GatedNUMAPreferenceWeight: 101,
+			},
+			expectedErr: fmt.Errorf("gatedNUMAPreferenceWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, negative sameSocketResourcesWeight",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				SameSocketResourcesWeight: -1,
+			},
+			expectedErr: fmt.Errorf("sameSocketResourcesWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, degradedNUMAWeight above 100",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DegradedNUMAWeight: 101,
+			},
+			expectedErr: fmt.Errorf("degradedNUMAWeight: Invalid value:"),
+		},
+		{
+			description: "incorrect config, negative degradedNUMAThreshold",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DegradedNUMAThreshold: -1,
+			},
+			expectedErr: fmt.Errorf("degradedNUMAThreshold: Invalid value:"),
+		},
+		{
+			description: "correct config, single-numa-node policy enabled only",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				EnabledPolicies: []string{"single-numa-node"},
+			},
+		},
+		{
+			description: "incorrect config, unknown enabled policy",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				EnabledPolicies: []string{"single-numa-node", "strict"},
+			},
+			expectedErr: fmt.Errorf("enabledPolicies[1]: Unsupported value:"),
+		},
+		{
+			description: "incorrect config, duplicate enabled policy",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				EnabledPolicies: []string{"restricted", "restricted"},
+			},
+			expectedErr: fmt.Errorf("enabledPolicies[1]: Duplicate value:"),
+		},
+		{
+			description: "correct config, memory and hugepages capacity enforced for burstable pods",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				QoSResourcePolicy: &config.QoSResourcePolicy{
+					BurstableEnforcedResources: []string{"memory", "hugepages-2Mi"},
+				},
+			},
+		},
+		{
+			description: "incorrect config, device capacity enforced for burstable pods",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				QoSResourcePolicy: &config.QoSResourcePolicy{
+					BurstableEnforcedResources: []string{"memory", "vendor.com/gpu"},
+				},
+			},
+			expectedErr: fmt.Errorf("qosResourcePolicy.burstableEnforcedResources[1]: Invalid value:"),
+		},
+		{
+			description: "incorrect config, duplicate excluded resource",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ExcludedResources: []string{"vendor.com/gpu", "vendor.com/gpu"},
+			},
+			expectedErr: fmt.Errorf("excludedResources[1]: Duplicate value:"),
+		},
+		{
+			description: "incorrect config, resource both excluded and required",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				ExcludedResources:          []string{"vendor.com/gpu"},
+				RequiredAlignmentResources: []string{"cpu", "vendor.com/gpu"},
+			},
+			expectedErr: fmt.Errorf("requiredAlignmentResources[1]: Invalid value:"),
+		},
+		{
+			description: "incorrect config, negative sidecar overhead estimate",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				SidecarOverheadEstimate: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("-100m"),
+				},
+			},
+			expectedErr: fmt.Errorf("sidecarOverheadEstimate[cpu]: Invalid value:"),
+		},
+		{
+			description: "incorrect config, schedulability report without period",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				SchedulabilityReport: &config.SchedulabilityReport{
+					ReferencePodRequests: v1.ResourceList{
+						v1.ResourceCPU: resource.MustParse("4"),
+					},
+				},
+			},
+			expectedErr: fmt.Errorf("schedulabilityReport.periodSeconds: Invalid value:"),
+		},
+		{
+			description: "incorrect config, schedulability report without reference pod",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				SchedulabilityReport: &config.SchedulabilityReport{
+					PeriodSeconds: 60,
+				},
+			},
+			expectedErr: fmt.Errorf("schedulabilityReport.referencePodRequests: Required value"),
+		},
+		{
+			description: "incorrect config, decision log without size",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DecisionLog: &config.DecisionLog{},
+			},
+			expectedErr: fmt.Errorf("decisionLog.size: Invalid value:"),
+		},
+		{
+			description: "correct config, decision webhook",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DecisionWebhook: &config.DecisionWebhook{
+					URL:            "https://policy.example.com/review",
+					TimeoutSeconds: 2,
+					FailurePolicy:  config.DecisionWebhookFail,
+				},
+			},
+		},
+		{
+			description: "incorrect config, decision webhook with relative url",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DecisionWebhook: &config.DecisionWebhook{
+					URL:            "/review",
+					TimeoutSeconds: 2,
+					FailurePolicy:  config.DecisionWebhookIgnore,
+				},
+			},
+			expectedErr: fmt.Errorf("decisionWebhook.url: Invalid value:"),
+		},
+		{
+			description: "incorrect config, decision webhook without timeout",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DecisionWebhook: &config.DecisionWebhook{
+					URL:           "https://policy.example.com/review",
+					FailurePolicy: config.DecisionWebhookFail,
+				},
+			},
+			expectedErr: fmt.Errorf("decisionWebhook.timeoutSeconds: Invalid value:"),
+		},
+		{
+			description: "incorrect config, decision webhook with unknown failure policy",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DecisionWebhook: &config.DecisionWebhook{
+					URL:            "https://policy.example.com/review",
+					TimeoutSeconds: 2,
+					FailurePolicy:  "Retry",
+				},
+			},
+			expectedErr: fmt.Errorf("decisionWebhook.failurePolicy: Unsupported value:"),
+		},
+		{
+			description: "incorrect config, device cpu balance without cpus per device",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DeviceCPUBalance: &config.DeviceCPUBalance{
+					Resource: "nvidia.com/gpu",
+					Weight:   50,
+				},
+			},
+			expectedErr: fmt.Errorf("deviceCPUBalance.cpusPerDevice: Invalid value:"),
+		},
+		{
+			description: "correct config, orphan capacity attributed to a NUMA node",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				OrphanCapacityAttribution: &config.OrphanCapacityAttribution{
+					Mode:     config.OrphanCapacityNUMANode,
+					NUMANode: 1,
+				},
+			},
+		},
+		{
+			description: "incorrect config, unknown orphan capacity mode",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				OrphanCapacityAttribution: &config.OrphanCapacityAttribution{
+					Mode: "Spread",
+				},
+			},
+			expectedErr: fmt.Errorf("orphanCapacityAttribution.mode: Unsupported value:"),
+		},
+		{
+			description: "incorrect config, orphan capacity attributed to a negative NUMA node",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				OrphanCapacityAttribution: &config.OrphanCapacityAttribution{
+					Mode:     config.OrphanCapacityNUMANode,
+					NUMANode: -1,
+				},
+			},
+			expectedErr: fmt.Errorf("orphanCapacityAttribution.numaNode: Invalid value:"),
+		},
+		{
+			description: "correct config, topology manager overlay",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				TopologyManagerOverlay: &config.TopologyManagerOverlay{
+					Policy: "single-numa-node",
+					Scope:  "pod",
+				},
+			},
+		},
+		{
+			description: "incorrect config, topology manager overlay with unknown policy",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				TopologyManagerOverlay: &config.TopologyManagerOverlay{
+					Policy: "SingleNUMANode",
+					Scope:  "pod",
+				},
+			},
+			expectedErr: fmt.Errorf("topologyManagerOverlay.policy: Unsupported value:"),
+		},
+		{
+			description: "incorrect config, default policy when missing with unknown scope",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DefaultPolicyWhenMissing: &config.TopologyManagerOverlay{
+					Policy: "single-numa-node",
+					Scope:  "node",
+				},
+			},
+			expectedErr: fmt.Errorf("defaultPolicyWhenMissing.scope: Unsupported value:"),
+		},
+		{
+			description: "incorrect config, device allocation with sharing cap for exclusive devices",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				DeviceAllocation: []config.DeviceAllocationSpec{
+					{Name: "vendor.com/vgpu", Mode: config.DeviceAllocationShared, SharingCap: 4},
+					{Name: "vendor.com/gpu", Mode: config.DeviceAllocationExclusive, SharingCap: 2},
+				},
+			},
+			expectedErr: fmt.Errorf("deviceAllocation[1].sharingCap: Invalid value:"),
+		},
+		{
+			description: "correct config, NUMA span",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				NUMASpan: []config.NUMASpanSpec{
+					{Name: "memory", Mode: config.NUMASpanMaySpan},
+					{Name: "nvidia.com/gpu", Mode: config.NUMASpanSingleNUMA},
+				},
+			},
+		},
+		{
+			description: "incorrect config, NUMA span with unsupported mode",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.LeastAllocated,
+				},
+				NUMASpan: []config.NUMASpanSpec{
+					{Name: "memory", Mode: "Interleaved"},
+				},
+			},
+			expectedErr: fmt.Errorf("numaSpan[0].mode: Unsupported value:"),
+		},
 	}
 
 	for _, testCase := range testCases {
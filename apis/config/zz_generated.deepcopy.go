@@ -52,6 +52,70 @@ func (in *CoschedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecisionLog) DeepCopyInto(out *DecisionLog) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecisionLog.
+func (in *DecisionLog) DeepCopy() *DecisionLog {
+	if in == nil {
+		return nil
+	}
+	out := new(DecisionLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecisionWebhook) DeepCopyInto(out *DecisionWebhook) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecisionWebhook.
+func (in *DecisionWebhook) DeepCopy() *DecisionWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(DecisionWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceAllocationSpec) DeepCopyInto(out *DeviceAllocationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceAllocationSpec.
+func (in *DeviceAllocationSpec) DeepCopy() *DeviceAllocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceAllocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceCPUBalance) DeepCopyInto(out *DeviceCPUBalance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCPUBalance.
+func (in *DeviceCPUBalance) DeepCopy() *DeviceCPUBalance {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceCPUBalance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadVariationRiskBalancingArgs) DeepCopyInto(out *LoadVariationRiskBalancingArgs) {
 	*out = *in
@@ -127,6 +191,22 @@ func (in *MetricProviderSpec) DeepCopy() *MetricProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NUMASpanSpec) DeepCopyInto(out *NUMASpanSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMASpanSpec.
+func (in *NUMASpanSpec) DeepCopy() *NUMASpanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NUMASpanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkOverheadArgs) DeepCopyInto(out *NetworkOverheadArgs) {
 	*out = *in
@@ -157,6 +237,22 @@ func (in *NetworkOverheadArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeQuarantine) DeepCopyInto(out *NodeQuarantine) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeQuarantine.
+func (in *NodeQuarantine) DeepCopy() *NodeQuarantine {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeQuarantine)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeResourceTopologyCache) DeepCopyInto(out *NodeResourceTopologyCache) {
 	*out = *in
@@ -175,6 +271,11 @@ func (in *NodeResourceTopologyCache) DeepCopyInto(out *NodeResourceTopologyCache
 		*out = new(CacheInformerMode)
 		**out = **in
 	}
+	if in.FullResyncPeriodSeconds != nil {
+		in, out := &in.FullResyncPeriodSeconds, &out.FullResyncPeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -198,6 +299,103 @@ func (in *NodeResourceTopologyMatchArgs) DeepCopyInto(out *NodeResourceTopologyM
 		*out = new(NodeResourceTopologyCache)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResourceRounding != nil {
+		in, out := &in.ResourceRounding, &out.ResourceRounding
+		*out = make([]ResourceRoundingSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeviceAvoidanceResources != nil {
+		in, out := &in.DeviceAvoidanceResources, &out.DeviceAvoidanceResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Quarantine != nil {
+		in, out := &in.Quarantine, &out.Quarantine
+		*out = new(NodeQuarantine)
+		**out = **in
+	}
+	if in.TopologyManagerOverlay != nil {
+		in, out := &in.TopologyManagerOverlay, &out.TopologyManagerOverlay
+		*out = new(TopologyManagerOverlay)
+		**out = **in
+	}
+	if in.DeviceAllocation != nil {
+		in, out := &in.DeviceAllocation, &out.DeviceAllocation
+		*out = make([]DeviceAllocationSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeviceCPUBalance != nil {
+		in, out := &in.DeviceCPUBalance, &out.DeviceCPUBalance
+		*out = new(DeviceCPUBalance)
+		**out = **in
+	}
+	if in.SchedulabilityReport != nil {
+		in, out := &in.SchedulabilityReport, &out.SchedulabilityReport
+		*out = new(SchedulabilityReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludedResources != nil {
+		in, out := &in.ExcludedResources, &out.ExcludedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredAlignmentResources != nil {
+		in, out := &in.RequiredAlignmentResources, &out.RequiredAlignmentResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SidecarOverheadEstimate != nil {
+		in, out := &in.SidecarOverheadEstimate, &out.SidecarOverheadEstimate
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.ResourceAliases != nil {
+		in, out := &in.ResourceAliases, &out.ResourceAliases
+		*out = make([]ResourceAliasSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrphanCapacityAttribution != nil {
+		in, out := &in.OrphanCapacityAttribution, &out.OrphanCapacityAttribution
+		*out = new(OrphanCapacityAttribution)
+		**out = **in
+	}
+	if in.ExemptNamespaces != nil {
+		in, out := &in.ExemptNamespaces, &out.ExemptNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DecisionLog != nil {
+		in, out := &in.DecisionLog, &out.DecisionLog
+		*out = new(DecisionLog)
+		**out = **in
+	}
+	if in.DefaultPolicyWhenMissing != nil {
+		in, out := &in.DefaultPolicyWhenMissing, &out.DefaultPolicyWhenMissing
+		*out = new(TopologyManagerOverlay)
+		**out = **in
+	}
+	if in.NUMASpan != nil {
+		in, out := &in.NUMASpan, &out.NUMASpan
+		*out = make([]NUMASpanSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.QoSResourcePolicy != nil {
+		in, out := &in.QoSResourcePolicy, &out.QoSResourcePolicy
+		*out = new(QoSResourcePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnabledPolicies != nil {
+		in, out := &in.EnabledPolicies, &out.EnabledPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DecisionWebhook != nil {
+		in, out := &in.DecisionWebhook, &out.DecisionWebhook
+		*out = new(DecisionWebhook)
+		**out = **in
+	}
 	return
 }
 
@@ -249,6 +447,22 @@ func (in *NodeResourcesAllocatableArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanCapacityAttribution) DeepCopyInto(out *OrphanCapacityAttribution) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanCapacityAttribution.
+func (in *OrphanCapacityAttribution) DeepCopy() *OrphanCapacityAttribution {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanCapacityAttribution)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreemptionTolerationArgs) DeepCopyInto(out *PreemptionTolerationArgs) {
 	*out = *in
@@ -274,6 +488,82 @@ func (in *PreemptionTolerationArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSResourcePolicy) DeepCopyInto(out *QoSResourcePolicy) {
+	*out = *in
+	if in.BurstableEnforcedResources != nil {
+		in, out := &in.BurstableEnforcedResources, &out.BurstableEnforcedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSResourcePolicy.
+func (in *QoSResourcePolicy) DeepCopy() *QoSResourcePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSResourcePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceAliasSpec) DeepCopyInto(out *ResourceAliasSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceAliasSpec.
+func (in *ResourceAliasSpec) DeepCopy() *ResourceAliasSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceAliasSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRoundingSpec) DeepCopyInto(out *ResourceRoundingSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRoundingSpec.
+func (in *ResourceRoundingSpec) DeepCopy() *ResourceRoundingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRoundingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulabilityReport) DeepCopyInto(out *SchedulabilityReport) {
+	*out = *in
+	if in.ReferencePodRequests != nil {
+		in, out := &in.ReferencePodRequests, &out.ReferencePodRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulabilityReport.
+func (in *SchedulabilityReport) DeepCopy() *SchedulabilityReport {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulabilityReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
 	*out = *in
@@ -383,6 +673,22 @@ func (in *TopologicalSortArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyManagerOverlay) DeepCopyInto(out *TopologyManagerOverlay) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyManagerOverlay.
+func (in *TopologyManagerOverlay) DeepCopy() *TopologyManagerOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyManagerOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrimaranSpec) DeepCopyInto(out *TrimaranSpec) {
 	*out = *in
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sync"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+)
+
+var allocatableMismatchTotal = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Subsystem:      "scheduler_plugins",
+		Name:           "noderesourcetopology_allocatable_mismatch_total",
+		Help:           "Number of NodeResourceTopology updates whose sum of the per-NUMA allocatable diverges from the node allocatable, by resource.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"resource"},
+)
+
+// allocatableConsistency compares the allocatable of the nodes with the sum of the allocatable of their NUMA zones,
+// which should roughly match: a significant divergence usually means a producer bug, or a resource the producer
+// doesn't map to the NUMA nodes. It only reports the divergences, the verdicts are not affected.
+// Each update, identified by its resource version, is checked once.
+type allocatableConsistency struct {
+	percent int64
+	lock    sync.Mutex
+	checked map[string]string
+}
+
+func newAllocatableConsistency(percent int64) *allocatableConsistency {
+	if percent <= 0 {
+		return nil
+	}
+	return &allocatableConsistency{
+		percent: percent,
+		checked: make(map[string]string),
+	}
+}
+
+// check compares the allocatable of the node with the NRT object of the node, if not compared already, and returns
+// the resources, in name order, whose sum of the per-NUMA allocatable diverges more than the allowed percentage.
+func (ac *allocatableConsistency) check(node *v1.Node, nrt *topologyv1alpha2.NodeResourceTopology) []v1.ResourceName {
+	ac.lock.Lock()
+	if rv, ok := ac.checked[nrt.Name]; ok && rv == nrt.ResourceVersion {
+		ac.lock.Unlock()
+		return nil
+	}
+	ac.checked[nrt.Name] = nrt.ResourceVersion
+	ac.lock.Unlock()
+
+	numaAllocatable := make(v1.ResourceList)
+	for _, zone := range nrt.Zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		for _, resInfo := range zone.Resources {
+			resName := v1.ResourceName(resInfo.Name)
+			total := numaAllocatable[resName]
+			total.Add(resInfo.Allocatable)
+			numaAllocatable[resName] = total
+		}
+	}
+
+	var divergent []v1.ResourceName
+	for _, resName := range sortedResourceNames(numaAllocatable) {
+		nodeQuantity, ok := node.Status.Allocatable[resName]
+		if !ok {
+			// the filter rejects the nodes lacking a requested resource anyway
+			continue
+		}
+		numaQuantity := numaAllocatable[resName]
+		if !ac.diverges(nodeQuantity, numaQuantity) {
+			continue
+		}
+		klog.V(2).InfoS("NUMA allocatable diverges from node allocatable", "node", nrt.Name, "resource", resName, "nodeAllocatable", nodeQuantity.String(), "numaAllocatable", numaQuantity.String())
+		allocatableMismatchTotal.WithLabelValues(string(resName)).Inc()
+		divergent = append(divergent, resName)
+	}
+	return divergent
+}
+
+// diverges tells if the NUMA quantity differs from the node quantity by more than the allowed percentage of the latter.
+func (ac *allocatableConsistency) diverges(nodeQuantity, numaQuantity resource.Quantity) bool {
+	diff := nodeQuantity.MilliValue() - numaQuantity.MilliValue()
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff*100 > nodeQuantity.MilliValue()*ac.percent
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestAllocatableConsistency(t *testing.T) {
+	registerMetrics()
+
+	makeResInfo := func(name, allocatable string) topologyv1alpha2.ResourceInfo {
+		resInfo := MakeTopologyResInfo(name, allocatable, allocatable)
+		resInfo.Allocatable = resource.MustParse(allocatable)
+		return resInfo
+	}
+	makeNRT := func(name string, numaMemory ...string) *topologyv1alpha2.NodeResourceTopology {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: "1"},
+		}
+		for idx, memoryAllocatable := range numaMemory {
+			nrt.Zones = append(nrt.Zones, topologyv1alpha2.Zone{
+				Name: "node-" + string(rune('0'+idx)),
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					makeResInfo(cpu, "4"),
+					makeResInfo(memory, memoryAllocatable),
+				},
+			})
+		}
+		return nrt
+	}
+	node := &v1.Node{
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("8"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		nrt      *topologyv1alpha2.NodeResourceTopology
+		expected []v1.ResourceName
+	}{
+		{
+			name: "consistent sums",
+			nrt:  makeNRT("consistent", "8Gi", "8Gi"),
+		},
+		{
+			name: "divergence within the tolerance",
+			nrt:  makeNRT("tolerated", "8Gi", "7.5Gi"),
+		},
+		{
+			name:     "divergent sums",
+			nrt:      makeNRT("divergent", "8Gi", "4Gi"),
+			expected: []v1.ResourceName{v1.ResourceMemory},
+		},
+		{
+			name:     "NUMA zones missing",
+			nrt:      makeNRT("unmapped", "16Gi"),
+			expected: []v1.ResourceName{v1.ResourceCPU},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := make(map[v1.ResourceName]float64)
+			for _, resName := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+				before[resName], _ = testutil.GetCounterMetricValue(allocatableMismatchTotal.WithLabelValues(string(resName)))
+			}
+
+			ac := newAllocatableConsistency(5)
+			got := ac.check(node, tt.nrt)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("divergent resources: got=%v expected=%v", got, tt.expected)
+			}
+			// the same update is checked once
+			if got := ac.check(node, tt.nrt); got != nil {
+				t.Errorf("unexpected divergent resources on the same update: %v", got)
+			}
+
+			for resName, value := range before {
+				after, err := testutil.GetCounterMetricValue(allocatableMismatchTotal.WithLabelValues(string(resName)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected := 0.0
+				for _, divergent := range tt.expected {
+					if divergent == resName {
+						expected = 1
+					}
+				}
+				if after-value != expected {
+					t.Errorf("metric for %s increased by %v, expected %v", resName, after-value, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestAllocatableConsistencyDisabled(t *testing.T) {
+	if ac := newAllocatableConsistency(0); ac != nil {
+		t.Errorf("expected no check, got %v", ac)
+	}
+}
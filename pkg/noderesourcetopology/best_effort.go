@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	bm "k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// bestEffortUnalignedPenaltyFactor divides the score of the nodes using the best-effort policy on which the pod can't be aligned.
+const bestEffortUnalignedPenaltyFactor = 2
+
+// bestEffortHandlerFromTopologyManagerConfig returns the handler computing the alignment the kubelet would try to achieve,
+// without enforcing it, with the given configuration, or nil if the kubelet would not try to align the pods at all.
+func bestEffortHandlerFromTopologyManagerConfig(conf TopologyManagerConfig) filterFn {
+	if conf.Policy != kubeletconfig.BestEffortTopologyManagerPolicy {
+		return nil
+	}
+	if conf.Scope == kubeletconfig.PodTopologyManagerScope {
+		return bestEffortPodLevelHandler
+	}
+	if conf.Scope == kubeletconfig.ContainerTopologyManagerScope {
+		return bestEffortContainerLevelHandler
+	}
+	return nil // cannot happen
+}
+
+// bestEffortPodLevelHandler records in the filterInfo the NUMA node the pod can be aligned on, like the kubelet prefers at
+// pod scope with the best-effort policy. If there is none, all the containers are recorded as unaligned, along with the
+// narrowest set of NUMA nodes fitting the pod, if any. The pod is admitted anyway, so it never fails.
+func bestEffortPodLevelHandler(pod *v1.Pod, info *filterInfo) *framework.Status {
+	klog.V(5).InfoS("Best effort pod level handler")
+
+	resources := info.podAlignmentResources(pod)
+	logID := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	logNumaNodes("pod handler NUMA resources", info.nodeName, info.numaNodes)
+
+	info.bestEffortHint = bm.NewEmptyBitMask()
+	numaID, match := resourcesAvailableInAnyNUMANodes(logID, info, resources, false)
+	if match {
+		info.choose(numaID)
+		_ = info.bestEffortHint.Add(numaID)
+		return nil
+	}
+
+	klog.V(4).InfoS("cannot align pod, admitted unaligned by the best-effort policy", "pod", klog.KObj(pod), "node", info.nodeName)
+	for _, container := range pod.Spec.InitContainers {
+		info.unalignedContainers = append(info.unalignedContainers, container.Name)
+	}
+	for _, container := range pod.Spec.Containers {
+		info.unalignedContainers = append(info.unalignedContainers, container.Name)
+	}
+	if numaNodes, _ := numaNodesRequired(logID, info.qos, info.includedNUMANodes(), resources, info.maxCandidateNUMASets); numaNodes != nil {
+		info.bestEffortHint = numaNodes
+	}
+	return nil
+}
+
+// bestEffortContainerLevelHandler records in the filterInfo the NUMA nodes the containers of the pod can be aligned on, each
+// one independently and consuming the resources of its NUMA node, like the kubelet prefers at container scope with the
+// best-effort policy. The bitmask accumulates the NUMA node of each aligned container, the others are recorded as unaligned.
+// The pod is admitted anyway, so it never fails.
+func bestEffortContainerLevelHandler(pod *v1.Pod, info *filterInfo) *framework.Status {
+	klog.V(5).InfoS("Best effort container level handler")
+
+	logNumaNodes("container handler NUMA resources", info.nodeName, info.numaNodes)
+
+	info.bestEffortHint = bm.NewEmptyBitMask()
+	// with the best-effort policy the unaligned containers are recorded rather than failing the alignment
+	_ = alignContainers(pod, info, info.excludedNUMANodes)
+	if len(info.unalignedContainers) > 0 {
+		klog.V(4).InfoS("cannot align all the containers, admitted unaligned by the best-effort policy", "pod", klog.KObj(pod), "node", info.nodeName, "containers", info.unalignedContainers)
+	}
+	_ = info.bestEffortHint.Add(info.chosenNUMANodes...)
+	return nil
+}
+
+// includedNUMANodes returns the NUMA nodes not excluded from the alignment.
+func (info *filterInfo) includedNUMANodes() NUMANodeList {
+	if len(info.excludedNUMANodes) == 0 {
+		return info.numaNodes
+	}
+	included := make(NUMANodeList, 0, len(info.numaNodes))
+	for _, numaNode := range info.numaNodes {
+		if _, excluded := info.excludedNUMANodes[numaNode.NUMAID]; !excluded {
+			included = append(included, numaNode)
+		}
+	}
+	return included
+}
+
+type bestEffortHintState struct {
+	hint                bm.BitMask
+	unalignedContainers []string
+}
+
+func (s *bestEffortHintState) Clone() framework.StateData {
+	hint, _ := bm.NewBitMask(s.hint.GetBits()...)
+	return &bestEffortHintState{
+		hint:                hint,
+		unalignedContainers: append([]string(nil), s.unalignedContainers...),
+	}
+}
+
+func bestEffortHintStateKey(nodeName string) framework.StateKey {
+	return framework.StateKey(Name + "/besteffort/" + nodeName)
+}
+
+// storeBestEffortHint stores in the CycleState the NUMA nodes the pod is expected to get, on a node using the best-effort
+// policy, and its containers which can't be aligned, so the nodes where the alignment is actually possible are preferred when scoring.
+func storeBestEffortHint(cycleState *framework.CycleState, nodeName string, hint bm.BitMask, unalignedContainers []string) {
+	cycleState.Write(bestEffortHintStateKey(nodeName), &bestEffortHintState{hint: hint, unalignedContainers: unalignedContainers})
+}
+
+// storedBestEffortHint returns the NUMA nodes the pod is expected to get, on a node using the best-effort policy, and its
+// containers which can't be aligned, as stored by the filter. Returns false if the filter made no best-effort alignment for the node.
+func storedBestEffortHint(cycleState *framework.CycleState, nodeName string) (bm.BitMask, []string, bool) {
+	data, err := cycleState.Read(bestEffortHintStateKey(nodeName))
+	if err != nil {
+		return nil, nil, false
+	}
+	state, ok := data.(*bestEffortHintState)
+	if !ok {
+		return nil, nil, false
+	}
+	return state.hint, state.unalignedContainers, true
+}
+
+// applyBestEffortHint penalizes the score of the nodes using the best-effort policy on which some containers of the pod
+// can't be aligned, so the nodes where the kubelet can actually align the pod are preferred.
+func applyBestEffortHint(cycleState *framework.CycleState, pod *v1.Pod, nodeName string, score int64) int64 {
+	_, unalignedContainers, ok := storedBestEffortHint(cycleState, nodeName)
+	if !ok || len(unalignedContainers) == 0 {
+		return score
+	}
+	klog.V(6).InfoS("pod not aligned by the best-effort policy", "pod", klog.KObj(pod), "node", nodeName, "containers", unalignedContainers)
+	return score / bestEffortUnalignedPenaltyFactor
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// AnnotationDowngradeToBestEffort, if "true", lets the plugin stop checking the NUMA alignment of the pod once
+// no node could align it, so the pod is scheduled like on nodes using the best-effort policy rather than staying pending.
+const AnnotationDowngradeToBestEffort = AnnotationKeyPrefix + "downgrade-to-best-effort"
+
+// downgradeExpiration is how long a pod stays downgraded without being bound, after which the alignment is checked
+// again. It also bounds the memory held for the pods deleted before being bound.
+const downgradeExpiration = 15 * time.Minute
+
+// bestEffortDowngrades tracks the pods whose NUMA alignment is no longer checked. The downgrade is decided at the end
+// of a scheduling cycle in which no node could align the pod, and takes effect from the next scheduling attempt.
+type bestEffortDowngrades struct {
+	now  func() time.Time
+	lock sync.Mutex
+	pods map[types.UID]time.Time
+}
+
+func newBestEffortDowngrades() *bestEffortDowngrades {
+	return &bestEffortDowngrades{
+		now:  time.Now,
+		pods: make(map[types.UID]time.Time),
+	}
+}
+
+// wantsDowngrade tells if the pod accepts to be downgraded to the best-effort alignment.
+func wantsDowngrade(pod *v1.Pod) bool {
+	val, ok := pod.Annotations[AnnotationDowngradeToBestEffort]
+	if !ok {
+		return false
+	}
+	downgrade, err := strconv.ParseBool(val)
+	if err != nil {
+		klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationDowngradeToBestEffort, "value", val)
+		return false
+	}
+	return downgrade
+}
+
+// downgrade records the pod as downgraded, and returns false if it was already.
+func (bd *bestEffortDowngrades) downgrade(pod *v1.Pod) bool {
+	if bd == nil {
+		return false
+	}
+	bd.lock.Lock()
+	defer bd.lock.Unlock()
+
+	now := bd.now()
+	for uid, since := range bd.pods {
+		if now.Sub(since) > downgradeExpiration {
+			delete(bd.pods, uid)
+		}
+	}
+	if _, ok := bd.pods[pod.UID]; ok {
+		return false
+	}
+	bd.pods[pod.UID] = now
+	klog.V(2).InfoS("no node can align the pod, downgrading to best-effort alignment", "pod", klog.KObj(pod))
+	bestEffortDowngradesTotal.Inc()
+	return true
+}
+
+// isDowngraded tells if the NUMA alignment of the pod must no longer be checked.
+func (bd *bestEffortDowngrades) isDowngraded(pod *v1.Pod) bool {
+	if bd == nil {
+		return false
+	}
+	bd.lock.Lock()
+	defer bd.lock.Unlock()
+
+	since, ok := bd.pods[pod.UID]
+	return ok && bd.now().Sub(since) <= downgradeExpiration
+}
+
+// forget drops the pod, once bound, from the downgraded pods.
+func (bd *bestEffortDowngrades) forget(pod *v1.Pod) {
+	if bd == nil {
+		return
+	}
+	bd.lock.Lock()
+	defer bd.lock.Unlock()
+	delete(bd.pods, pod.UID)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestBestEffortDowngrade(t *testing.T) {
+	makeNRT := func(name string) *topologyv1alpha2.NodeResourceTopology {
+		zone := func(zoneName string) topologyv1alpha2.Zone {
+			return topologyv1alpha2.Zone{
+				Name: zoneName,
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			}
+		}
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            topologyv1alpha2.ZoneList{zone("node-0"), zone("node-1")},
+		}
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{makeNRT("node1"), makeNRT("node2")}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// each node fits the pod as a whole, but no NUMA node does
+	makeDowngradablePod := func(uid, downgrade string) *v1.Pod {
+		pod := makePodByResourceList(&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("3"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		})
+		pod.UID = types.UID(uid)
+		if downgrade != "" {
+			pod.Annotations = map[string]string{AnnotationDowngradeToBestEffort: downgrade}
+		}
+		return pod
+	}
+
+	tm := &TopologyMatch{
+		nrtCache:   nrtcache.NewPassthrough(fakeClient),
+		downgrades: newBestEffortDowngrades(),
+	}
+	// runs a scheduling cycle, returns the nodes passing the filter and the PostFilter status if none passed
+	schedule := func(pod *v1.Pod) ([]string, *framework.Status) {
+		cycleState := framework.NewCycleState()
+		statuses := make(framework.NodeToStatusMap)
+		var feasible []string
+		for _, nrt := range nrts {
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			if status := tm.Filter(context.Background(), cycleState, pod, nodeInfo); status != nil {
+				statuses[nrt.Name] = status
+				continue
+			}
+			feasible = append(feasible, nrt.Name)
+		}
+		if len(feasible) > 0 {
+			return feasible, nil
+		}
+		_, status := tm.PostFilter(context.Background(), cycleState, pod, statuses)
+		return nil, status
+	}
+
+	t.Run("downgrade after strict failure everywhere", func(t *testing.T) {
+		pod := makeDowngradablePod("downgradable", "true")
+		feasible, status := schedule(pod)
+		if len(feasible) != 0 {
+			t.Fatalf("unexpected feasible nodes before the downgrade: %v", feasible)
+		}
+		if got, want := status.Message(), "cannot align pod: 2 nodes lack NUMA-local cpu, downgraded to best-effort NUMA alignment"; got != want {
+			t.Errorf("message does not match: %q, want: %q", got, want)
+		}
+
+		feasible, _ = schedule(pod)
+		if len(feasible) != len(nrts) {
+			t.Fatalf("expected all the nodes feasible after the downgrade, got: %v", feasible)
+		}
+
+		// once bound, the pod is forgotten
+		tm.PostBind(context.Background(), framework.NewCycleState(), pod, feasible[0])
+		if tm.downgrades.isDowngraded(pod) {
+			t.Errorf("pod still downgraded once bound")
+		}
+	})
+
+	for _, downgrade := range []string{"", "false", "maybe"} {
+		t.Run("no downgrade with annotation "+downgrade, func(t *testing.T) {
+			pod := makeDowngradablePod("strict-"+downgrade, downgrade)
+			for attempt := 0; attempt < 2; attempt++ {
+				feasible, status := schedule(pod)
+				if len(feasible) != 0 {
+					t.Fatalf("attempt %d: unexpected feasible nodes: %v", attempt, feasible)
+				}
+				if got, want := status.Message(), "cannot align pod: 2 nodes lack NUMA-local cpu"; got != want {
+					t.Errorf("attempt %d: message does not match: %q, want: %q", attempt, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBestEffortDowngradeKeepsOtherRejections(t *testing.T) {
+	noneNRT := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "none-node"},
+		TopologyPolicies: []string{string(topologyv1alpha2.None)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	// the available cpus exceed the capacity
+	invalidNRT := makeQuarantineTestNRT("", false)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{noneNRT, invalidNRT} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	pod.UID = types.UID("downgraded")
+	pod.Annotations = map[string]string{
+		AnnotationDowngradeToBestEffort: "true",
+		AnnotationRequiredResources:     "cpu",
+	}
+
+	tm := &TopologyMatch{
+		nrtCache:   nrtcache.NewPassthrough(fakeClient),
+		downgrades: newBestEffortDowngrades(),
+		quarantine: newNodeQuarantine(&apiconfig.NodeQuarantine{
+			Threshold:         1,
+			RecoveryThreshold: 1,
+		}),
+	}
+	tm.downgrades.downgrade(pod)
+
+	tests := []struct {
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		wantStatus *framework.Status
+	}{
+		{
+			nrt:        noneNRT,
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "cannot align required resource cpu: node topology manager policy is none"),
+		},
+		{
+			nrt:        invalidNRT,
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "node topology data quarantined"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nrt.Name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestBestEffortDowngradeExpiration(t *testing.T) {
+	now := time.Now()
+	bd := newBestEffortDowngrades()
+	bd.now = func() time.Time { return now }
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", UID: types.UID("pod")}}
+	if !bd.downgrade(pod) {
+		t.Fatalf("pod not downgraded")
+	}
+	if bd.downgrade(pod) {
+		t.Errorf("pod downgraded twice")
+	}
+
+	now = now.Add(downgradeExpiration + time.Second)
+	if bd.isDowngraded(pod) {
+		t.Errorf("pod still downgraded after the expiration")
+	}
+	if !bd.downgrade(pod) {
+		t.Errorf("pod not downgraded again after the expiration")
+	}
+}
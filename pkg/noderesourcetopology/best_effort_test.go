@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestBestEffortHandlers(t *testing.T) {
+	// the NUMA node 0 has 2 CPUs free, the NUMA node 1 has 4
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "2"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	podLevel := makeNRT("best-effort-pod-level", topologyv1alpha2.BestEffortPodLevel)
+	containerLevel := makeNRT("best-effort-container-level", topologyv1alpha2.BestEffortContainerLevel)
+	singleNUMA := makeNRT("single-numa-pod-level", topologyv1alpha2.SingleNUMANodePodLevel)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{podLevel, containerLevel, singleNUMA} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	guaranteedPod := func(cntReq ...map[string]string) *v1.Pod {
+		return makePod("guaranteed", withMultiContainers(parseContainerRes(cntReq)))
+	}
+
+	tests := []struct {
+		name      string
+		nrt       *topologyv1alpha2.NodeResourceTopology
+		pod       *v1.Pod
+		stored    bool
+		expected  []int
+		unaligned []string
+	}{
+		{
+			name:     "pod scope, aligned",
+			nrt:      podLevel,
+			pod:      guaranteedPod(map[string]string{cpu: "3", memory: "1Gi"}),
+			stored:   true,
+			expected: []int{1},
+		},
+		{
+			name:      "pod scope, not aligned",
+			nrt:       podLevel,
+			pod:       guaranteedPod(map[string]string{cpu: "2", memory: "1Gi"}, map[string]string{cpu: "3", memory: "1Gi"}),
+			stored:    true,
+			expected:  []int{0, 1},
+			unaligned: []string{"cnt-1", "cnt-2"},
+		},
+		{
+			name:      "pod scope, not fitting the node",
+			nrt:       podLevel,
+			pod:       guaranteedPod(map[string]string{cpu: "8", memory: "1Gi"}),
+			stored:    true,
+			unaligned: []string{"cnt-1"},
+		},
+		{
+			name:     "container scope, containers sharing a NUMA node",
+			nrt:      containerLevel,
+			pod:      guaranteedPod(map[string]string{cpu: "1", memory: "1Gi"}, map[string]string{cpu: "1", memory: "1Gi"}),
+			stored:   true,
+			expected: []int{0},
+		},
+		{
+			name:     "container scope, containers on different NUMA nodes",
+			nrt:      containerLevel,
+			pod:      guaranteedPod(map[string]string{cpu: "2", memory: "1Gi"}, map[string]string{cpu: "2", memory: "1Gi"}),
+			stored:   true,
+			expected: []int{0, 1},
+		},
+		{
+			name:      "container scope, container not aligned",
+			nrt:       containerLevel,
+			pod:       guaranteedPod(map[string]string{cpu: "2", memory: "1Gi"}, map[string]string{cpu: "5", memory: "1Gi"}),
+			stored:    true,
+			expected:  []int{0},
+			unaligned: []string{"cnt-2"},
+		},
+		{
+			name: "single-numa-node policy",
+			nrt:  singleNUMA,
+			pod:  guaranteedPod(map[string]string{cpu: "3", memory: "1Gi"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			before, _ := testutil.GetCounterMetricValue(alignmentBitmaskEmptyTotal.WithLabelValues(string(v1.ResourceCPU)))
+			if status := tm.Filter(context.Background(), state, tt.pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			if after, _ := testutil.GetCounterMetricValue(alignmentBitmaskEmptyTotal.WithLabelValues(string(v1.ResourceCPU))); after != before {
+				t.Errorf("alignment failures counted %v times, expected none", after-before)
+			}
+
+			hint, unaligned, ok := storedBestEffortHint(state, tt.nrt.Name)
+			if ok != tt.stored {
+				t.Fatalf("best-effort hint stored: %v, expected %v", ok, tt.stored)
+			}
+			if !ok {
+				return
+			}
+			if got := hint.GetBits(); !reflect.DeepEqual(got, tt.expected) && !(len(got) == 0 && len(tt.expected) == 0) {
+				t.Errorf("best-effort hint %v, expected %v", got, tt.expected)
+			}
+			if !reflect.DeepEqual(unaligned, tt.unaligned) {
+				t.Errorf("unaligned containers %v, expected %v", unaligned, tt.unaligned)
+			}
+		})
+	}
+}
+
+func TestBestEffortHintScore(t *testing.T) {
+	// the NUMA node 0 has 2 CPUs free, the NUMA node 1 has 4
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "best-effort-pod-level"},
+		TopologyPolicies: []string{string(topologyv1alpha2.BestEffortPodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := &TopologyMatch{
+		nrtCache:          nrtcache.NewPassthrough(fakeClient),
+		scoreStrategyType: apiconfig.LeastAllocated,
+		scoreStrategyFunc: leastAllocatedScoreStrategy,
+		resourceToWeightMap: resourceToWeightMap{
+			v1.ResourceCPU:    1,
+			v1.ResourceMemory: 1,
+		},
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+
+	score := func(pod *v1.Pod, filtered bool) int64 {
+		t.Helper()
+		state := framework.NewCycleState()
+		if filtered {
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+		}
+		got, status := tm.Score(context.Background(), state, pod, nrt.Name)
+		if !status.IsSuccess() {
+			t.Fatalf("unexpected score status: %v", status)
+		}
+		return got
+	}
+
+	unaligned := makePod("unaligned", withMultiContainers(parseContainerRes([]map[string]string{
+		{cpu: "2", memory: "1Gi"},
+		{cpu: "3", memory: "1Gi"},
+	})))
+	if unfiltered, got := score(unaligned, false), score(unaligned, true); got != unfiltered/bestEffortUnalignedPenaltyFactor || got == 0 {
+		t.Errorf("unaligned pod scored %d, expected %d", got, unfiltered/bestEffortUnalignedPenaltyFactor)
+	}
+
+	aligned := makePod("aligned", withMultiContainers(parseContainerRes([]map[string]string{
+		{cpu: "1", memory: "1Gi"},
+	})))
+	if unfiltered, got := score(aligned, false), score(aligned, true); got != unfiltered || got == 0 {
+		t.Errorf("aligned pod scored %d, expected %d", got, unfiltered)
+	}
+}
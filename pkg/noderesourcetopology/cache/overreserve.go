@@ -223,30 +223,62 @@ func (ov *OverReserve) Resync() {
 			continue
 		}
 
-		objs, ok := nodeToObjsMap[nodeName]
-		if !ok {
-			// this really should never happen
-			klog.V(3).InfoS("nrtcache: cannot find any pod for node", "logID", logID, "node", nodeName)
+		if !ov.canFlushNode(logID, nodeName, nrtCandidate, nodeToObjsMap) {
 			continue
 		}
 
-		pfpExpected, onlyExclRes := podFingerprintForNodeTopology(nrtCandidate, ov.resyncMethod)
-		if pfpExpected == "" {
-			klog.V(3).InfoS("nrtcache: missing NodeTopology podset fingerprint data", "logID", logID, "node", nodeName)
-			continue
-		}
+		klog.V(4).InfoS("nrtcache: overriding cached info", "logID", logID, "node", nodeName)
+		nrtUpdates = append(nrtUpdates, nrtCandidate)
+	}
+
+	ov.FlushNodes(logID, nrtUpdates...)
+}
+
+// FullResync implements the full resync loop step. Unlike Resync, which fetches the NRT objects of the dirty nodes one at a time,
+// this function lists all the NRT objects at once and reconciles in bulk the dirty nodes against this fresh snapshot, flushing the ones
+// whose podset fingerprint matches. The cache lock is taken only to collect the dirty nodes and to flush the reconciled ones,
+// so the scheduling cycles are not blocked while the NRT objects are listed and checked.
+func (ov *OverReserve) FullResync() {
+	// we are not working with a specific pod, so we need a unique key to track this flow
+	logID := logIDFromTime()
 
-		klog.V(6).InfoS("nrtcache: trying to resync NodeTopology", "logID", logID, "node", nodeName, "fingerprint", pfpExpected, "onlyExclusiveResources", onlyExclRes)
+	nodeNames := ov.NodesMaybeOverReserved(logID)
+	// avoid as much as we can unnecessary work and logs.
+	if len(nodeNames) == 0 {
+		klog.V(6).InfoS("nrtcache: full resync: no dirty nodes detected")
+		return
+	}
+
+	nrtObjs := &topologyv1alpha2.NodeResourceTopologyList{}
+	if err := ov.client.List(context.Background(), nrtObjs); err != nil {
+		klog.ErrorS(err, "cannot list the NodeTopology objects")
+		return
+	}
 
-		err = checkPodFingerprintForNode(logID, objs, nodeName, pfpExpected, onlyExclRes)
-		if errors.Is(err, podfingerprint.ErrSignatureMismatch) {
-			// can happen, not critical
-			klog.V(5).InfoS("nrtcache: NodeTopology podset fingerprint mismatch", "logID", logID, "node", nodeName)
+	// node -> pod identifier (namespace, name)
+	nodeToObjsMap, err := makeNodeToPodDataMap(ov.podLister, ov.isPodRelevant, logID)
+	if err != nil {
+		klog.ErrorS(err, "cannot find the mapping between running pods and nodes")
+		return
+	}
+
+	klog.V(6).InfoS("nrtcache: full resync NodeTopology cache starting", "logID", logID, "objects", len(nrtObjs.Items))
+	defer klog.V(6).InfoS("nrtcache: full resync NodeTopology cache complete", "logID", logID)
+
+	nrtByNodeName := make(map[string]*topologyv1alpha2.NodeResourceTopology, len(nrtObjs.Items))
+	for idx := range nrtObjs.Items {
+		nrtByNodeName[nrtObjs.Items[idx].Name] = &nrtObjs.Items[idx]
+	}
+
+	var nrtUpdates []*topologyv1alpha2.NodeResourceTopology
+	for _, nodeName := range nodeNames {
+		nrtCandidate, ok := nrtByNodeName[nodeName]
+		if !ok {
+			klog.V(3).InfoS("nrtcache: missing NodeTopology", "logID", logID, "node", nodeName)
 			continue
 		}
-		if err != nil {
-			// should never happen, let's be vocal
-			klog.V(3).ErrorS(err, "nrtcache: checking NodeTopology podset fingerprint", "logID", logID, "node", nodeName)
+
+		if !ov.canFlushNode(logID, nodeName, nrtCandidate, nodeToObjsMap) {
 			continue
 		}
 
@@ -257,6 +289,38 @@ func (ov *OverReserve) Resync() {
 	ov.FlushNodes(logID, nrtUpdates...)
 }
 
+// canFlushNode tells if the given fresh NRT object of a dirty node proves the node state is in sync with the pods running on it,
+// comparing the podset fingerprint it reports with the one computed from the pods the scheduler knows.
+func (ov *OverReserve) canFlushNode(logID, nodeName string, nrtCandidate *topologyv1alpha2.NodeResourceTopology, nodeToObjsMap map[string][]podData) bool {
+	objs, ok := nodeToObjsMap[nodeName]
+	if !ok {
+		// this really should never happen
+		klog.V(3).InfoS("nrtcache: cannot find any pod for node", "logID", logID, "node", nodeName)
+		return false
+	}
+
+	pfpExpected, onlyExclRes := podFingerprintForNodeTopology(nrtCandidate, ov.resyncMethod)
+	if pfpExpected == "" {
+		klog.V(3).InfoS("nrtcache: missing NodeTopology podset fingerprint data", "logID", logID, "node", nodeName)
+		return false
+	}
+
+	klog.V(6).InfoS("nrtcache: trying to resync NodeTopology", "logID", logID, "node", nodeName, "fingerprint", pfpExpected, "onlyExclusiveResources", onlyExclRes)
+
+	err := checkPodFingerprintForNode(logID, objs, nodeName, pfpExpected, onlyExclRes)
+	if errors.Is(err, podfingerprint.ErrSignatureMismatch) {
+		// can happen, not critical
+		klog.V(5).InfoS("nrtcache: NodeTopology podset fingerprint mismatch", "logID", logID, "node", nodeName)
+		return false
+	}
+	if err != nil {
+		// should never happen, let's be vocal
+		klog.V(3).ErrorS(err, "nrtcache: checking NodeTopology podset fingerprint", "logID", logID, "node", nodeName)
+		return false
+	}
+	return true
+}
+
 // FlushNodes drops all the cached information about a given node, resetting its state clean.
 func (ov *OverReserve) FlushNodes(logID string, nrts ...*topologyv1alpha2.NodeResourceTopology) {
 	ov.lock.Lock()
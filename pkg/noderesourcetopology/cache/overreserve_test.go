@@ -623,6 +623,142 @@ func TestResyncMatchFingerprint(t *testing.T) {
 	}
 }
 
+func TestFullResyncMatchFingerprint(t *testing.T) {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakePodLister := &fakePodLister{}
+
+	nrtCache := mustOverReserve(t, fakeClient, fakePodLister)
+
+	nodeTopologies := makeDefaultTestTopology()
+	for _, obj := range nodeTopologies {
+		nrtCache.Store().Update(obj)
+	}
+
+	makePod := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "namespace1",
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("8"),
+								corev1.ResourceMemory: resource.MustParse("16Gi"),
+							},
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("8"),
+								corev1.ResourceMemory: resource.MustParse("16Gi"),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// node1 will be reported in sync, node2 will not
+	testPod1 := makePod("pod1", "node1")
+	nrtCache.ReserveNodeResources("node1", testPod1)
+	nrtCache.NodeMaybeOverReserved("node1", testPod1)
+
+	testPod2 := makePod("pod2", "node2")
+	nrtCache.ReserveNodeResources("node2", testPod2)
+	nrtCache.NodeMaybeOverReserved("node2", testPod2)
+
+	freshNodeTopologies := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node1",
+				Annotations: map[string]string{
+					podfingerprint.Annotation: "pfp0v0019e0420efb37746c6",
+				},
+			},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "32", "30"),
+						MakeTopologyResInfo(memory, "64Gi", "60Gi"),
+						MakeTopologyResInfo(nicResourceName, "16", "16"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "32", "22"),
+						MakeTopologyResInfo(memory, "64Gi", "44Gi"),
+						MakeTopologyResInfo(nicResourceName, "16", "16"),
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node2",
+				Annotations: map[string]string{
+					podfingerprint.Annotation: "pfp0v001badbadbadbadbad1",
+				},
+			},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "32", "30"),
+						MakeTopologyResInfo(memory, "64Gi", "60Gi"),
+						MakeTopologyResInfo(nicResourceName, "16", "16"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "32", "30"),
+						MakeTopologyResInfo(memory, "64Gi", "60Gi"),
+						MakeTopologyResInfo(nicResourceName, "16", "16"),
+					},
+				},
+			},
+		},
+	}
+	for _, obj := range freshNodeTopologies {
+		if err := fakeClient.Create(context.Background(), obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, pod := range []*corev1.Pod{testPod1, testPod2} {
+		runningPod := pod.DeepCopy()
+		runningPod.Status.Phase = corev1.PodRunning
+		fakePodLister.AddPod(runningPod)
+	}
+
+	nrtCache.FullResync()
+
+	dirtyNodes := nrtCache.NodesMaybeOverReserved("testing")
+	if len(dirtyNodes) != 1 || dirtyNodes[0] != "node2" {
+		t.Errorf("unexpected dirty nodes after full resync: %v", dirtyNodes)
+	}
+
+	nrtObj, _ := nrtCache.GetCachedNRTCopy(context.Background(), "node1", testPod1)
+	if !isNRTEqual(nrtObj, freshNodeTopologies[0]) {
+		t.Fatalf("unexpected nrt from cache\ngot: %v\nexpected: %v\n",
+			dumpNRT(nrtObj), dumpNRT(freshNodeTopologies[0]))
+	}
+}
+
 func isNRTEqual(a, b *topologyv1alpha2.NodeResourceTopology) bool {
 	return equality.Semantic.DeepDerivative(a.Zones, b.Zones) &&
 		equality.Semantic.DeepDerivative(a.TopologyPolicies, b.TopologyPolicies) &&
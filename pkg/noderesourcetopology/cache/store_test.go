@@ -630,3 +630,64 @@ func (fpnl *fakePodNamespaceLister) List(selector labels.Selector) ([]*corev1.Po
 func (fpnl *fakePodNamespaceLister) Get(name string) (*corev1.Pod, error) {
 	return nil, fmt.Errorf("not yet implemented")
 }
+
+func TestResourceStoreUpdateFractionalQuantities(t *testing.T) {
+	const gpuSlice = "vendor.com/gpu-slice"
+
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(gpuSlice, "1.5", "1.5"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(gpuSlice, "1.5", "0.5"),
+				},
+			},
+		},
+	}
+
+	rs := newResourceStore()
+	for _, name := range []string{"pod-0", "pod-1"} {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns-0",
+				Name:      name,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "cnt-0",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceName(gpuSlice): resource.MustParse("0.5"),
+							},
+						},
+					},
+				},
+			},
+		}
+		rs.AddPod(&pod)
+	}
+	rs.UpdateNRT("testResourceStoreUpdateFractionalQuantities", nrt)
+
+	// the second slice doesn't fit in the NUMA node 1 anymore, which must be left empty, not negative
+	expected := []string{"0.5", "0"}
+	for zi, zone := range nrt.Zones {
+		info := findResourceInfo(zone.Resources, gpuSlice)
+		if info.Available.Cmp(resource.MustParse(expected[zi])) != 0 {
+			t.Errorf("bad availability for resource %q on zone %d: expected %v got %v", gpuSlice, zi, expected[zi], info.Available.String())
+		}
+		if info.Available.Sign() < 0 {
+			t.Errorf("negative availability for resource %q on zone %d: %v", gpuSlice, zi, info.Available.String())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"errors"
+	"fmt"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/resourcerequests"
+)
+
+// AlignablePods returns how many of the pods a node with the given topology would align, placing them in order, each
+// aligned pod consuming the resources of the NUMA nodes it is expected to run on, and the pods not aligned being skipped.
+// Meant for the capacity planning tools, to evaluate a node shape against the pending pods before buying the hardware:
+// the NRT object describes the topology manager configuration of the shape and the resources available on its NUMA
+// zones, which are also all the resources of the node. The checks are the ones of the filter, with the default args.
+// Returns an error if the NRT object is invalid, or the kubelet wouldn't align the pods on such a node.
+func AlignablePods(nodeTopology *topologyv1alpha2.NodeResourceTopology, pods []*v1.Pod) (int, error) {
+	if errs := ValidateNRT(nodeTopology); len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	conf := ResolveConfig(nodeTopology)
+	handler := filterHandlerFromTopologyManagerConfig(conf)
+	if handler == nil {
+		return 0, fmt.Errorf("topology manager policy %q doesn't align the pods", conf.Policy)
+	}
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeTopology.Name},
+		Status: v1.NodeStatus{
+			Capacity:    makeResourceListFromZones(nodeTopology.Zones),
+			Allocatable: makeResourceListFromZones(nodeTopology.Zones),
+		},
+	})
+	numaNodes := createNUMANodeList(nodeTopology.Zones)
+	aligned := 0
+	for _, pod := range pods {
+		qos := v1qos.GetPodQOS(pod)
+		// like the filter, the pods with nothing to align always fit
+		if (qos == v1.PodQOSBestEffort && !resourcerequests.IncludeNonNative(pod)) || resourcerequests.AreZeroForPod(pod) {
+			aligned++
+			continue
+		}
+		info := &filterInfo{
+			nodeName:          nodeTopology.Name,
+			node:              nodeInfo,
+			topologyManager:   conf,
+			qos:               qos,
+			numaNodes:         copyNUMANodeList(numaNodes),
+			preferences:       numaPreferencesFromAnnotations(pod),
+			excludedNUMANodes: untoleratedNUMANodes(pod, nodeTopology.Zones),
+		}
+		if _, missing := missingNodeResource(pod, info); missing {
+			continue
+		}
+		// the handlers consume the resources of the NUMA nodes chosen for the containers, but not at pod scope
+		if status := handler(pod, info); status != nil {
+			continue
+		}
+		if conf.Scope == kubeletconfig.PodTopologyManagerScope {
+			subtractFromNUMA(info.numaNodes, info.chosenNUMANodes[0], info.podAlignmentResources(pod), info.rounding)
+		}
+		numaNodes = info.numaNodes
+		aligned++
+	}
+	return aligned, nil
+}
+
+// copyNUMANodeList returns a copy of the NUMA nodes whose resources can be consumed without affecting the original ones.
+func copyNUMANodeList(numaNodes NUMANodeList) NUMANodeList {
+	copied := make(NUMANodeList, 0, len(numaNodes))
+	for _, numaNode := range numaNodes {
+		copied = append(copied, NUMANode{
+			NUMAID:    numaNode.NUMAID,
+			Resources: numaNode.Resources.DeepCopy(),
+			Costs:     numaNode.Costs,
+		})
+	}
+	return copied
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAlignablePods(t *testing.T) {
+	makeShape := func(policy topologyv1alpha2.TopologyManagerPolicy, numaCount int, numaCPUs string) *topologyv1alpha2.NodeResourceTopology {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: "shape"},
+			TopologyPolicies: []string{string(policy)},
+		}
+		for numaID := 0; numaID < numaCount; numaID++ {
+			nrt.Zones = append(nrt.Zones, topologyv1alpha2.Zone{
+				Name: fmt.Sprintf("node-%d", numaID),
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, numaCPUs, numaCPUs),
+					MakeTopologyResInfo(memory, "64Gi", "64Gi"),
+				},
+			})
+		}
+		return nrt
+	}
+	makePods := func(count int, cpus string) []*v1.Pod {
+		pods := make([]*v1.Pod, 0, count)
+		for idx := 0; idx < count; idx++ {
+			pods = append(pods, makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpus),
+				v1.ResourceMemory: resource.MustParse("4Gi"),
+			}))
+		}
+		return pods
+	}
+	gpuPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+		gpuResource:       resource.MustParse("1"),
+	})
+
+	tests := []struct {
+		name        string
+		shape       *topologyv1alpha2.NodeResourceTopology
+		pods        []*v1.Pod
+		expected    int
+		expectedErr bool
+	}{
+		{
+			name:     "two NUMA nodes, pod scope",
+			shape:    makeShape(topologyv1alpha2.SingleNUMANodePodLevel, 2, "16"),
+			pods:     makePods(8, "6"),
+			expected: 4,
+		},
+		{
+			name:     "four NUMA nodes, pod scope",
+			shape:    makeShape(topologyv1alpha2.SingleNUMANodePodLevel, 4, "8"),
+			pods:     makePods(8, "6"),
+			expected: 4,
+		},
+		{
+			name:     "four NUMA nodes, container scope",
+			shape:    makeShape(topologyv1alpha2.SingleNUMANodeContainerLevel, 4, "8"),
+			pods:     makePods(8, "3"),
+			expected: 8,
+		},
+		{
+			name:     "pods not aligned skipped",
+			shape:    makeShape(topologyv1alpha2.SingleNUMANodePodLevel, 2, "16"),
+			pods:     append([]*v1.Pod{gpuPod}, makePods(4, "10")...),
+			expected: 2,
+		},
+		{
+			name:        "shape not aligning the pods",
+			shape:       makeShape(topologyv1alpha2.BestEffortPodLevel, 2, "16"),
+			pods:        makePods(8, "6"),
+			expectedErr: true,
+		},
+		{
+			name: "invalid shape",
+			shape: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta:       metav1.ObjectMeta{Name: "shape"},
+				TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+				Zones: topologyv1alpha2.ZoneList{
+					{
+						Name:      "numa-0",
+						Type:      "Node",
+						Resources: topologyv1alpha2.ResourceInfoList{MakeTopologyResInfo(cpu, "16", "16")},
+					},
+				},
+			},
+			pods:        makePods(8, "6"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AlignablePods(tt.shape, tt.pods)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("alignable pods: got=%d expected=%d", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var chosenNUMATotal = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Subsystem:      "scheduler_plugins",
+		Name:           "nrt_chosen_numa_total",
+		Help:           "Number of pods placed on each NUMA node of each node, as chosen by the filter for the reserved node.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"node", "numa"},
+)
+
+// observeChosenNUMANodes counts the pod once on each NUMA node the filter chose for its containers on the reserved node.
+func (tm *TopologyMatch) observeChosenNUMANodes(cycleState *framework.CycleState, nodeName string) {
+	if !tm.chosenNUMAMetric {
+		return
+	}
+	containerNUMANodes, ok := storedPlacement(cycleState, nodeName)
+	if !ok {
+		return
+	}
+	observed := make(map[int]bool, len(containerNUMANodes))
+	for _, numaID := range containerNUMANodes {
+		if observed[numaID] {
+			continue
+		}
+		observed[numaID] = true
+		chosenNUMATotal.WithLabelValues(nodeName, strconv.Itoa(numaID)).Inc()
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestChosenNUMAMetric(t *testing.T) {
+	registerMetrics()
+
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "2"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "chosen-pod-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            zones,
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "chosen-container-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones:            zones,
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "chosen-disabled"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            zones,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cntReq := []map[string]string{
+		{cpu: "2", memory: "1Gi"},
+		{cpu: "2", memory: "1Gi"},
+	}
+
+	tests := []struct {
+		name     string
+		nrt      *topologyv1alpha2.NodeResourceTopology
+		disabled bool
+		// increments by NUMA ID
+		expected map[string]float64
+	}{
+		{
+			name:     "pod scope",
+			nrt:      nrts[0],
+			expected: map[string]float64{"0": 0, "1": 1},
+		},
+		{
+			name:     "container scope",
+			nrt:      nrts[1],
+			expected: map[string]float64{"0": 1, "1": 1},
+		},
+		{
+			name:     "disabled",
+			nrt:      nrts[2],
+			disabled: true,
+			expected: map[string]float64{"0": 0, "1": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := make(map[string]float64)
+			for numaID := range tt.expected {
+				before[numaID], _ = testutil.GetCounterMetricValue(chosenNUMATotal.WithLabelValues(tt.nrt.Name, numaID))
+			}
+
+			tm := &TopologyMatch{
+				nrtCache:         nrtcache.NewPassthrough(fakeClient),
+				chosenNUMAMetric: !tt.disabled,
+			}
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			if status := tm.Reserve(context.Background(), state, pod, tt.nrt.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected reserve status: %v", status)
+			}
+
+			for numaID, expected := range tt.expected {
+				after, err := testutil.GetCounterMetricValue(chosenNUMATotal.WithLabelValues(tt.nrt.Name, numaID))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if after-before[numaID] != expected {
+					t.Errorf("metric for NUMA %s increased by %v, expected %v", numaID, after-before[numaID], expected)
+				}
+			}
+		})
+	}
+}
@@ -17,7 +17,13 @@ limitations under the License.
 package noderesourcetopology
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
 
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
@@ -26,6 +32,16 @@ import (
 const (
 	AttributeScope  = "topologyManagerScope"
 	AttributePolicy = "topologyManagerPolicy"
+	// AttributeHintProviders lists, comma-separated, the resource managers of the kubelet providing topology hints,
+	// e.g. "device" on the nodes on which only the Device Manager enforces the NUMA alignment. If missing, all the
+	// resource managers are assumed to be active.
+	AttributeHintProviders = "topologyManagerHintProviders"
+)
+
+const (
+	HintProviderCPU    = "cpu"
+	HintProviderMemory = "memory"
+	HintProviderDevice = "device"
 )
 
 // TODO: handle topologyManagerPolicyOptions added in k8s 1.26
@@ -37,6 +53,10 @@ func IsValidScope(scope string) bool {
 	return false
 }
 
+func IsValidHintProvider(provider string) bool {
+	return provider == HintProviderCPU || provider == HintProviderMemory || provider == HintProviderDevice
+}
+
 func IsValidPolicy(policy string) bool {
 	if policy == kubeletconfig.NoneTopologyManagerPolicy || policy == kubeletconfig.BestEffortTopologyManagerPolicy ||
 		policy == kubeletconfig.RestrictedTopologyManagerPolicy || policy == kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
@@ -48,6 +68,85 @@ func IsValidPolicy(policy string) bool {
 type TopologyManagerConfig struct {
 	Scope  string
 	Policy string
+	// HintProviders lists the resource managers enforcing the NUMA alignment of the resources they govern.
+	// Empty means all of them.
+	HintProviders []string
+}
+
+// providesHints tells if the NUMA alignment of the resource is enforced by any of the active resource managers:
+// cpu by the CPU Manager, memory and hugepages by the Memory Manager, the devices by the Device Manager.
+func (conf TopologyManagerConfig) providesHints(resName v1.ResourceName) bool {
+	if len(conf.HintProviders) == 0 {
+		return true
+	}
+	var provider string
+	switch {
+	case resName == v1.ResourceCPU:
+		provider = HintProviderCPU
+	case resName == v1.ResourceMemory || v1helper.IsHugePageResourceName(resName):
+		provider = HintProviderMemory
+	case !v1helper.IsNativeResource(resName):
+		provider = HintProviderDevice
+	default:
+		// no resource manager governs the other native resources, which are handled as usual
+		return true
+	}
+	for _, hintProvider := range conf.HintProviders {
+		if hintProvider == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffConfig describes the differences between the topology manager configurations a and b, one item per changed
+// setting, e.g. `policy: "restricted" -> "single-numa-node"`. Returns nil if the configurations are equivalent.
+// Listing no hint providers is equivalent to listing all of them. The filter logs the differences at verbosity 2
+// when an NRT update changes the configuration reported by a node.
+func DiffConfig(a, b TopologyManagerConfig) []string {
+	var diffs []string
+	if a.Policy != b.Policy {
+		diffs = append(diffs, fmt.Sprintf("policy: %q -> %q", a.Policy, b.Policy))
+	}
+	if a.Scope != b.Scope {
+		diffs = append(diffs, fmt.Sprintf("scope: %q -> %q", a.Scope, b.Scope))
+	}
+	if providersA, providersB := normalizedHintProviders(a.HintProviders), normalizedHintProviders(b.HintProviders); providersA != providersB {
+		diffs = append(diffs, fmt.Sprintf("hint providers: %q -> %q", providersA, providersB))
+	}
+	return diffs
+}
+
+// normalizedHintProviders returns the sorted, comma-separated, distinct hint providers, all of them if none is listed.
+func normalizedHintProviders(providers []string) string {
+	if len(providers) == 0 {
+		providers = []string{HintProviderCPU, HintProviderMemory, HintProviderDevice}
+	}
+	seen := make(map[string]bool, len(providers))
+	var names []string
+	for _, provider := range providers {
+		if seen[provider] {
+			continue
+		}
+		seen[provider] = true
+		names = append(names, provider)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// parseHintProviders returns the valid resource managers listed in the value, ignoring the others.
+func parseHintProviders(value string) []string {
+	var providers []string
+	for _, provider := range strings.Split(value, ",") {
+		provider = strings.TrimSpace(provider)
+		if !IsValidHintProvider(provider) {
+			klog.Warningf("ignoring unknown hint provider %q in attribute %q", provider, AttributeHintProviders)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
 }
 
 func makeTopologyManagerConfigDefaults() TopologyManagerConfig {
@@ -57,6 +156,36 @@ func makeTopologyManagerConfigDefaults() TopologyManagerConfig {
 	}
 }
 
+// PolicyResolver provides the topology manager configuration of the nodes.
+type PolicyResolver interface {
+	// TopologyManagerConfig returns the configuration of the node described by nodeTopology.
+	TopologyManagerConfig(nodeTopology *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig
+}
+
+// NRTPolicyResolver is the default PolicyResolver, which reads the configuration from the NRT object itself.
+type NRTPolicyResolver struct{}
+
+func (NRTPolicyResolver) TopologyManagerConfig(nodeTopology *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
+	return ResolveConfig(nodeTopology)
+}
+
+// ResolveConfig returns the topology manager configuration reported by the NRT object, like the plugin resolves it:
+// the attributes take precedence over the deprecated topologyPolicies field, and the unreported settings get the
+// kubelet defaults. Meant for the other plugins and tools, to avoid reimplementing the resolution.
+func ResolveConfig(nodeTopology *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
+	return topologyManagerConfigFromNodeResourceTopology(nodeTopology)
+}
+
+// OverlayPolicyResolver is a PolicyResolver which returns the same configuration for all the nodes,
+// regardless of the one they report. Meant for shadow or test scheduler profiles.
+type OverlayPolicyResolver struct {
+	Config TopologyManagerConfig
+}
+
+func (r OverlayPolicyResolver) TopologyManagerConfig(_ *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
+	return r.Config
+}
+
 func topologyManagerConfigFromNodeResourceTopology(nodeTopology *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
 	conf := makeTopologyManagerConfigDefaults()
 	// Backward compatibility (v1alpha2 and previous). Deprecated, will be removed when the NRT API moves to v1beta1.
@@ -66,20 +195,63 @@ func topologyManagerConfigFromNodeResourceTopology(nodeTopology *topologyv1alpha
 	return conf
 }
 
+// updateTopologyManagerConfigFromAttributes sets the scope and the policy from the attributes.
+// Should the attributes erroneously list the scope or the policy more than once, the first valid value is used
+// and the others are ignored, so the outcome doesn't depend on how many duplicates are listed.
 func updateTopologyManagerConfigFromAttributes(conf *TopologyManagerConfig, attrs topologyv1alpha2.AttributeList) {
+	scopeFound, policyFound := false, false
 	for _, attr := range attrs {
+		if attr.Name == AttributeHintProviders && conf.HintProviders == nil {
+			conf.HintProviders = parseHintProviders(attr.Value)
+			continue
+		}
 		if attr.Name == AttributeScope && IsValidScope(attr.Value) {
+			if scopeFound {
+				if attr.Value != conf.Scope {
+					klog.Warningf("ignoring duplicate attribute %q value %q, using %q", AttributeScope, attr.Value, conf.Scope)
+				}
+				continue
+			}
 			conf.Scope = attr.Value
+			scopeFound = true
 			continue
 		}
 		if attr.Name == AttributePolicy && IsValidPolicy(attr.Value) {
+			if policyFound {
+				if attr.Value != conf.Policy {
+					klog.Warningf("ignoring duplicate attribute %q value %q, using %q", AttributePolicy, attr.Value, conf.Policy)
+				}
+				continue
+			}
 			conf.Policy = attr.Value
+			policyFound = true
 			continue
 		}
 		// TODO: handle topologyManagerPolicyOptions added in k8s 1.26
 	}
 }
 
+// attributesProvideConfig returns true if the attributes set both a valid scope and a valid policy, overriding
+// entirely the deprecated topologyPolicies field.
+func attributesProvideConfig(attrs topologyv1alpha2.AttributeList) bool {
+	scopeFound, policyFound := false, false
+	for _, attr := range attrs {
+		scopeFound = scopeFound || (attr.Name == AttributeScope && IsValidScope(attr.Value))
+		policyFound = policyFound || (attr.Name == AttributePolicy && IsValidPolicy(attr.Value))
+	}
+	return scopeFound && policyFound
+}
+
+// hasConflictingTopologyPolicies returns true if the deprecated topologyPolicies list more than one distinct policy.
+func hasConflictingTopologyPolicies(topologyPolicies []string) bool {
+	for _, policy := range topologyPolicies {
+		if policy != topologyPolicies[0] {
+			return true
+		}
+	}
+	return false
+}
+
 func updateTopologyManagerConfigFromTopologyPolicies(conf *TopologyManagerConfig, nodeName string, topologyPolicies []string) {
 	if len(topologyPolicies) == 0 {
 		klog.V(3).InfoS("Cannot determine policy", "node", nodeName)
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sync"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+type observedConfig struct {
+	resourceVersion string
+	conf            TopologyManagerConfig
+}
+
+// configChanges remembers the topology manager configuration last reported by each node, and logs what changed
+// when an NRT update reports a different one. Each update, identified by its resource version, is resolved once.
+type configChanges struct {
+	lock  sync.Mutex
+	nodes map[string]observedConfig
+}
+
+func newConfigChanges() *configChanges {
+	return &configChanges{
+		nodes: make(map[string]observedConfig),
+	}
+}
+
+// forgetDeletedNodes makes the tracker drop the configuration of the nodes deleted from the cluster.
+func (cc *configChanges) forgetDeletedNodes(nodeInformer k8scache.SharedInformer) {
+	nodeInformer.AddEventHandler(k8scache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(k8scache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			node, ok := obj.(*v1.Node)
+			if !ok {
+				return
+			}
+			cc.forget(node.Name)
+		},
+	})
+}
+
+func (cc *configChanges) forget(nodeName string) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	delete(cc.nodes, nodeName)
+}
+
+// observe records the configuration reported by the NRT object, resolved by resolve if not observed already, and
+// returns the differences with the configuration previously reported by the node. The first observation of a node
+// returns nil.
+func (cc *configChanges) observe(nrt *topologyv1alpha2.NodeResourceTopology, resolve func(*topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig) []string {
+	if cc == nil {
+		return nil
+	}
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	prev, ok := cc.nodes[nrt.Name]
+	if ok && prev.resourceVersion == nrt.ResourceVersion {
+		return nil
+	}
+	conf := resolve(nrt)
+	cc.nodes[nrt.Name] = observedConfig{resourceVersion: nrt.ResourceVersion, conf: conf}
+	if !ok {
+		return nil
+	}
+	diffs := DiffConfig(prev.conf, conf)
+	if len(diffs) > 0 {
+		klog.V(2).InfoS("topology manager configuration changed", "node", nrt.Name, "changes", diffs)
+	}
+	return diffs
+}
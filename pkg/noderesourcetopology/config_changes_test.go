@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigChanges(t *testing.T) {
+	makeNRT := func(resourceVersion, policy, scope string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: "node", ResourceVersion: resourceVersion},
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: policy},
+				{Name: AttributeScope, Value: scope},
+			},
+		}
+	}
+	resolved := 0
+	resolve := func(nrt *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
+		resolved++
+		return topologyManagerConfigFromNodeResourceTopology(nrt)
+	}
+
+	steps := []struct {
+		name     string
+		nrt      *topologyv1alpha2.NodeResourceTopology
+		forget   bool
+		expected []string
+		resolved int
+	}{
+		{
+			name:     "first observation",
+			nrt:      makeNRT("1", "single-numa-node", "pod"),
+			resolved: 1,
+		},
+		{
+			name:     "same update",
+			nrt:      makeNRT("1", "restricted", "pod"),
+			resolved: 1,
+		},
+		{
+			name:     "update with the same config",
+			nrt:      makeNRT("2", "single-numa-node", "pod"),
+			resolved: 2,
+		},
+		{
+			name:     "policy changed",
+			nrt:      makeNRT("3", "restricted", "pod"),
+			expected: []string{`policy: "single-numa-node" -> "restricted"`},
+			resolved: 3,
+		},
+		{
+			name:     "scope changed",
+			nrt:      makeNRT("4", "restricted", "container"),
+			expected: []string{`scope: "pod" -> "container"`},
+			resolved: 4,
+		},
+		{
+			name:     "node deleted and recreated",
+			nrt:      makeNRT("5", "single-numa-node", "pod"),
+			forget:   true,
+			resolved: 5,
+		},
+	}
+
+	cc := newConfigChanges()
+	for _, step := range steps {
+		if step.forget {
+			cc.forget(step.nrt.Name)
+		}
+		got := cc.observe(step.nrt, resolve)
+		if !reflect.DeepEqual(got, step.expected) {
+			t.Errorf("%s: changes=%q expected=%q", step.name, got, step.expected)
+		}
+		if resolved != step.resolved {
+			t.Errorf("%s: resolved %d configs, expected %d", step.name, resolved, step.resolved)
+		}
+	}
+}
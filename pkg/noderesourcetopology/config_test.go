@@ -17,12 +17,20 @@ limitations under the License.
 package noderesourcetopology
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
 )
 
 func TestIsValidScope(t *testing.T) {
@@ -119,6 +127,85 @@ func TestIsValidPolicy(t *testing.T) {
 	}
 }
 
+func TestDiffConfig(t *testing.T) {
+	singleNUMAPod := TopologyManagerConfig{
+		Policy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+		Scope:  kubeletconfig.PodTopologyManagerScope,
+	}
+
+	tests := []struct {
+		name     string
+		a        TopologyManagerConfig
+		b        TopologyManagerConfig
+		expected []string
+	}{
+		{
+			name: "same config",
+			a:    singleNUMAPod,
+			b:    singleNUMAPod,
+		},
+		{
+			name: "policy changed",
+			a:    singleNUMAPod,
+			b: TopologyManagerConfig{
+				Policy: kubeletconfig.RestrictedTopologyManagerPolicy,
+				Scope:  kubeletconfig.PodTopologyManagerScope,
+			},
+			expected: []string{`policy: "single-numa-node" -> "restricted"`},
+		},
+		{
+			name: "policy and scope changed",
+			a:    singleNUMAPod,
+			b:    makeTopologyManagerConfigDefaults(),
+			expected: []string{
+				`policy: "single-numa-node" -> "none"`,
+				`scope: "pod" -> "container"`,
+			},
+		},
+		{
+			name: "hint providers changed",
+			a:    singleNUMAPod,
+			b: TopologyManagerConfig{
+				Policy:        kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:         kubeletconfig.PodTopologyManagerScope,
+				HintProviders: []string{HintProviderDevice},
+			},
+			expected: []string{`hint providers: "cpu,device,memory" -> "device"`},
+		},
+		{
+			name: "all hint providers listed",
+			a:    singleNUMAPod,
+			b: TopologyManagerConfig{
+				Policy:        kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:         kubeletconfig.PodTopologyManagerScope,
+				HintProviders: []string{HintProviderMemory, HintProviderDevice, HintProviderCPU},
+			},
+		},
+		{
+			name: "hint providers reordered",
+			a: TopologyManagerConfig{
+				Policy:        kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:         kubeletconfig.PodTopologyManagerScope,
+				HintProviders: []string{HintProviderDevice, HintProviderCPU},
+			},
+			b: TopologyManagerConfig{
+				Policy:        kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:         kubeletconfig.PodTopologyManagerScope,
+				HintProviders: []string{HintProviderCPU, HintProviderDevice, HintProviderCPU},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffConfig(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got=%q expected=%q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestConfigFromAttributes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -225,6 +312,51 @@ func TestConfigFromAttributes(t *testing.T) {
 				Policy: kubeletconfig.RestrictedTopologyManagerPolicy,
 			},
 		},
+		{
+			name: "duplicate-keys",
+			attrs: topologyv1alpha2.AttributeList{
+				{
+					Name:  "topologyManagerPolicy",
+					Value: "single-numa-node",
+				},
+				{
+					Name:  "topologyManagerScope",
+					Value: "pod",
+				},
+				{
+					Name:  "topologyManagerPolicy",
+					Value: "restricted",
+				},
+				{
+					Name:  "topologyManagerScope",
+					Value: "container",
+				},
+			},
+			expected: TopologyManagerConfig{
+				Policy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:  kubeletconfig.PodTopologyManagerScope,
+			},
+		},
+		{
+			name: "duplicate-keys-first-invalid",
+			attrs: topologyv1alpha2.AttributeList{
+				{
+					Name:  "topologyManagerPolicy",
+					Value: "SingleNUMANode",
+				},
+				{
+					Name:  "topologyManagerPolicy",
+					Value: "best-effort",
+				},
+				{
+					Name:  "topologyManagerPolicy",
+					Value: "restricted",
+				},
+			},
+			expected: TopologyManagerConfig{
+				Policy: kubeletconfig.BestEffortTopologyManagerPolicy,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -376,6 +508,26 @@ func TestConfigFromNRT(t *testing.T) {
 				Scope:  kubeletconfig.ContainerTopologyManagerScope,
 			},
 		},
+		{
+			name: "attributes-hint-providers",
+			nrt: topologyv1alpha2.NodeResourceTopology{
+				Attributes: topologyv1alpha2.AttributeList{
+					{
+						Name:  "topologyManagerPolicy",
+						Value: "single-numa-node",
+					},
+					{
+						Name:  "topologyManagerHintProviders",
+						Value: "device, unknown",
+					},
+				},
+			},
+			expected: TopologyManagerConfig{
+				Policy:        kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:         kubeletconfig.ContainerTopologyManagerScope,
+				HintProviders: []string{HintProviderDevice},
+			},
+		},
 		{
 			name: "attributes-overrides-policy-full",
 			nrt: topologyv1alpha2.NodeResourceTopology{
@@ -410,3 +562,420 @@ func TestConfigFromNRT(t *testing.T) {
 	}
 
 }
+
+func TestResolveConfig(t *testing.T) {
+	nrts := map[string]*topologyv1alpha2.NodeResourceTopology{
+		"empty": {},
+		"deprecated-policies": {
+			TopologyPolicies: []string{string(topologyv1alpha2.RestrictedPodLevel)},
+		},
+		"attributes": {
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: kubeletconfig.SingleNumaNodeTopologyManagerPolicy},
+				{Name: AttributeScope, Value: kubeletconfig.PodTopologyManagerScope},
+				{Name: AttributeHintProviders, Value: "cpu,device"},
+			},
+		},
+		"attributes-override-deprecated-policies": {
+			TopologyPolicies: []string{string(topologyv1alpha2.BestEffortContainerLevel)},
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: kubeletconfig.SingleNumaNodeTopologyManagerPolicy},
+			},
+		},
+		"invalid-attributes": {
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: "foo"},
+				{Name: AttributeScope, Value: "bar"},
+			},
+		},
+	}
+
+	for name, nrt := range nrts {
+		t.Run(name, func(t *testing.T) {
+			got := ResolveConfig(nrt)
+			if expected := topologyManagerConfigFromNodeResourceTopology(nrt); !reflect.DeepEqual(got, expected) {
+				t.Errorf("conf got=%+#v expected=%+#v", got, expected)
+			}
+			if resolved := (NRTPolicyResolver{}).TopologyManagerConfig(nrt); !reflect.DeepEqual(got, resolved) {
+				t.Errorf("conf got=%+#v resolved=%+#v", got, resolved)
+			}
+			tm := TopologyMatch{policyResolver: NRTPolicyResolver{}}
+			if used := tm.topologyManagerConfig(nrt); !reflect.DeepEqual(got, used) {
+				t.Errorf("conf got=%+#v used by the plugin=%+#v", got, used)
+			}
+		})
+	}
+}
+
+func TestNodeTopologyManagerConfig(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Attributes: topologyv1alpha2.AttributeList{
+			{Name: AttributePolicy, Value: kubeletconfig.RestrictedTopologyManagerPolicy},
+			{Name: AttributeScope, Value: kubeletconfig.PodTopologyManagerScope},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                   string
+		nodeName               string
+		restrictedAsSingleNUMA bool
+		expected               TopologyManagerConfig
+		expectedOK             bool
+	}{
+		{
+			name:     "reported configuration",
+			nodeName: "node1",
+			expected: TopologyManagerConfig{
+				Policy: kubeletconfig.RestrictedTopologyManagerPolicy,
+				Scope:  kubeletconfig.PodTopologyManagerScope,
+			},
+			expectedOK: true,
+		},
+		{
+			name:                   "restricted policy handled as single-numa-node",
+			nodeName:               "node1",
+			restrictedAsSingleNUMA: true,
+			expected: TopologyManagerConfig{
+				Policy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:  kubeletconfig.PodTopologyManagerScope,
+			},
+			expectedOK: true,
+		},
+		{
+			name:     "missing NRT data",
+			nodeName: "node2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				policyResolver:         NRTPolicyResolver{},
+				restrictedAsSingleNUMA: tt.restrictedAsSingleNUMA,
+			}
+			got, ok := tm.NodeTopologyManagerConfig(context.Background(), tt.nodeName)
+			if ok != tt.expectedOK || !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("conf got=%+#v,%v expected=%+#v,%v", got, ok, tt.expected, tt.expectedOK)
+			}
+		})
+	}
+}
+
+type fakePolicyResolver struct {
+	conf TopologyManagerConfig
+}
+
+func (fpr fakePolicyResolver) TopologyManagerConfig(nodeTopology *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
+	return fpr.conf
+}
+
+func TestFilterWithPolicyResolver(t *testing.T) {
+	// the NRT object reports the none policy, so the filter would admit the pod if the resolver was ignored
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Attributes: topologyv1alpha2.AttributeList{
+			{Name: AttributePolicy, Value: kubeletconfig.NoneTopologyManagerPolicy},
+		},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+
+	tests := []struct {
+		name       string
+		resolver   PolicyResolver
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "default resolver",
+			resolver:   NRTPolicyResolver{},
+			wantStatus: nil,
+		},
+		{
+			name: "resolver forcing single-numa-node policy",
+			resolver: fakePolicyResolver{
+				conf: TopologyManagerConfig{
+					Policy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+					Scope:  kubeletconfig.PodTopologyManagerScope,
+				},
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:       nrtcache.NewPassthrough(fakeClient),
+				policyResolver: tt.resolver,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestFilterWithOverlayPolicyResolver(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+	}
+	// the nodes report different configurations, all admitting a pod spanning both NUMA nodes
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "attributes"},
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: kubeletconfig.BestEffortTopologyManagerPolicy},
+				{Name: AttributeScope, Value: kubeletconfig.ContainerTopologyManagerScope},
+			},
+			Zones: zones,
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "policies"},
+			TopologyPolicies: []string{string(topologyv1alpha2.None)},
+			Zones:            zones,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unset"},
+			Zones:      zones,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+	overlay := OverlayPolicyResolver{
+		Config: TopologyManagerConfig{
+			Policy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+			Scope:  kubeletconfig.PodTopologyManagerScope,
+		},
+	}
+
+	for _, nrt := range nrts {
+		t.Run(nrt.Name, func(t *testing.T) {
+			if got := overlay.TopologyManagerConfig(nrt); !reflect.DeepEqual(got, overlay.Config) {
+				t.Errorf("conf got=%+#v expected=%+#v", got, overlay.Config)
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			tm := TopologyMatch{
+				nrtCache:       nrtcache.NewPassthrough(fakeClient),
+				policyResolver: NRTPolicyResolver{},
+			}
+			if gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo); gotStatus != nil {
+				t.Errorf("reported configuration: unexpected status: %v", gotStatus)
+			}
+
+			tm.policyResolver = overlay
+			wantStatus := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+			if gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo); !reflect.DeepEqual(gotStatus, wantStatus) {
+				t.Errorf("overlay: status does not match: %v, want: %v", gotStatus, wantStatus)
+			}
+		})
+	}
+}
+
+func TestFilterStrictTopologyPolicies(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+	}
+	// the first policy listed makes the filter reject a pod spanning both NUMA nodes
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "conflicting"},
+			TopologyPolicies: []string{
+				string(topologyv1alpha2.SingleNUMANodePodLevel),
+				string(topologyv1alpha2.BestEffortPodLevel),
+			},
+			Zones: zones,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "duplicate"},
+			TopologyPolicies: []string{
+				string(topologyv1alpha2.SingleNUMANodePodLevel),
+				string(topologyv1alpha2.SingleNUMANodePodLevel),
+			},
+			Zones: zones,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "overridden"},
+			TopologyPolicies: []string{
+				string(topologyv1alpha2.BestEffortPodLevel),
+				string(topologyv1alpha2.SingleNUMANodePodLevel),
+			},
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: kubeletconfig.SingleNumaNodeTopologyManagerPolicy},
+				{Name: AttributeScope, Value: kubeletconfig.PodTopologyManagerScope},
+			},
+			Zones: zones,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "scope-from-policies"},
+			TopologyPolicies: []string{
+				string(topologyv1alpha2.SingleNUMANodePodLevel),
+				string(topologyv1alpha2.BestEffortPodLevel),
+			},
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: kubeletconfig.SingleNumaNodeTopologyManagerPolicy},
+			},
+			Zones: zones,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+	cannotAlign := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+
+	tests := []struct {
+		name       string
+		nodeName   string
+		strict     bool
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "conflicting policies, first one used",
+			nodeName:   "conflicting",
+			wantStatus: cannotAlign,
+		},
+		{
+			name:       "conflicting policies, strict",
+			nodeName:   "conflicting",
+			strict:     true,
+			wantStatus: nil,
+		},
+		{
+			name:       "duplicate policy, strict",
+			nodeName:   "duplicate",
+			strict:     true,
+			wantStatus: cannotAlign,
+		},
+		{
+			name:       "conflicting policies overridden by the attributes, strict",
+			nodeName:   "overridden",
+			strict:     true,
+			wantStatus: cannotAlign,
+		},
+		{
+			name:       "conflicting policies providing the scope, strict",
+			nodeName:   "scope-from-policies",
+			strict:     true,
+			wantStatus: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				policyResolver:         NRTPolicyResolver{},
+				strictTopologyPolicies: tt.strict,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			for _, nrt := range nrts {
+				if nrt.Name == tt.nodeName {
+					nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+				}
+			}
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
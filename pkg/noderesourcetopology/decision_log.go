@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/configz"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// decisionLogConfigzPrefix prefixes the name of the decision log of each scheduler profile in the /configz endpoint.
+const decisionLogConfigzPrefix = "noderesourcetopology.decisions."
+
+// filterDecision is the outcome of the filter for a pod on a node.
+type filterDecision struct {
+	Time    time.Time `json:"time"`
+	Pod     string    `json:"pod"`
+	Node    string    `json:"node"`
+	Verdict string    `json:"verdict"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// decisionSlot is a filter decision, and its position in the sequence of the recorded ones.
+type decisionSlot struct {
+	seq      uint64
+	decision filterDecision
+}
+
+// decisionLog keeps the most recent filter decisions in a ring buffer, to debug the plugin without raising the log
+// verbosity. The oldest decisions are overwritten once the buffer is full. The filter runs in parallel on the nodes,
+// so the decisions are recorded without locking: the readers skip the slots overwritten meanwhile.
+type decisionLog struct {
+	next    atomic.Uint64
+	entries []atomic.Pointer[decisionSlot]
+}
+
+func newDecisionLog(size int64) *decisionLog {
+	return &decisionLog{
+		entries: make([]atomic.Pointer[decisionSlot], size),
+	}
+}
+
+// startDecisionLog makes the decision log of the plugin served by the /configz endpoint of the scheduler, which honors
+// its authentication and authorization, under a name unique to the scheduler profile.
+func startDecisionLog(tm *TopologyMatch, conf *apiconfig.DecisionLog, profileName string) error {
+	name := decisionLogConfigzPrefix + profileName
+	cz, err := configz.New(name)
+	if err != nil {
+		return fmt.Errorf("cannot register the decision log: %w", err)
+	}
+	tm.decisionLog = newDecisionLog(conf.Size)
+	cz.Set(tm.decisionLog)
+	klog.InfoS("Enabling the decision log", "size", conf.Size, "configz", name)
+	return nil
+}
+
+// profileName returns the name of the scheduler profile the plugin runs in, empty if unknown.
+func profileName(handle framework.Handle) string {
+	fwk, ok := handle.(framework.Framework)
+	if !ok {
+		return ""
+	}
+	return fwk.ProfileName()
+}
+
+func (dl *decisionLog) record(pod *v1.Pod, nodeName string, status *framework.Status) {
+	if dl == nil {
+		return
+	}
+	seq := dl.next.Add(1) - 1
+	dl.entries[seq%uint64(len(dl.entries))].Store(&decisionSlot{
+		seq: seq,
+		decision: filterDecision{
+			Time:    time.Now(),
+			Pod:     klog.KObj(pod).String(),
+			Node:    nodeName,
+			Verdict: status.Code().String(),
+			Reason:  status.Message(),
+		},
+	})
+}
+
+// decisions returns a copy of the recorded decisions, oldest first.
+func (dl *decisionLog) decisions() []filterDecision {
+	size := uint64(len(dl.entries))
+	next := dl.next.Load()
+	first := uint64(0)
+	if next > size {
+		first = next - size
+	}
+	decisions := make([]filterDecision, 0, next-first)
+	for seq := first; seq < next; seq++ {
+		slot := dl.entries[seq%size].Load()
+		// not stored yet, or overwritten by a newer decision
+		if slot == nil || slot.seq != seq {
+			continue
+		}
+		decisions = append(decisions, slot.decision)
+	}
+	return decisions
+}
+
+func (dl *decisionLog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dl.decisions())
+}
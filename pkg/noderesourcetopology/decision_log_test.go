@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/configz"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestDecisionLogRing(t *testing.T) {
+	dl := newDecisionLog(2)
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"}}
+	if got := dl.decisions(); len(got) != 0 {
+		t.Fatalf("unexpected decisions in an empty log: %v", got)
+	}
+
+	dl.record(pod, "node1", nil)
+	dl.record(pod, "node2", framework.NewStatus(framework.Unschedulable, "cannot align pod"))
+	dl.record(pod, "node3", framework.NewStatus(framework.Unschedulable, "cannot align container"))
+
+	// the decision on node1 is the oldest, dropped once the log is full
+	expected := []filterDecision{
+		{Pod: "ns/pod", Node: "node2", Verdict: "Unschedulable", Reason: "cannot align pod"},
+		{Pod: "ns/pod", Node: "node3", Verdict: "Unschedulable", Reason: "cannot align container"},
+	}
+	if got := withoutTime(dl.decisions()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("decisions: got=%+v expected=%+v", got, expected)
+	}
+}
+
+func TestDecisionLogConcurrentRecords(t *testing.T) {
+	dl := newDecisionLog(16)
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod"}}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for idx := 0; idx < 100; idx++ {
+				dl.record(pod, fmt.Sprintf("node-%d-%d", worker, idx), nil)
+				dl.decisions()
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if got := dl.decisions(); len(got) != 16 {
+		t.Errorf("decisions kept: got=%d expected=16", len(got))
+	}
+}
+
+func TestDecisionLogFilter(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := &TopologyMatch{
+		nrtCache:    nrtcache.NewPassthrough(fakeClient),
+		decisionLog: newDecisionLog(10),
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+	for _, cpus := range []string{"1", "3"} {
+		pod := makePodByResourceList(&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpus),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		})
+		pod.Name = "pod-" + cpus
+		tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+	}
+
+	data, err := json.Marshal(tm.decisionLog)
+	if err != nil {
+		t.Fatalf("cannot encode the decisions: %v", err)
+	}
+	var got []filterDecision
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("cannot decode the decisions: %v", err)
+	}
+	expected := []filterDecision{
+		{Pod: "pod-1", Node: "node1", Verdict: "Success"},
+		{Pod: "pod-3", Node: "node1", Verdict: "Unschedulable", Reason: "insufficient node resources: cpu"},
+	}
+	if got := withoutTime(got); !reflect.DeepEqual(got, expected) {
+		t.Errorf("decisions: got=%+v expected=%+v", got, expected)
+	}
+}
+
+func TestStartDecisionLog(t *testing.T) {
+	conf := &apiconfig.DecisionLog{Size: 10}
+	profile := "decision-log-test"
+	defer configz.Delete(decisionLogConfigzPrefix + profile)
+
+	tm := &TopologyMatch{}
+	if err := startDecisionLog(tm, conf, profile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tm.decisionLog == nil {
+		t.Fatalf("decision log not enabled")
+	}
+	// each profile needs its own name
+	if err := startDecisionLog(&TopologyMatch{}, conf, profile); err == nil {
+		t.Errorf("expected an error registering the decision log of the same profile twice")
+	}
+	other := "decision-log-test-other"
+	defer configz.Delete(decisionLogConfigzPrefix + other)
+	if err := startDecisionLog(&TopologyMatch{}, conf, other); err != nil {
+		t.Errorf("unexpected error for another profile: %v", err)
+	}
+}
+
+func withoutTime(decisions []filterDecision) []filterDecision {
+	for idx := range decisions {
+		decisions[idx].Time = time.Time{}
+	}
+	return decisions
+}
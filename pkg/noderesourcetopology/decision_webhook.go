@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// placementReview is the NUMA placement of a pod, as submitted to the decision webhook.
+type placementReview struct {
+	Pod                string         `json:"pod"`
+	Node               string         `json:"node"`
+	ContainerNUMANodes map[string]int `json:"containerNUMANodes"`
+	Policy             string         `json:"policy"`
+	Scope              string         `json:"scope"`
+}
+
+// placementVerdict is the response of the decision webhook.
+type placementVerdict struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// decisionWebhook submits the NUMA placements of the pods to an external endpoint which can veto them.
+type decisionWebhook struct {
+	url        string
+	client     *http.Client
+	failClosed bool
+}
+
+func newDecisionWebhook(conf *apiconfig.DecisionWebhook) *decisionWebhook {
+	klog.InfoS("Enabling the decision webhook", "url", conf.URL, "timeoutSeconds", conf.TimeoutSeconds, "failurePolicy", conf.FailurePolicy)
+	return &decisionWebhook{
+		url: conf.URL,
+		client: &http.Client{
+			Timeout: time.Duration(conf.TimeoutSeconds) * time.Second,
+		},
+		failClosed: conf.FailurePolicy == apiconfig.DecisionWebhookFail,
+	}
+}
+
+func (dw *decisionWebhook) review(ctx context.Context, review placementReview) (placementVerdict, error) {
+	var verdict placementVerdict
+	body, err := json.Marshal(review)
+	if err != nil {
+		return verdict, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dw.url, bytes.NewReader(body))
+	if err != nil {
+		return verdict, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := dw.client.Do(req)
+	if err != nil {
+		return verdict, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return verdict, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return verdict, fmt.Errorf("cannot decode the verdict: %w", err)
+	}
+	return verdict, nil
+}
+
+// reviewPlacement submits to the decision webhook, if any, the NUMA placement the filter chose for the pod on the reserved
+// node, and rejects the pod if the webhook vetoes it. The pods the filter made no NUMA alignment decision for are not submitted.
+// The errors and timeouts of the webhook reject the pod only with the "Fail" failure policy. Called in PreBind, which runs
+// in the binding cycle of the pod, so a slow endpoint delays the binding of the pod but not the scheduling of the others.
+func (tm *TopologyMatch) reviewPlacement(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if tm.decisionWebhook == nil {
+		return nil
+	}
+	placement, ok := storedPlacementState(cycleState, nodeName)
+	if !ok {
+		return nil
+	}
+	review := placementReview{
+		Pod:                klog.KObj(pod).String(),
+		Node:               nodeName,
+		ContainerNUMANodes: placement.containerNUMANodes,
+		Policy:             placement.topologyManager.Policy,
+		Scope:              placement.topologyManager.Scope,
+	}
+	verdict, err := tm.decisionWebhook.review(ctx, review)
+	if err != nil {
+		if !tm.decisionWebhook.failClosed {
+			klog.ErrorS(err, "cannot review the NUMA placement, allowing it", "pod", klog.KObj(pod), "node", nodeName)
+			return nil
+		}
+		klog.ErrorS(err, "cannot review the NUMA placement, rejecting it", "pod", klog.KObj(pod), "node", nodeName)
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("cannot review the NUMA placement: %v", err))
+	}
+	if !verdict.Allowed {
+		klog.V(2).InfoS("NUMA placement vetoed", "pod", klog.KObj(pod), "node", nodeName, "reason", verdict.Reason)
+		msg := "NUMA placement vetoed by the decision webhook"
+		if verdict.Reason != "" {
+			msg += ": " + verdict.Reason
+		}
+		return framework.NewStatus(framework.Unschedulable, msg)
+	}
+	klog.V(5).InfoS("NUMA placement allowed", "pod", klog.KObj(pod), "node", nodeName)
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestDecisionWebhook(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "webhook-node"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedReview := placementReview{
+		Pod:                "default/testpod",
+		Node:               nrt.Name,
+		ContainerNUMANodes: map[string]int{"cnt-1": 1, "cnt-2": 1},
+		Policy:             "single-numa-node",
+		Scope:              "pod",
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		failClosed bool
+		skipFilter bool
+		expected   framework.Code
+		message    string
+		submitted  bool
+	}{
+		{
+			name: "approved",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"allowed": true}`))
+			},
+			expected:  framework.Success,
+			submitted: true,
+		},
+		{
+			name: "vetoed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"allowed": false, "reason": "NUMA node 1 is reserved"}`))
+			},
+			expected:  framework.Unschedulable,
+			message:   "NUMA node 1 is reserved",
+			submitted: true,
+		},
+		{
+			name: "failing webhook, fail open",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			},
+			expected:  framework.Success,
+			submitted: true,
+		},
+		{
+			name: "failing webhook, fail closed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			},
+			failClosed: true,
+			expected:   framework.Unschedulable,
+			message:    "cannot review the NUMA placement",
+			submitted:  true,
+		},
+		{
+			name: "timed out webhook, fail closed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(500 * time.Millisecond)
+				w.Write([]byte(`{"allowed": true}`))
+			},
+			failClosed: true,
+			expected:   framework.Unschedulable,
+			message:    "cannot review the NUMA placement",
+			submitted:  true,
+		},
+		{
+			name: "no alignment decision",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"allowed": false}`))
+			},
+			skipFilter: true,
+			expected:   framework.Success,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviews := make(chan placementReview, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var review placementReview
+				if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+					t.Errorf("cannot decode the review: %v", err)
+				}
+				reviews <- review
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			tm := &TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+				decisionWebhook: &decisionWebhook{
+					url:        server.URL,
+					client:     &http.Client{Timeout: 100 * time.Millisecond},
+					failClosed: tt.failClosed,
+				},
+			}
+			pod := makePod("testpod", withMultiContainers(parseContainerRes([]map[string]string{
+				{cpu: "2", memory: "1Gi"},
+				{cpu: "2", memory: "1Gi"},
+			})))
+			pod.Namespace = "default"
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			state := framework.NewCycleState()
+			if !tt.skipFilter {
+				if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+					t.Fatalf("unexpected filter status: %v", status)
+				}
+			}
+
+			status := tm.PreBind(context.Background(), state, pod, nrt.Name)
+			if status.Code() != tt.expected {
+				t.Errorf("unexpected permit status: %v, expected code %v", status, tt.expected)
+			}
+			if !strings.Contains(status.Message(), tt.message) {
+				t.Errorf("unexpected permit message %q, expected to contain %q", status.Message(), tt.message)
+			}
+			if !tt.submitted {
+				if len(reviews) != 0 {
+					t.Errorf("unexpected review: %+v", <-reviews)
+				}
+				return
+			}
+			if review := <-reviews; !reflect.DeepEqual(review, expectedReview) {
+				t.Errorf("unexpected review: %+v, expected %+v", review, expectedReview)
+			}
+		})
+	}
+}
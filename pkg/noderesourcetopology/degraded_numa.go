@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sort"
+	"strconv"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// ZoneAttributeDegradation is the name of the zone attribute holding the degradation of the hardware of a NUMA node,
+// from 0 for healthy to 100 for unusable, e.g. raised by the node agent when the NUMA node reports ECC errors.
+const ZoneAttributeDegradation = "numaDegradation"
+
+// numaNodeDegradations maps the NUMA nodes to the degradation reported by their zone. The NUMA nodes not reporting
+// a valid degradation are omitted. Returns nil if no NUMA node reports it.
+func numaNodeDegradations(zones topologyv1alpha2.ZoneList) map[int]int64 {
+	var degradations map[int]int64
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		numaID, err := getID(zone.Name)
+		if err != nil {
+			continue
+		}
+		for _, attr := range zone.Attributes {
+			if attr.Name != ZoneAttributeDegradation {
+				continue
+			}
+			degradation, err := strconv.ParseInt(attr.Value, 10, 64)
+			if err != nil || degradation < 0 || degradation > 100 {
+				klog.V(2).InfoS("ignoring malformed zone attribute", "zone", zone.Name, "attribute", ZoneAttributeDegradation, "value", attr.Value)
+				break
+			}
+			if degradations == nil {
+				degradations = make(map[int]int64)
+			}
+			degradations[numaID] = degradation
+			break
+		}
+	}
+	return degradations
+}
+
+// excludeDegradedNUMANodes adds to the excluded NUMA nodes the ones whose degradation reaches the threshold.
+// A zero threshold excludes none.
+func excludeDegradedNUMANodes(excluded map[int]string, zones topologyv1alpha2.ZoneList, threshold int64) map[int]string {
+	if threshold == 0 {
+		return excluded
+	}
+	for numaID, degradation := range numaNodeDegradations(zones) {
+		if degradation < threshold {
+			continue
+		}
+		if _, ok := excluded[numaID]; ok {
+			continue
+		}
+		if excluded == nil {
+			excluded = make(map[int]string)
+		}
+		excluded[numaID] = "degraded"
+		klog.V(5).InfoS("excluding degraded NUMA node", "NUMA", numaID, "degradation", degradation)
+	}
+	return excluded
+}
+
+// degradedNUMAComponent scores the health of the NUMA node expected to host the pod, the lowest NUMA ID fitting it like
+// the kubelet chooses. It doesn't apply to the nodes none of whose NUMA nodes report their degradation, nor to the pods
+// fitting on none of them.
+func (tm *TopologyMatch) degradedNUMAComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	degradations := numaNodeDegradations(zones)
+	if degradations == nil {
+		return 0, false, nil
+	}
+	numaNodes := createNUMANodeList(zones)
+	sort.Slice(numaNodes, func(i, j int) bool {
+		return numaNodes[i].NUMAID < numaNodes[j].NUMAID
+	})
+	requests := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	for _, numaNode := range numaNodes {
+		degradation := degradations[numaNode.NUMAID]
+		if tm.degradedNUMAThreshold > 0 && degradation >= tm.degradedNUMAThreshold {
+			continue
+		}
+		if !numaFitsRequests(requests, numaNode.Resources) {
+			continue
+		}
+		klog.V(6).InfoS("degraded NUMA", "pod", klog.KObj(pod), "node", nodeName, "NUMA", numaNode.NUMAID, "degradation", degradation)
+		return framework.MaxNodeScore * (100 - degradation) / 100, true, nil
+	}
+	return 0, false, nil
+}
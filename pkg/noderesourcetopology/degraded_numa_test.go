@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func withDegradation(zone topologyv1alpha2.Zone, degradation string) topologyv1alpha2.Zone {
+	zone.Attributes = append(zone.Attributes, topologyv1alpha2.AttributeInfo{Name: ZoneAttributeDegradation, Value: degradation})
+	return zone
+}
+
+func TestScoreDegradedNUMA(t *testing.T) {
+	nodes := map[string]topologyv1alpha2.ZoneList{
+		"healthy": {
+			makeSocketZone("node-0", "", "8", "8"),
+			makeSocketZone("node-1", "", "8", "8"),
+		},
+		"degraded-first": {
+			withDegradation(makeSocketZone("node-0", "", "8", "8"), "80"),
+			makeSocketZone("node-1", "", "8", "8"),
+		},
+		"degraded-last": {
+			makeSocketZone("node-0", "", "8", "8"),
+			withDegradation(makeSocketZone("node-1", "", "8", "8"), "80"),
+		},
+		"malformed": {
+			withDegradation(makeSocketZone("node-0", "", "8", "8"), "severe"),
+			makeSocketZone("node-1", "", "8", "8"),
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for nodeName, zones := range nodes {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: nodeName},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            zones,
+		}
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		weight    int64
+		threshold int64
+		expected  nodeToScoreMap
+	}{
+		{
+			name:     "disabled",
+			expected: nodeToScoreMap{"healthy": 9, "degraded-first": 9, "degraded-last": 9, "malformed": 9},
+		},
+		{
+			name:     "degraded NUMA node expected to host the pod",
+			weight:   100,
+			expected: nodeToScoreMap{"healthy": 9, "degraded-first": 20, "degraded-last": 100, "malformed": 9},
+		},
+		{
+			name:     "blended",
+			weight:   50,
+			expected: nodeToScoreMap{"healthy": 9, "degraded-first": 14, "degraded-last": 54, "malformed": 9},
+		},
+		{
+			name:      "degraded NUMA node excluded",
+			weight:    100,
+			threshold: 50,
+			expected:  nodeToScoreMap{"healthy": 9, "degraded-first": 100, "degraded-last": 100, "malformed": 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			})
+			tm := &TopologyMatch{
+				nrtCache:              nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc:     mostAllocatedScoreStrategy,
+				scoreStrategyType:     apiconfig.MostAllocated,
+				degradedNUMAWeight:    tt.weight,
+				degradedNUMAThreshold: tt.threshold,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterDegradedNUMA(t *testing.T) {
+	// only the NUMA node 0, severely degraded, can host the pod
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "degraded"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			withDegradation(makeSocketZone("node-0", "", "8", "8"), "90"),
+			makeSocketZone("node-1", "", "8", "2"),
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		threshold int64
+		expected  framework.Code
+	}{
+		{
+			name:     "not strict",
+			expected: framework.Success,
+		},
+		{
+			name:      "degradation below the threshold",
+			threshold: 95,
+			expected:  framework.Success,
+		},
+		{
+			name:      "degradation reaching the threshold",
+			threshold: 90,
+			expected:  framework.Unschedulable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache:              nrtcache.NewPassthrough(fakeClient),
+				degradedNUMAThreshold: tt.threshold,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			})
+			status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if status.Code() != tt.expected {
+				t.Errorf("unexpected filter status: %v, expected code %v", status, tt.expected)
+			}
+		})
+	}
+}
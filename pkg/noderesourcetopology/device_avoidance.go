@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// deviceAvoidancePenaltyFactor divides the score of the nodes on which the pod would take
+// a NUMA node which should be kept available for the pods requesting its devices.
+const deviceAvoidancePenaltyFactor = 2
+
+func resourceNames(names []string) []v1.ResourceName {
+	if len(names) == 0 {
+		return nil
+	}
+	resNames := make([]v1.ResourceName, 0, len(names))
+	for _, name := range names {
+		resNames = append(resNames, v1.ResourceName(name))
+	}
+	return resNames
+}
+
+// applyDeviceAvoidance penalizes the score if the pod does not request any of the device avoidance
+// resources, and all the NUMA nodes it fits in have some of these resources available.
+// If at least one of the fitting NUMA nodes has none of these resources available, the pod can be
+// placed without taking devices away from the pods which need them, so the score is left unchanged.
+func (tm *TopologyMatch) applyDeviceAvoidance(pod *v1.Pod, zones topologyv1alpha2.ZoneList, score int64) int64 {
+	if len(tm.deviceAvoidanceResources) == 0 {
+		return score
+	}
+	requests := util.GetPodEffectiveRequest(pod)
+	for _, resName := range tm.deviceAvoidanceResources {
+		if quantity, ok := requests[resName]; ok && !quantity.IsZero() {
+			return score
+		}
+	}
+
+	fitting := 0
+	for _, numa := range createNUMANodeList(zones) {
+		if !numaFitsRequests(requests, numa.Resources) {
+			continue
+		}
+		if !tm.hasDeviceAvoidanceResources(numa.Resources) {
+			return score
+		}
+		fitting++
+	}
+	if fitting == 0 {
+		return score
+	}
+	klog.V(6).InfoS("pod fits only on NUMA nodes with devices available", "pod", klog.KObj(pod), "numaNodes", fitting)
+	return score / deviceAvoidancePenaltyFactor
+}
+
+func (tm *TopologyMatch) hasDeviceAvoidanceResources(resources v1.ResourceList) bool {
+	for _, resName := range tm.deviceAvoidanceResources {
+		if quantity, ok := resources[resName]; ok && quantity.Sign() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func numaFitsRequests(requests, available v1.ResourceList) bool {
+	for resName, quantity := range requests {
+		if quantity.IsZero() {
+			continue
+		}
+		numaQuantity, ok := available[resName]
+		if !ok || numaQuantity.Cmp(quantity) < 0 {
+			return false
+		}
+	}
+	return true
+}
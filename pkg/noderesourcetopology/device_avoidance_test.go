@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestDeviceAvoidanceScore(t *testing.T) {
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			// plenty of CPUs, but on the NUMA nodes having the GPUs
+			ObjectMeta:       metav1.ObjectMeta{Name: "gpu-node"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "16", "16"),
+						MakeTopologyResInfo(memory, "32Gi", "32Gi"),
+						MakeTopologyResInfo(gpu, "2", "2"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "16", "16"),
+						MakeTopologyResInfo(memory, "32Gi", "32Gi"),
+						MakeTopologyResInfo(gpu, "2", "1"),
+					},
+				},
+			},
+		},
+		{
+			// fewer CPUs, but a NUMA node without GPUs
+			ObjectMeta:       metav1.ObjectMeta{Name: "mixed-node"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "32Gi", "32Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "32Gi", "32Gi"),
+						MakeTopologyResInfo(gpu, "2", "2"),
+					},
+				},
+			},
+		},
+		{
+			// the GPUs are all taken, nothing to keep available
+			ObjectMeta:       metav1.ObjectMeta{Name: "busy-gpu-node"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "16", "16"),
+						MakeTopologyResInfo(memory, "32Gi", "32Gi"),
+						MakeTopologyResInfo(gpu, "2", "0"),
+					},
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cpuPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+	burstableCPUPod := cpuPod.DeepCopy()
+	burstableCPUPod.Spec.Containers[0].Resources.Limits = nil
+	gpuPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+		gpu:               resource.MustParse("1"),
+	})
+
+	tests := []struct {
+		name                     string
+		pod                      *v1.Pod
+		deviceAvoidanceResources []v1.ResourceName
+		expected                 nodeToScoreMap
+	}{
+		{
+			name: "disabled",
+			pod:  cpuPod,
+			// pod fits in NUMA nodes with 16, 8 and 16 CPUs, memory: (100 - 4/32 * 100)
+			expected: nodeToScoreMap{"gpu-node": 81, "mixed-node": 68, "busy-gpu-node": 81},
+		},
+		{
+			name:                     "CPU-only pod steered away from GPU NUMA nodes",
+			pod:                      cpuPod,
+			deviceAvoidanceResources: []v1.ResourceName{gpu},
+			expected:                 nodeToScoreMap{"gpu-node": 40, "mixed-node": 68, "busy-gpu-node": 81},
+		},
+		{
+			name:     "burstable CPU-only pod, disabled",
+			pod:      burstableCPUPod,
+			expected: nodeToScoreMap{"gpu-node": 100, "mixed-node": 100, "busy-gpu-node": 100},
+		},
+		{
+			name:                     "burstable CPU-only pod steered away from GPU NUMA nodes",
+			pod:                      burstableCPUPod,
+			deviceAvoidanceResources: []v1.ResourceName{gpu},
+			expected:                 nodeToScoreMap{"gpu-node": 50, "mixed-node": 100, "busy-gpu-node": 100},
+		},
+		{
+			name:     "GPU pod, disabled",
+			pod:      gpuPod,
+			expected: nodeToScoreMap{"gpu-node": 54, "mixed-node": 45},
+		},
+		{
+			name:                     "GPU pod not penalized",
+			pod:                      gpuPod,
+			deviceAvoidanceResources: []v1.ResourceName{gpu},
+			// same as with the option disabled
+			expected: nodeToScoreMap{"gpu-node": 54, "mixed-node": 45},
+		},
+		{
+			name:                     "unrelated resource",
+			pod:                      cpuPod,
+			deviceAvoidanceResources: []v1.ResourceName{nicResourceName},
+			expected:                 nodeToScoreMap{"gpu-node": 81, "mixed-node": 68, "busy-gpu-node": 81},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache:                 nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc:        leastAllocatedScoreStrategy,
+				scoreStrategyType:        apiconfig.LeastAllocated,
+				deviceAvoidanceResources: tt.deviceAvoidanceResources,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), tt.pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// deviceCPUBalanceWeight returns the weight of the device-to-cpu balance, zero if disabled.
+func (tm *TopologyMatch) deviceCPUBalanceWeight() int64 {
+	if tm.deviceCPUBalance == nil {
+		return 0
+	}
+	return tm.deviceCPUBalance.Weight
+}
+
+// deviceCPUBalanceComponent scores the device-to-cpu balance of the most balanced NUMA node of the node fitting the pod.
+// It doesn't apply to the pods fitting in no single NUMA node.
+func (tm *TopologyMatch) deviceCPUBalanceComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	requests := util.GetPodEffectiveRequest(pod)
+	balance := int64(-1)
+	for _, numa := range createNUMANodeList(zones) {
+		if !numaFitsRequests(requests, numa.Resources) {
+			continue
+		}
+		if numaBalance := deviceCPUBalanceScore(requests, numa.Resources, tm.deviceCPUBalance); numaBalance > balance {
+			balance = numaBalance
+		}
+	}
+	return balance, balance >= 0, nil
+}
+
+// deviceCPUBalanceScore scores the share of the devices left on a NUMA node once the pod is placed which can
+// still be used, given the cpus left alongside them. A NUMA node left without devices has none to strand.
+func deviceCPUBalanceScore(requests, available v1.ResourceList, balance *apiconfig.DeviceCPUBalance) int64 {
+	devices := available[v1.ResourceName(balance.Resource)]
+	devices.Sub(requests[v1.ResourceName(balance.Resource)])
+	remainingDevices := devices.Value()
+	if remainingDevices <= 0 {
+		return framework.MaxNodeScore
+	}
+
+	cpus := available[v1.ResourceCPU]
+	cpus.Sub(requests[v1.ResourceCPU])
+	usableDevices := cpus.MilliValue() / (balance.CPUsPerDevice * 1000)
+	if usableDevices <= 0 {
+		return 0
+	}
+	if usableDevices >= remainingDevices {
+		return framework.MaxNodeScore
+	}
+	return framework.MaxNodeScore * usableDevices / remainingDevices
+}
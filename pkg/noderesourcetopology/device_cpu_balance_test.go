@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestDeviceCPUBalanceScore(t *testing.T) {
+	balance := &apiconfig.DeviceCPUBalance{
+		Resource:      vendorGPU,
+		CPUsPerDevice: 2,
+		Weight:        100,
+	}
+	requests := v1.ResourceList{
+		v1.ResourceCPU:             resource.MustParse("2"),
+		v1.ResourceName(vendorGPU): resource.MustParse("1"),
+	}
+	tests := []struct {
+		name      string
+		available v1.ResourceList
+		expected  int64
+	}{
+		{
+			name: "last device",
+			available: v1.ResourceList{
+				v1.ResourceCPU:             resource.MustParse("2"),
+				v1.ResourceName(vendorGPU): resource.MustParse("1"),
+			},
+			expected: 100,
+		},
+		{
+			name: "enough cpus for the devices left",
+			available: v1.ResourceList{
+				v1.ResourceCPU:             resource.MustParse("8"),
+				v1.ResourceName(vendorGPU): resource.MustParse("4"),
+			},
+			expected: 100,
+		},
+		{
+			name: "cpus for one of the devices left",
+			available: v1.ResourceList{
+				v1.ResourceCPU:             resource.MustParse("5500m"),
+				v1.ResourceName(vendorGPU): resource.MustParse("4"),
+			},
+			// 3500m cpus left can serve one device only
+			expected: 33,
+		},
+		{
+			name: "cpus exhausted",
+			available: v1.ResourceList{
+				v1.ResourceCPU:             resource.MustParse("3"),
+				v1.ResourceName(vendorGPU): resource.MustParse("4"),
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := deviceCPUBalanceScore(requests, tt.available, balance)
+			if score != tt.expected {
+				t.Errorf("score=%d expected=%d", score, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeviceCPUBalanceBlendedScore(t *testing.T) {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	nodes := map[string]string{
+		"cpu-starved": "4",
+		"balanced":    "16",
+	}
+	for nodeName, cpus := range nodes {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: nodeName},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "16", cpus),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+						MakeTopologyResInfo(vendorGPU, "4", "4"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "16", "16"),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+			},
+		}
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:             resource.MustParse("2"),
+		v1.ResourceMemory:          resource.MustParse("4Gi"),
+		v1.ResourceName(vendorGPU): resource.MustParse("1"),
+	})
+
+	tests := []struct {
+		name     string
+		balance  *apiconfig.DeviceCPUBalance
+		expected nodeToScoreMap
+	}{
+		{
+			name:     "disabled",
+			expected: nodeToScoreMap{"cpu-starved": 12, "balanced": 12},
+		},
+		{
+			name: "cpu-starved device NUMA node penalized",
+			balance: &apiconfig.DeviceCPUBalance{
+				Resource:      vendorGPU,
+				CPUsPerDevice: 2,
+				Weight:        50,
+			},
+			// balance scores: 33 and 100
+			expected: nodeToScoreMap{"cpu-starved": 22, "balanced": 56},
+		},
+		{
+			name: "balance only",
+			balance: &apiconfig.DeviceCPUBalance{
+				Resource:      vendorGPU,
+				CPUsPerDevice: 2,
+				Weight:        100,
+			},
+			expected: nodeToScoreMap{"cpu-starved": 33, "balanced": 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc: mostAllocatedScoreStrategy,
+				scoreStrategyType: apiconfig.MostAllocated,
+				deviceCPUBalance:  tt.balance,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
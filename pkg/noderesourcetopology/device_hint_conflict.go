@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// deviceHintConflictComponent scores the agreement between the device hints and the NUMA fit of the pod. It doesn't apply
+// to the pods requesting no device with NUMA affinity, nor to the nodes none of whose NUMA nodes can provide the devices.
+func (tm *TopologyMatch) deviceHintConflictComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	agreement, ok := deviceHintAgreementScore(pod, zones)
+	return agreement, ok, nil
+}
+
+// deviceHintAgreementScore scores the share of the NUMA nodes able to provide the devices the pod requests which can
+// also host the whole pod. The device manager generates its hints from the devices only, so the more NUMA nodes it may
+// hint without fitting the other resources of the pod, the more likely the hints merged by the topology manager conflict
+// with the NUMA node the scheduler expects, and the admission fails. The devices are the non-native resources.
+func deviceHintAgreementScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, bool) {
+	numaNodes := createNUMANodeList(zones)
+	requests := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	devices := v1.ResourceList{}
+	for resName, quantity := range requests {
+		if !v1helper.IsNativeResource(resName) && !quantity.IsZero() {
+			devices[resName] = quantity
+		}
+	}
+	if len(devices) == 0 {
+		return 0, false
+	}
+
+	hinted, agreeing := int64(0), int64(0)
+	for _, numaNode := range numaNodes {
+		if !numaFitsRequests(devices, numaNode.Resources) {
+			continue
+		}
+		hinted++
+		if numaFitsRequests(requests, numaNode.Resources) {
+			agreeing++
+		}
+	}
+	if hinted == 0 {
+		return 0, false
+	}
+	return framework.MaxNodeScore * agreeing / hinted, true
+}
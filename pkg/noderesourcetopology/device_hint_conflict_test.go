@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makeDeviceZone(numaID, cpuAvailable, nicAvailable string) topologyv1alpha2.Zone {
+	return topologyv1alpha2.Zone{
+		Name: numaID,
+		Type: "Node",
+		Resources: topologyv1alpha2.ResourceInfoList{
+			MakeTopologyResInfo(cpu, "8", cpuAvailable),
+			MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+			MakeTopologyResInfo(nicResourceName, "2", nicAvailable),
+		},
+	}
+}
+
+func TestDeviceHintAgreementScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		zones    topologyv1alpha2.ZoneList
+		devices  bool
+		expected int64
+		ok       bool
+	}{
+		{
+			name: "pod requests no device",
+			zones: topologyv1alpha2.ZoneList{
+				makeDeviceZone("node-0", "8", "2"),
+				makeDeviceZone("node-1", "8", "2"),
+			},
+		},
+		{
+			name:    "no NUMA node provides the devices",
+			devices: true,
+			zones: topologyv1alpha2.ZoneList{
+				makeDeviceZone("node-0", "8", "0"),
+				makeDeviceZone("node-1", "8", "0"),
+			},
+		},
+		{
+			name:    "devices available where the pod fits",
+			devices: true,
+			zones: topologyv1alpha2.ZoneList{
+				makeDeviceZone("node-0", "8", "2"),
+				makeDeviceZone("node-1", "8", "0"),
+			},
+			expected: 100,
+			ok:       true,
+		},
+		{
+			name:    "devices also available where the pod does not fit",
+			devices: true,
+			zones: topologyv1alpha2.ZoneList{
+				makeDeviceZone("node-0", "2", "2"),
+				makeDeviceZone("node-1", "8", "2"),
+			},
+			expected: 50,
+			ok:       true,
+		},
+		{
+			name:    "devices available only where the pod does not fit",
+			devices: true,
+			zones: topologyv1alpha2.ZoneList{
+				makeDeviceZone("node-0", "2", "2"),
+				makeDeviceZone("node-1", "8", "0"),
+			},
+			expected: 0,
+			ok:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			}
+			if tt.devices {
+				requests[nicResourceName] = resource.MustParse("1")
+			}
+			got, ok := deviceHintAgreementScore(makePodByResourceList(&requests), tt.zones)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("score=%d,%v expected=%d,%v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestScoreDeviceHintConflict(t *testing.T) {
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			// the NUMA node with most devices left lacks the cpus for the pod
+			ObjectMeta:       metav1.ObjectMeta{Name: "constrained"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				makeDeviceZone("node-0", "2", "2"),
+				makeDeviceZone("node-1", "8", "1"),
+			},
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "consistent"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				makeDeviceZone("node-0", "8", "1"),
+				makeDeviceZone("node-1", "8", "1"),
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		weight   int64
+		expected nodeToScoreMap
+	}{
+		{
+			name:     "disabled",
+			expected: nodeToScoreMap{"constrained": 33, "consistent": 33},
+		},
+		{
+			name:   "device hint conflicts penalized",
+			weight: 50,
+			// constrained: (33 + 50) / 2, consistent: (33 + 100) / 2
+			expected: nodeToScoreMap{"constrained": 41, "consistent": 66},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+				nicResourceName:   resource.MustParse("1"),
+			})
+			tm := &TopologyMatch{
+				nrtCache:                 nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc:        leastAllocatedScoreStrategy,
+				scoreStrategyType:        apiconfig.LeastAllocated,
+				deviceHintConflictWeight: tt.weight,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const noEnforcingNodesReason = "no topology-enforcing nodes available"
+
+// PreFilter rejects upfront, if enabled, the pods requiring the NUMA alignment of a resource they request when no node
+// of the cluster enforces a topology manager policy other than none, since no node would pass the filter anyway.
+func (tm *TopologyMatch) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	if !tm.failFastNoEnforcing {
+		return nil, nil
+	}
+	if tm.exemptNamespaces.Has(pod.Namespace) {
+		return nil, nil
+	}
+	prefs := tm.numaPreferencesForPod(pod)
+	if prefs.requiresMemorySpread() {
+		return nil, nil
+	}
+	resName, ok := requestedRequiredResource(pod, prefs)
+	if !ok {
+		return nil, nil
+	}
+	nodeInfos, err := tm.handle.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, framework.NewStatus(framework.Error, fmt.Sprintf("listing the nodes from Snapshot: %v", err))
+	}
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Node() == nil {
+			continue
+		}
+		if tm.mayEnforceTopology(ctx, nodeInfo, pod) {
+			return nil, nil
+		}
+	}
+	klog.V(2).InfoS("pod requires the NUMA alignment, no node provides it", "pod", klog.KObj(pod), "resource", resName, "nodes", len(nodeInfos))
+	return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("%s: cannot align required resource %s", noEnforcingNodesReason, resName))
+}
+
+// PreFilterExtensions returns nil, the outcome of PreFilter doesn't depend on the pods running on the nodes.
+func (tm *TopologyMatch) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// mayEnforceTopology returns true unless the node is known to use the none topology manager policy, or to be handled
+// like it, as the filter would. The nodes waiting for a resync of their NRT data are assumed to enforce a policy.
+func (tm *TopologyMatch) mayEnforceTopology(ctx context.Context, nodeInfo *framework.NodeInfo, pod *v1.Pod) bool {
+	nodeTopology, ok := tm.nrtCache.GetCachedNRTCopy(ctx, nodeInfo.Node().Name, pod)
+	if !ok {
+		return true
+	}
+	if nodeTopology == nil {
+		if tm.defaultPolicyWhenMissing == nil {
+			return false
+		}
+		nodeTopology = pseudoNodeResourceTopology(nodeInfo, tm.defaultPolicyWhenMissing)
+	}
+	if tm.isMisconfigured(nodeTopology) {
+		return false
+	}
+	return tm.topologyManagerConfig(nodeTopology).Policy != kubeletconfig.NoneTopologyManagerPolicy
+}
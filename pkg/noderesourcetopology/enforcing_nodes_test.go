@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestPreFilterFailFastWithoutEnforcingNodes(t *testing.T) {
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	noneCluster := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("none-1", topologyv1alpha2.None),
+		makeNRT("none-2", topologyv1alpha2.None),
+	}
+	mixedCluster := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("mixed-none", topologyv1alpha2.None),
+		makeNRT("mixed-enforcing", topologyv1alpha2.SingleNUMANodePodLevel),
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range append(noneCluster, mixedCluster...) {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the node without NRT object is not handled by the filter, unless a default policy is assumed
+	missingNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing"},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+
+	requiringPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	requiringPod.Annotations = map[string]string{AnnotationRequiredResources: cpu}
+	plainPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+
+	tests := []struct {
+		name          string
+		disabled      bool
+		nrts          []*topologyv1alpha2.NodeResourceTopology
+		extraNodes    []*v1.Node
+		defaultPolicy *apiconfig.TopologyManagerOverlay
+		pod           *v1.Pod
+		expectedCode  framework.Code
+	}{
+		{
+			name:         "all none cluster",
+			nrts:         noneCluster,
+			pod:          requiringPod,
+			expectedCode: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:         "all none cluster, node without NRT object",
+			nrts:         noneCluster,
+			extraNodes:   []*v1.Node{missingNode},
+			pod:          requiringPod,
+			expectedCode: framework.UnschedulableAndUnresolvable,
+		},
+		{
+			name:          "all none cluster, node without NRT object assumed enforcing",
+			nrts:          noneCluster,
+			extraNodes:    []*v1.Node{missingNode},
+			defaultPolicy: &apiconfig.TopologyManagerOverlay{Policy: "single-numa-node", Scope: "pod"},
+			pod:           requiringPod,
+			expectedCode:  framework.Success,
+		},
+		{
+			name:         "all none cluster, disabled",
+			disabled:     true,
+			nrts:         noneCluster,
+			pod:          requiringPod,
+			expectedCode: framework.Success,
+		},
+		{
+			name:         "all none cluster, pod not requiring the alignment",
+			nrts:         noneCluster,
+			pod:          plainPod,
+			expectedCode: framework.Success,
+		},
+		{
+			name:         "enforcing node available",
+			nrts:         mixedCluster,
+			pod:          requiringPod,
+			expectedCode: framework.Success,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []*v1.Node
+			for _, nrt := range tt.nrts {
+				nodes = append(nodes, makeNodeFromNodeResourceTopology(nrt))
+			}
+			nodes = append(nodes, tt.extraNodes...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			fh, err := st.NewFramework(
+				ctx,
+				[]st.RegisterPluginFunc{
+					st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+					st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				},
+				"default-scheduler",
+				frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(nil, nodes)),
+			)
+			if err != nil {
+				t.Fatalf("fail to create framework: %s", err)
+			}
+
+			tm := &TopologyMatch{
+				handle:                   fh,
+				nrtCache:                 nrtcache.NewPassthrough(fakeClient),
+				downgrades:               newBestEffortDowngrades(),
+				defaultPolicyWhenMissing: tt.defaultPolicy,
+				failFastNoEnforcing:      !tt.disabled,
+			}
+			_, status := tm.PreFilter(context.Background(), framework.NewCycleState(), tt.pod)
+			if status.Code() != tt.expectedCode {
+				t.Fatalf("unexpected status code %v, expected %v: %v", status.Code(), tt.expectedCode, status)
+			}
+			if tt.expectedCode == framework.Success {
+				return
+			}
+			if msg := status.Message(); !strings.Contains(msg, noEnforcingNodesReason) {
+				t.Errorf("unexpected status message %q", msg)
+			}
+		})
+	}
+}
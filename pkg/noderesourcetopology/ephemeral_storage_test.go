@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterNUMALocalEphemeralStorage(t *testing.T) {
+	ephemeralStorage := string(v1.ResourceEphemeralStorage)
+
+	// only the NUMA node 1 has enough NUMA-local ephemeral storage left for the pod
+	reported := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "reported"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(ephemeralStorage, "100Gi", "10Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(ephemeralStorage, "100Gi", "100Gi"),
+				},
+			},
+		},
+	}
+	notReported := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "not-reported"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{reported, notReported} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		nrt      *topologyv1alpha2.NodeResourceTopology
+		enabled  bool
+		required bool
+		expected int
+	}{
+		{
+			name:     "disabled",
+			nrt:      reported,
+			expected: 0,
+		},
+		{
+			name:     "disabled, alignment required by the pod",
+			nrt:      reported,
+			required: true,
+			expected: 1,
+		},
+		{
+			name:     "enabled",
+			nrt:      reported,
+			enabled:  true,
+			expected: 1,
+		},
+		{
+			name:     "enabled, not reported per NUMA node",
+			nrt:      notReported,
+			enabled:  true,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakePlacementRecorder{}
+			tm := &TopologyMatch{
+				nrtCache:             nrtcache.NewPassthrough(fakeClient),
+				numaEphemeralStorage: tt.enabled,
+			}
+			WithPlacementRecorder(recorder)(tm)
+
+			pod := makePod("testpod", withMultiContainers(parseContainerRes([]map[string]string{
+				{cpu: "2", memory: "1Gi", ephemeralStorage: "50Gi"},
+			})))
+			if tt.required {
+				pod.Annotations = map[string]string{AnnotationRequiredResources: ephemeralStorage}
+			}
+			node := makeNodeFromNodeResourceTopology(tt.nrt)
+			node.Status.Allocatable[v1.ResourceEphemeralStorage] = resource.MustParse("200Gi")
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			state := framework.NewCycleState()
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			if status := tm.Reserve(context.Background(), state, pod, tt.nrt.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected reserve status: %v", status)
+			}
+			expected := []recordedPlacement{
+				{podName: pod.Name, nodeName: tt.nrt.Name, containerNUMANodes: map[string]int{"cnt-1": tt.expected}},
+			}
+			if !reflect.DeepEqual(recorder.placements, expected) {
+				t.Errorf("placements=%v expected=%v", recorder.placements, expected)
+			}
+		})
+	}
+}
@@ -19,9 +19,15 @@ package noderesourcetopology
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2"
 	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
@@ -30,6 +36,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/resourcerequests"
 	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/stringify"
 	"sigs.k8s.io/scheduler-plugins/pkg/util"
@@ -39,63 +46,390 @@ import (
 // https://kubernetes.io/docs/tasks/administer-cluster/topology-manager/#known-limitations
 const highestNUMAID = 8
 
+var (
+	alignmentBitmaskEmptyTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "nrt_alignment_bitmask_empty_total",
+			Help:           "Number of times no NUMA node could align a pod, or a container, on a node, by the resource that left no candidate NUMA node.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+
+	bestEffortDowngradesTotal = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "noderesourcetopology_best_effort_downgrades_total",
+			Help:           "Number of pods downgraded to the best-effort NUMA alignment because no node could align them.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	registerFilterMetricsOnce sync.Once
+)
+
+// registerFilterMetrics registers the metrics updated by the filter, which, unlike the optional ones, are always enabled.
+func registerFilterMetrics() {
+	registerFilterMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(alignmentBitmaskEmptyTotal)
+		legacyregistry.MustRegister(bestEffortDowngradesTotal)
+		legacyregistry.MustRegister(invalidTopologyRejectionsTotal)
+	})
+}
+
 type PolicyHandler func(pod *v1.Pod, zoneMap topologyv1alpha2.ZoneList) *framework.Status
 
-func singleNUMAContainerLevelHandler(pod *v1.Pod, zones topologyv1alpha2.ZoneList, nodeInfo *framework.NodeInfo) *framework.Status {
+// filterInfo holds the node-specific data the filter handlers work on.
+type filterInfo struct {
+	nodeName        string // shortcut, used very often
+	node            *framework.NodeInfo
+	topologyManager TopologyManagerConfig
+	qos             v1.PodQOSClass
+	numaNodes       NUMANodeList
+	preferences     NUMAPreferences
+	rounding        resourceRounding
+	sharedDevices   sharedDevices
+	qosPolicy       qosResourcePolicy
+	// excludedNUMANodes maps the NUMA nodes the pod can't be placed on to the reason of the exclusion
+	excludedNUMANodes map[int]string
+	// alignMemoryToLimits is set if the memory alignment must be checked against the limits of the containers
+	alignMemoryToLimits bool
+	// containerCPUExclusivity is set if the CPU capacity must be checked for the guaranteed-like containers
+	containerCPUExclusivity bool
+	// fractionalCPUShared is set if the CPU capacity must not be checked for the containers requesting fractional CPUs
+	fractionalCPUShared bool
+	// wholeCPUs is set if the integer CPU requests must fit in the whole CPUs of the NUMA nodes
+	wholeCPUs bool
+	// burstFactorPercent, if set, is the percentage the CPU and memory requests are multiplied by
+	burstFactorPercent int64
+	// pcieGroups is set if the multi-device requests must fit in a single PCIe group
+	pcieGroups pcieGroups
+	// spanningResources are checked against, and consumed from, all the NUMA nodes the pod can use
+	spanningResources spanningResources
+	// nodeLevelDevices holds the free device instances the node reports only at node level, nil unless they can be used
+	nodeLevelDevices v1.ResourceList
+	// numaEphemeralStorage is set if the ephemeral-storage reported per NUMA node must be aligned
+	numaEphemeralStorage bool
+	// containerScopeSameNUMA is set if, at container scope, all the containers must share a single NUMA node
+	containerScopeSameNUMA bool
+	// initContainersSameNUMA is set if, at container scope, the init containers must share a NUMA node with the app containers
+	initContainersSameNUMA bool
+	// numaSockets maps the NUMA nodes to their socket, nil if the node doesn't report them
+	numaSockets map[int]string
+	// numaPowerHints maps the NUMA nodes to their power hint, nil if the hints are not used or the node doesn't report them
+	numaPowerHints map[int]int64
+	// chosenNUMANodes is filled by the handlers with the NUMA nodes the kubelet is expected to pick
+	chosenNUMANodes []int
+	// chosenNUMAReasons tells, for each of the chosenNUMANodes, why the NUMA node was picked among the feasible ones
+	chosenNUMAReasons []string
+	// numaChoiceReason is filled by resourcesAvailableInAnyNUMANodes with the reason of its last choice
+	numaChoiceReason string
+	// unalignedResource is filled by the handlers with the resource which last left no candidate NUMA node, if any
+	unalignedResource v1.ResourceName
+	// bestEffort is set if the kubelet admits the pod regardless of the alignment, which is then only computed
+	bestEffort bool
+	// bestEffortHint is filled by the best-effort handlers with the NUMA nodes the kubelet is expected to pick for the pod,
+	// the narrowest set of NUMA nodes fitting it if it can't be aligned, empty if it doesn't fit the NUMA nodes at all
+	bestEffortHint bm.BitMask
+	// unalignedContainers is filled by the best-effort handlers with the containers which can't be aligned
+	unalignedContainers []string
+	// maxCandidateNUMASets caps the sets of NUMA nodes evaluated by the best-effort handlers for the pods which can't be aligned
+	maxCandidateNUMASets int64
+}
+
+// containerAlignmentResources returns the resources of the container to be checked against, and subtracted from, the NUMA nodes.
+func (info *filterInfo) containerAlignmentResources(container *v1.Container) v1.ResourceList {
+	requests := info.withBurstFactor(container.Resources.Requests)
+	if info.hasSharedCPU(container) {
+		resources := requests.DeepCopy()
+		delete(resources, v1.ResourceCPU)
+		return resources
+	}
+	if !info.alignMemoryToLimits {
+		return requests
+	}
+	limit, ok := container.Resources.Limits[v1.ResourceMemory]
+	if !ok {
+		return requests
+	}
+	resources := requests.DeepCopy()
+	if resources == nil {
+		resources = v1.ResourceList{}
+	}
+	resources[v1.ResourceMemory] = limit
+	return resources
+}
+
+// hasSharedCPU returns true if the container requests a fractional amount of CPUs, which the static CPU manager policy
+// takes from the shared pool rather than allocating exclusively, so its CPUs don't need to be aligned.
+func (info *filterInfo) hasSharedCPU(container *v1.Container) bool {
+	if !info.fractionalCPUShared || info.preferences.requiresAlignment(v1.ResourceCPU) {
+		return false
+	}
+	request, ok := container.Resources.Requests[v1.ResourceCPU]
+	return ok && !isIntegerCPU(request)
+}
+
+// isIntegerCPU returns true if the CPU quantity is a whole number of CPUs, the only amounts the static CPU manager policy
+// allocates exclusive CPUs for.
+func isIntegerCPU(quantity resource.Quantity) bool {
+	return quantity.MilliValue()%1000 == 0
+}
+
+// wholeCPUs returns the CPU quantity truncated to its whole CPUs.
+func wholeCPUs(quantity resource.Quantity) resource.Quantity {
+	return *resource.NewQuantity(quantity.MilliValue()/1000, quantity.Format)
+}
+
+// withBurstFactor returns the requests with the CPU and memory multiplied by the burst factor, if any, rounding up.
+func (info *filterInfo) withBurstFactor(requests v1.ResourceList) v1.ResourceList {
+	if info.burstFactorPercent == 0 {
+		return requests
+	}
+	scaled := requests.DeepCopy()
+	if quantity, ok := requests[v1.ResourceCPU]; ok {
+		scaled[v1.ResourceCPU] = *resource.NewMilliQuantity(divideRoundingUp(quantity.MilliValue()*info.burstFactorPercent, 100), quantity.Format)
+	}
+	if quantity, ok := requests[v1.ResourceMemory]; ok {
+		scaled[v1.ResourceMemory] = *resource.NewQuantity(divideRoundingUp(quantity.Value()*info.burstFactorPercent, 100), quantity.Format)
+	}
+	return scaled
+}
+
+func divideRoundingUp(dividend, divisor int64) int64 {
+	return (dividend + divisor - 1) / divisor
+}
+
+// podAlignmentResources returns the resources of the pod to be checked against the NUMA nodes.
+func (info *filterInfo) podAlignmentResources(pod *v1.Pod) v1.ResourceList {
+	if !info.alignMemoryToLimits && !info.fractionalCPUShared && info.burstFactorPercent == 0 {
+		return util.GetPodEffectiveRequest(pod)
+	}
+	podCopy := pod.DeepCopy()
+	for i := range podCopy.Spec.InitContainers {
+		podCopy.Spec.InitContainers[i].Resources.Requests = info.containerAlignmentResources(&podCopy.Spec.InitContainers[i])
+	}
+	for i := range podCopy.Spec.Containers {
+		podCopy.Spec.Containers[i].Resources.Requests = info.containerAlignmentResources(&podCopy.Spec.Containers[i])
+	}
+	return util.GetPodEffectiveRequest(podCopy)
+}
+
+func singleNUMAContainerLevelHandler(pod *v1.Pod, info *filterInfo) *framework.Status {
 	klog.V(5).InfoS("Single NUMA node handler")
 
-	// prepare NUMANodes list from zoneMap
-	nodes := createNUMANodeList(zones)
-	qos := v1qos.GetPodQOS(pod)
+	if info.containerScopeSameNUMA {
+		return singleNUMAContainerLevelSameNUMAHandler(pod, info)
+	}
+	if info.initContainersSameNUMA && len(pod.Spec.InitContainers) > 0 {
+		return singleNUMAContainerLevelInitSameNUMAHandler(pod, info)
+	}
 
-	// Node() != nil already verified in Filter(), which is the only public entry point
-	logNumaNodes("container handler NUMA resources", nodeInfo.Node().Name, nodes)
+	logNumaNodes("container handler NUMA resources", info.nodeName, info.numaNodes)
+	return alignContainers(pod, info, info.excludedNUMANodes)
+}
 
+// alignContainers aligns each container of the pod on a NUMA node, consuming the resources of the NUMA nodes, like the
+// kubelet does at container scope. The init containers are not placed on the NUMA nodes in initExcludedNUMANodes.
+func alignContainers(pod *v1.Pod, info *filterInfo, initExcludedNUMANodes map[int]string) *framework.Status {
 	// the init containers are running SERIALLY and BEFORE the normal containers.
 	// https://kubernetes.io/docs/concepts/workloads/pods/init-containers/#understanding-init-containers
-	// therefore, we don't need to accumulate their resources together
+	// therefore, we don't need to accumulate their resources together.
+	// The exception are the restartable init containers (sidecars), which keep running along with
+	// all the containers started after them, so their resources must be accumulated.
+	// https://kubernetes.io/docs/concepts/workloads/pods/sidecar-containers/
+	excludedNUMANodes := info.excludedNUMANodes
+	info.excludedNUMANodes = initExcludedNUMANodes
 	for _, initContainer := range pod.Spec.InitContainers {
 		logID := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, initContainer.Name)
-		klog.V(6).InfoS("target resources", stringify.ResourceListToLoggable(logID, initContainer.Resources.Requests)...)
+		resources := info.containerAlignmentResources(&initContainer)
+		klog.V(6).InfoS("target resources", stringify.ResourceListToLoggable(logID, resources)...)
 
-		_, match := resourcesAvailableInAnyNUMANodes(logID, nodes, initContainer.Resources.Requests, qos, nodeInfo)
+		numaID, match := resourcesAvailableInAnyNUMANodes(logID, info, resources, info.hasExclusiveCPU(&initContainer))
+		if !match && info.bestEffort {
+			// the kubelet admits the container anyway, unaligned
+			info.unalignedContainers = append(info.unalignedContainers, initContainer.Name)
+			continue
+		}
 		if !match {
+			info.excludedNUMANodes = excludedNUMANodes
 			// we can't align init container, so definitely we can't align a pod
 			klog.V(2).InfoS("cannot align container", "name", initContainer.Name, "kind", "init")
-			return framework.NewStatus(framework.Unschedulable, "cannot align init container")
+			return info.cannotAlignStatus("cannot align init container", resources)
+		}
+		info.choose(numaID)
+
+		if isRestartableInitContainer(&initContainer) {
+			info.consume(numaID, sidecarConsumedResources(pod, resources))
 		}
 	}
+	info.excludedNUMANodes = excludedNUMANodes
 
 	for _, container := range pod.Spec.Containers {
 		logID := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container.Name)
-		klog.V(6).InfoS("target resources", stringify.ResourceListToLoggable(logID, container.Resources.Requests)...)
+		resources := info.containerAlignmentResources(&container)
+		klog.V(6).InfoS("target resources", stringify.ResourceListToLoggable(logID, resources)...)
 
-		numaID, match := resourcesAvailableInAnyNUMANodes(logID, nodes, container.Resources.Requests, qos, nodeInfo)
+		numaID, match := resourcesAvailableInAnyNUMANodes(logID, info, resources, info.hasExclusiveCPU(&container))
+		if !match && info.bestEffort {
+			// the kubelet admits the container anyway, unaligned
+			info.unalignedContainers = append(info.unalignedContainers, container.Name)
+			continue
+		}
 		if !match {
 			// we can't align container, so definitely we can't align a pod
 			klog.V(2).InfoS("cannot align container", "name", container.Name, "kind", "app")
-			return framework.NewStatus(framework.Unschedulable, "cannot align container")
+			return info.cannotAlignStatus("cannot align container", resources)
 		}
+		info.choose(numaID)
 
 		// subtract the resources requested by the container from the given NUMA.
 		// this is necessary, so we won't allocate the same resources for the upcoming containers
-		subtractFromNUMA(nodes, numaID, container)
+		info.consume(numaID, resources)
+	}
+	return nil
+}
+
+// singleNUMAContainerLevelInitSameNUMAHandler requires the init containers of the pod to be aligned on a NUMA node chosen
+// for its app containers. Unless the independent alignment already satisfies this, the NUMA nodes are tried in turn, lowest
+// ID first, as the one of all the init containers, aligning the app containers like the container handler does; the first
+// one also chosen for an app container wins.
+func singleNUMAContainerLevelInitSameNUMAHandler(pod *v1.Pod, info *filterInfo) *framework.Status {
+	logNumaNodes("container handler NUMA resources", info.nodeName, info.numaNodes)
+
+	trial := tryAlignContainers(pod, info, info.excludedNUMANodes)
+	if trial == nil {
+		// the init containers can't be aligned even independently
+		return alignContainers(pod, info, info.excludedNUMANodes)
+	}
+	if initSharesAppNUMANode(pod, trial.chosenNUMANodes) {
+		*info = *trial
+		return nil
+	}
+
+	numaIDs := make([]int, 0, len(info.numaNodes))
+	for _, numaNode := range info.numaNodes {
+		if _, excluded := info.excludedNUMANodes[numaNode.NUMAID]; !excluded {
+			numaIDs = append(numaIDs, numaNode.NUMAID)
+		}
+	}
+	sort.Ints(numaIDs)
+	for _, numaID := range numaIDs {
+		initExcluded := make(map[int]string, len(info.numaNodes))
+		for excludedID, reason := range info.excludedNUMANodes {
+			initExcluded[excludedID] = reason
+		}
+		initExcluded = excludeNonLocalNUMANodes(initExcluded, info.numaNodes, map[int]bool{numaID: true}, "not the NUMA node tried for the init containers")
+
+		trial = tryAlignContainers(pod, info, initExcluded)
+		if trial != nil && initSharesAppNUMANode(pod, trial.chosenNUMANodes) {
+			*info = *trial
+			return nil
+		}
+	}
+	klog.V(2).InfoS("cannot align init containers on the NUMA node of an app container", "name", pod.Name)
+	return framework.NewStatus(framework.Unschedulable, "cannot align init containers with the app containers")
+}
+
+// tryAlignContainers aligns the containers of the pod on a copy of the NUMA nodes, returning the resulting filter data,
+// or nil if the containers can't be aligned.
+func tryAlignContainers(pod *v1.Pod, info *filterInfo, initExcludedNUMANodes map[int]string) *filterInfo {
+	trial := *info
+	trial.numaNodes = copyNUMANodeList(info.numaNodes)
+	trial.chosenNUMANodes = nil
+	trial.chosenNUMAReasons = nil
+	if status := alignContainers(pod, &trial, initExcludedNUMANodes); status != nil {
+		return nil
+	}
+	return &trial
+}
+
+// initSharesAppNUMANode returns true if all the init containers, listed first in chosenNUMANodes, were aligned on
+// the same NUMA node, also chosen for an app container.
+func initSharesAppNUMANode(pod *v1.Pod, chosenNUMANodes []int) bool {
+	initNUMANodes := chosenNUMANodes[:len(pod.Spec.InitContainers)]
+	for _, numaID := range initNUMANodes {
+		if numaID != initNUMANodes[0] {
+			return false
+		}
+	}
+	for _, numaID := range chosenNUMANodes[len(pod.Spec.InitContainers):] {
+		if numaID == initNUMANodes[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// singleNUMAContainerLevelSameNUMAHandler requires all the containers of the pod to share a single NUMA node, which must fit
+// the sum of their requests like at pod scope. The chosen NUMA node is then recorded, and its resources consumed, for each
+// container like the container handler does.
+func singleNUMAContainerLevelSameNUMAHandler(pod *v1.Pod, info *filterInfo) *framework.Status {
+	resources := info.podAlignmentResources(pod)
+
+	logID := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	klog.V(6).InfoS("target resources", stringify.ResourceListToLoggable(logID, resources)...)
+
+	// conservatively check the CPU capacity of the whole pod if any of its containers needs exclusive CPUs
+	exclusiveCPU := false
+	for _, containers := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			exclusiveCPU = exclusiveCPU || info.hasExclusiveCPU(&containers[i])
+		}
+	}
+
+	numaID, match := resourcesAvailableInAnyNUMANodes(logID, info, resources, exclusiveCPU)
+	if !match {
+		klog.V(2).InfoS("cannot align containers on the same NUMA node", "name", pod.Name)
+		return info.cannotAlignStatus("cannot align containers on the same NUMA node", resources)
+	}
+
+	for _, initContainer := range pod.Spec.InitContainers {
+		info.choose(numaID)
+		if isRestartableInitContainer(&initContainer) {
+			info.consume(numaID, sidecarConsumedResources(pod, info.containerAlignmentResources(&initContainer)))
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		info.choose(numaID)
+		info.consume(numaID, info.containerAlignmentResources(&container))
 	}
 	return nil
 }
 
+// hasExclusiveCPU returns true if the CPU capacity must be checked for the container regardless of the pod QoS.
+// The QoS class is a pod-level property: a container whose requests equal its limits doesn't get exclusive CPUs
+// from the CPU manager unless the pod as a whole is guaranteed, so this is deliberately conservative, opt-in behavior.
+func (info *filterInfo) hasExclusiveCPU(container *v1.Container) bool {
+	if !info.containerCPUExclusivity || info.qos != v1.PodQOSBurstable {
+		return false
+	}
+	for _, resName := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		request, ok := container.Resources.Requests[resName]
+		if !ok {
+			return false
+		}
+		limit, ok := container.Resources.Limits[resName]
+		if !ok || request.Cmp(limit) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // resourcesAvailableInAnyNUMANodes checks for sufficient resource and return the NUMAID that would be selected by Kubelet.
-// this function requires NUMANodeList with properly populated NUMANode, NUMAID should be in range 0-63
-func resourcesAvailableInAnyNUMANodes(logID string, numaNodes NUMANodeList, resources v1.ResourceList, qos v1.PodQOSClass, nodeInfo *framework.NodeInfo) (int, bool) {
+// this function requires NUMANodeList with properly populated NUMANode, NUMAID should be in range 0-63.
+// If exclusiveCPU is set, the CPU capacity is checked as if the pod was guaranteed.
+func resourcesAvailableInAnyNUMANodes(logID string, info *filterInfo, resources v1.ResourceList, exclusiveCPU bool) (int, bool) {
 	numaID := highestNUMAID
 	bitmask := bm.NewEmptyBitMask()
 	// set all bits, each bit is a NUMA node, if resources couldn't be aligned
 	// on the NUMA node, bit should be unset
 	bitmask.Fill()
 
-	// Node() != nil already verified in Filter(), which is the only public entry point
-	nodeName := nodeInfo.Node().Name
-	nodeResources := util.ResourceList(nodeInfo.Allocatable)
+	nodeName := info.nodeName
+	nodeResources := util.ResourceList(info.node.Allocatable)
 
 	for resource, quantity := range resources {
 		if quantity.IsZero() {
@@ -112,18 +446,68 @@ func resourcesAvailableInAnyNUMANodes(logID string, numaNodes NUMANodeList, reso
 			return numaID, false
 		}
 
+		if !info.topologyManager.providesHints(resource) {
+			// the kubelet doesn't align this resource, so it can't constrain the NUMA nodes
+			klog.V(6).InfoS("resource not governed by any active resource manager", "logID", logID, "node", nodeName, "resource", resource)
+			continue
+		}
+
+		if info.preferences.excludesAlignment(resource) {
+			klog.V(6).InfoS("resource excluded from the NUMA alignment", "logID", logID, "node", nodeName, "resource", resource)
+			continue
+		}
+
+		if resource == v1.ResourceEphemeralStorage && !info.numaEphemeralStorage && !info.preferences.requiresAlignment(resource) {
+			// the kubelet doesn't align the ephemeral-storage, even if the NUMA nodes report it
+			klog.V(6).InfoS("ephemeral storage handled at node level", "logID", logID, "node", nodeName)
+			continue
+		}
+
 		// for each requested resource, calculate which NUMA slots are good fits, and then AND with the aggregated bitmask, IOW unset appropriate bit if we can't align resources, or set it
 		// obvious, bits which are not in the NUMA id's range would be unset
+		// resources the pod explicitly requires to be aligned are checked as if the pod was guaranteed,
+		// and so is memory when it must be aligned against the limits
+		required := info.preferences.requiresAlignment(resource) || (resource == v1.ResourceMemory && info.alignMemoryToLimits) ||
+			(resource == v1.ResourceCPU && exclusiveCPU)
+		qos := info.qos
+		if required {
+			qos = v1.PodQOSGuaranteed
+		}
+
+		if info.spanningResources.has(resource) && !required {
+			// the resource may be allocated from many NUMA nodes, so it doesn't constrain the NUMA node the pod is aligned on
+			if fits, reported := info.spanningResourceFits(qos, resource, quantity); reported {
+				if !fits {
+					klog.V(5).InfoS("early verdict: cannot meet request across the NUMA nodes", "logID", logID, "node", nodeName, "resource", resource, "suitable", "false")
+					info.unalignedResource = resource
+					return numaID, false
+				}
+				klog.V(6).InfoS("feasible across the NUMA nodes", "logID", logID, "node", nodeName, "resource", resource)
+				continue
+			}
+		}
+
 		hasNUMAAffinity := false
 		resourceBitmask := bm.NewEmptyBitMask()
-		for _, numaNode := range numaNodes {
+		for _, numaNode := range info.numaNodes {
 			numaQuantity, ok := numaNode.Resources[resource]
 			if !ok {
 				continue
 			}
 
 			hasNUMAAffinity = true
-			if !isResourceSetSuitable(qos, resource, quantity, numaQuantity) {
+			if reason, excluded := info.excludedNUMANodes[numaNode.NUMAID]; excluded {
+				klog.V(6).InfoS("excluded", "logID", logID, "node", nodeName, "NUMA", numaNode.NUMAID, "reason", reason)
+				continue
+			}
+			if !info.isResourceSuitable(qos, resource, quantity, numaQuantity) {
+				if !info.fitsWithNodeLevelDevices(resource, quantity, numaQuantity) {
+					continue
+				}
+				klog.V(6).InfoS("feasible with the instances reported only at node level", "logID", logID, "node", nodeName, "NUMA", numaNode.NUMAID, "resource", resource)
+			}
+			if !info.pcieGroups.fits(numaNode.NUMAID, resource, info.rounding.roundUp(resource, quantity)) {
+				klog.V(6).InfoS("cannot fit in a PCIe group", "logID", logID, "node", nodeName, "NUMA", numaNode.NUMAID, "resource", resource)
 				continue
 			}
 
@@ -133,7 +517,8 @@ func resourcesAvailableInAnyNUMANodes(logID string, numaNodes NUMANodeList, reso
 
 		// non-native resources or ephemeral-storage may not expose NUMA affinity,
 		// but since they are available at node level, this is fine
-		if !hasNUMAAffinity && (!v1helper.IsNativeResource(resource) || resource == v1.ResourceEphemeralStorage) {
+		// unless the pod requires them to be aligned.
+		if !hasNUMAAffinity && !required && (!v1helper.IsNativeResource(resource) || resource == v1.ResourceEphemeralStorage) {
 			klog.V(6).InfoS("resource available at node level (no NUMA affinity)", "logID", logID, "node", nodeName, "resource", resource)
 			continue
 		}
@@ -141,14 +526,20 @@ func resourcesAvailableInAnyNUMANodes(logID string, numaNodes NUMANodeList, reso
 		bitmask.And(resourceBitmask)
 		if bitmask.IsEmpty() {
 			klog.V(5).InfoS("early verdict", "logID", logID, "node", nodeName, "resource", resource, "suitable", "false")
+			if !info.bestEffort {
+				alignmentBitmaskEmptyTotal.WithLabelValues(string(resource)).Inc()
+			}
+			info.unalignedResource = resource
 			return numaID, false
 		}
 	}
 	// according to TopologyManager, the preferred NUMA affinity, is the narrowest one.
 	// https://github.com/kubernetes/kubernetes/blob/v1.24.0-rc.1/pkg/kubelet/cm/topologymanager/policy.go#L155
 	// in single-numa-node policy all resources should be allocated from a single NUMA,
-	// which means that the lowest NUMA ID (with available resources) is the one to be selected by Kubelet.
-	numaID = bitmask.GetBits()[0]
+	// which means that the lowest NUMA ID (with available resources) is the one to be selected by Kubelet,
+	// unless the power hints of the NUMA nodes are preferred.
+	numaID, info.numaChoiceReason = info.preferredNUMAID(bitmask.GetBits())
+	klog.V(4).InfoS("NUMA node chosen", "logID", logID, "node", nodeName, "NUMA", numaID, "reason", info.numaChoiceReason)
 
 	// at least one NUMA node is available
 	ret := !bitmask.IsEmpty()
@@ -156,41 +547,121 @@ func resourcesAvailableInAnyNUMANodes(logID string, numaNodes NUMANodeList, reso
 	return numaID, ret
 }
 
-func isResourceSetSuitable(qos v1.PodQOSClass, resource v1.ResourceName, quantity, numaQuantity resource.Quantity) bool {
-	// Check for the following:
-	if qos != v1.PodQOSGuaranteed {
-		// 1. set numa node as possible node if resource is memory or Hugepages
-		if resource == v1.ResourceMemory {
-			return true
+// resourceRounding maps resources to their allocation unit
+type resourceRounding map[v1.ResourceName]int64
+
+func newResourceRounding(specs []apiconfig.ResourceRoundingSpec) resourceRounding {
+	if len(specs) == 0 {
+		return nil
+	}
+	rr := make(resourceRounding, len(specs))
+	for _, spec := range specs {
+		rr[v1.ResourceName(spec.Name)] = spec.Multiple
+	}
+	return rr
+}
+
+// roundUp returns the quantity rounded up to the closest multiple of the allocation unit of the resource.
+// Quantities of resources without a configured allocation unit are returned unchanged.
+func (rr resourceRounding) roundUp(resName v1.ResourceName, quantity resource.Quantity) resource.Quantity {
+	multiple, ok := rr[resName]
+	if !ok || multiple <= 1 {
+		return quantity
+	}
+	value := quantity.Value() // rounds up fractional values
+	rounded := ((value + multiple - 1) / multiple) * multiple
+	return *resource.NewQuantity(rounded, quantity.Format)
+}
+
+// sharedDevices maps the resources whose devices can be used by many containers at once to the maximum
+// quantity a container can request, zero meaning no limit.
+type sharedDevices map[v1.ResourceName]int64
+
+func newSharedDevices(specs []apiconfig.DeviceAllocationSpec) sharedDevices {
+	var sd sharedDevices
+	for _, spec := range specs {
+		if spec.Mode != apiconfig.DeviceAllocationShared {
+			continue
 		}
-		if v1helper.IsHugePageResourceName(resource) {
-			return true
+		if sd == nil {
+			sd = make(sharedDevices)
 		}
-		// 2. set numa node as possible node if resource is CPU
-		if resource == v1.ResourceCPU {
-			return true
+		sd[v1.ResourceName(spec.Name)] = spec.SharingCap
+	}
+	return sd
+}
+
+// fits returns true if the quantity of the shared resource can be requested on a NUMA node with numaQuantity available.
+// The shared devices are not consumed by the requests, so the NUMA node only needs to expose some.
+func (sd sharedDevices) fits(resName v1.ResourceName, quantity, numaQuantity resource.Quantity) bool {
+	if numaQuantity.Sign() <= 0 {
+		return false
+	}
+	sharingCap := sd[resName]
+	return sharingCap == 0 || quantity.Value() <= sharingCap
+}
+
+// consumedResources returns the resources without the shared ones, which don't reduce the NUMA availability.
+func (sd sharedDevices) consumedResources(resources v1.ResourceList) v1.ResourceList {
+	if len(sd) == 0 {
+		return resources
+	}
+	consumed := make(v1.ResourceList, len(resources))
+	for resName, quantity := range resources {
+		if _, shared := sd[resName]; shared {
+			continue
 		}
+		consumed[resName] = quantity
+	}
+	return consumed
+}
+
+// isResourceSuitable checks the quantity of the resource against the NUMA node, honoring the device allocation mode.
+func (info *filterInfo) isResourceSuitable(qos v1.PodQOSClass, resName v1.ResourceName, quantity, numaQuantity resource.Quantity) bool {
+	if _, shared := info.sharedDevices[resName]; shared {
+		return info.sharedDevices.fits(resName, quantity, numaQuantity)
+	}
+	if resName == v1.ResourceCPU && info.wholeCPUs && isIntegerCPU(quantity) {
+		// the static CPU manager policy pins the integer requests to whole CPUs, the fractional ones run on the
+		// shared pool and are checked against the millicores
+		numaQuantity = wholeCPUs(numaQuantity)
 	}
-	// 3. otherwise check amount of resources
-	return numaQuantity.Cmp(quantity) >= 0
+	return isResourceSetSuitable(info.qosPolicy, qos, resName, quantity, numaQuantity, info.rounding)
 }
 
-func singleNUMAPodLevelHandler(pod *v1.Pod, zones topologyv1alpha2.ZoneList, nodeInfo *framework.NodeInfo) *framework.Status {
+// isCapacityIgnoredForQoS returns true if the resource is not exclusively allocated to non-guaranteed pods,
+// hence its NUMA capacity doesn't constrain them.
+func isCapacityIgnoredForQoS(resource v1.ResourceName) bool {
+	return resource == v1.ResourceCPU || resource == v1.ResourceMemory || v1helper.IsHugePageResourceName(resource)
+}
+
+func isResourceSetSuitable(policy qosResourcePolicy, qos v1.PodQOSClass, resource v1.ResourceName, quantity, numaQuantity resource.Quantity, rounding resourceRounding) bool {
+	// Check for the following:
+	// 1. set numa node as possible node if the QoS policy ignores the capacity of the resource for the pod, by default
+	// if resource is memory, Hugepages or CPU and the pod is not guaranteed
+	if policy.capacityIgnored(qos, resource) {
+		return true
+	}
+	// 2. otherwise check amount of resources, honoring the allocation unit
+	return numaQuantity.Cmp(rounding.roundUp(resource, quantity)) >= 0
+}
+
+func singleNUMAPodLevelHandler(pod *v1.Pod, info *filterInfo) *framework.Status {
 	klog.V(5).InfoS("Pod Level Resource handler")
 
-	resources := util.GetPodEffectiveRequest(pod)
+	resources := info.podAlignmentResources(pod)
 
 	logID := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
-	nodes := createNUMANodeList(zones)
 
-	// Node() != nil already verified in Filter(), which is the only public entry point
-	logNumaNodes("pod handler NUMA resources", nodeInfo.Node().Name, nodes)
+	logNumaNodes("pod handler NUMA resources", info.nodeName, info.numaNodes)
 	klog.V(6).InfoS("target resources", stringify.ResourceListToLoggable(logID, resources)...)
 
-	if _, match := resourcesAvailableInAnyNUMANodes(logID, createNUMANodeList(zones), resources, v1qos.GetPodQOS(pod), nodeInfo); !match {
+	numaID, match := resourcesAvailableInAnyNUMANodes(logID, info, resources, false)
+	if !match {
 		klog.V(2).InfoS("cannot align pod", "name", pod.Name)
-		return framework.NewStatus(framework.Unschedulable, "cannot align pod")
+		return info.cannotAlignStatus("cannot align pod", resources)
 	}
+	info.choose(numaID)
 	return nil
 }
 
@@ -199,44 +670,275 @@ func (tm *TopologyMatch) Filter(ctx context.Context, cycleState *framework.Cycle
 	if nodeInfo.Node() == nil {
 		return framework.NewStatus(framework.Error, "node not found")
 	}
+
+	ctx, span := tm.startSpan(ctx, "Filter", nodeInfo.Node().Name)
+	defer span.End()
+	status := tm.filter(ctx, cycleState, pod, nodeInfo, span)
+	setSpanVerdict(span, status)
+	tm.decisionLog.record(pod, nodeInfo.Node().Name, status)
+	return status
+}
+
+func (tm *TopologyMatch) filter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo, span trace.Span) (status *framework.Status) {
+	if tm.exemptNamespaces.Has(pod.Namespace) {
+		klog.V(5).InfoS("pod namespace exempt from the NUMA alignment, skipping", "pod", klog.KObj(pod))
+		return nil
+	}
 	if v1qos.GetPodQOS(pod) == v1.PodQOSBestEffort && !resourcerequests.IncludeNonNative(pod) {
 		return nil
 	}
+	// this also covers the non-BestEffort pods setting only limits: nothing to align
+	if resourcerequests.AreZeroForPod(pod) {
+		klog.V(5).InfoS("pod requests no resources, skipping", "pod", klog.KObj(pod))
+		return nil
+	}
 
 	nodeName := nodeInfo.Node().Name
 	nodeTopology, ok := tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, pod)
 	if !ok {
-		klog.V(2).InfoS("invalid topology data", "node", nodeName)
-		return framework.NewStatus(framework.Unschedulable, "invalid node topology data")
+		return tm.invalidTopologies.invalid(nodeName)
 	}
+	tm.invalidTopologies.valid(nodeName)
 	if nodeTopology == nil {
+		if tm.defaultPolicyWhenMissing == nil {
+			return nil
+		}
+		klog.V(5).InfoS("NodeResourceTopology not found, assuming a single NUMA node", "node", nodeName, "policy", tm.defaultPolicyWhenMissing.Policy)
+		nodeTopology = pseudoNodeResourceTopology(nodeInfo, tm.defaultPolicyWhenMissing)
+	}
+	if tm.logTopologyAge {
+		defer func() {
+			logFilterDecisionTopologyAge(pod, nodeName, nodeTopology, status)
+		}()
+	}
+	if tm.isMisconfigured(nodeTopology) {
+		klog.V(2).InfoS("conflicting topology policies, ignoring topology data", "node", nodeName, "policies", nodeTopology.TopologyPolicies)
 		return nil
 	}
 
 	klog.V(5).InfoS("Found NodeResourceTopology", "nodeTopology", klog.KObj(nodeTopology))
+	tm.configChanges.observe(nodeTopology, tm.reportedTopologyManagerConfig)
+	if tm.allocatableConsistency != nil {
+		tm.allocatableConsistency.check(nodeInfo.Node(), nodeTopology)
+	}
+	if tm.quarantine != nil && tm.quarantine.isQuarantined(nodeTopology) {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, "node topology data quarantined")
+	}
 
-	handler := filterHandlerFromTopologyManagerConfig(topologyManagerConfigFromNodeResourceTopology(nodeTopology))
+	conf := tm.topologyManagerConfig(nodeTopology)
+	setSpanConfig(span, conf)
+	prefs := tm.numaPreferencesForPod(pod)
+	if prefs.requiresMemorySpread() {
+		return filterMemorySpread(pod, conf, nodeTopology.Zones, prefs)
+	}
+	if conf.Policy == kubeletconfig.NoneTopologyManagerPolicy {
+		// the kubelet won't align anything on this node
+		if resName, ok := requestedRequiredResource(pod, prefs); ok {
+			klog.V(2).InfoS("pod requires the NUMA alignment, node doesn't provide it", "pod", klog.KObj(pod), "node", nodeName, "resource", resName)
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf("cannot align required resource %s: node topology manager policy is none", resName))
+		}
+		// the convention is about the policy the node runs, not about the policies the plugin acts on
+		if tm.guaranteedOffNonePolicy && v1qos.GetPodQOS(pod) == v1.PodQOSGuaranteed &&
+			tm.reportedTopologyManagerConfig(nodeTopology).Policy == kubeletconfig.NoneTopologyManagerPolicy {
+			klog.V(2).InfoS("guaranteed pod rejected on node with the none policy", "pod", klog.KObj(pod), "node", nodeName)
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, "guaranteed pods not allowed: node topology manager policy is none")
+		}
+	}
+	handler := filterHandlerFromTopologyManagerConfig(conf)
+	bestEffort := false
 	if handler == nil {
+		// the kubelet admits the pods regardless of the alignment, which is still worth knowing when scoring
+		handler = bestEffortHandlerFromTopologyManagerConfig(conf)
+		if handler == nil {
+			return nil
+		}
+		bestEffort = true
+	}
+
+	numaNodes := createNUMANodeList(nodeTopology.Zones)
+	numaNodes = tm.numaOccupancy.reconstruct(nodeName, numaNodes, nodeTopology.Zones, nodeInfo.Pods, tm.qosResourcePolicy)
+	numaNodes = tm.orphanCapacity.attribute(nodeName, numaNodes, nodeTopology.Zones, util.ResourceList(nodeInfo.Allocatable))
+	numaNodes = reserveNUMAResources(nodeName, numaNodes, nodeTopology.Zones)
+	qos := v1qos.GetPodQOS(pod)
+	info := &filterInfo{
+		nodeName:                nodeName,
+		node:                    nodeInfo,
+		topologyManager:         conf,
+		qos:                     qos,
+		numaNodes:               tm.resourceAliases.resolve(numaNodes),
+		preferences:             prefs,
+		rounding:                tm.resourceRounding,
+		sharedDevices:           tm.sharedDevices,
+		qosPolicy:               tm.qosResourcePolicy,
+		spanningResources:       tm.spanningResources,
+		excludedNUMANodes:       excludeDegradedNUMANodes(untoleratedNUMANodes(pod, nodeTopology.Zones), nodeTopology.Zones, tm.degradedNUMAThreshold),
+		alignMemoryToLimits:     tm.memoryAlignAgainstLimits && qos == v1.PodQOSBurstable,
+		containerCPUExclusivity: tm.containerCPUExclusivity,
+		fractionalCPUShared:     tm.fractionalCPUShared && qos == v1.PodQOSGuaranteed,
+		wholeCPUs:               tm.staticCPUManager && qos == v1.PodQOSGuaranteed,
+		burstFactorPercent:      burstFactorPercentForQoS(tm.burstFactorPercent, qos),
+		containerScopeSameNUMA:  tm.containerScopeSameNUMA,
+		initContainersSameNUMA:  tm.initContainersSameNUMA,
+		numaSockets:             numaNodeSockets(nodeTopology.Zones),
+		numaPowerHints:          numaNodePowerHints(nodeTopology.Zones, tm.numaPowerHintAttribute),
+		numaEphemeralStorage:    tm.numaEphemeralStorage,
+		bestEffort:              bestEffort,
+		maxCandidateNUMASets:    tm.maxCandidateNUMASets,
+	}
+	if tm.numaAffinityMemory != nil {
+		info.numaNodes = tm.numaAffinityMemory.stabilize(nodeName, info.numaNodes)
+	}
+	if claimed, ok := tm.claimedNUMANodes(pod, nodeInfo.Node()); ok {
+		if len(claimed) == 0 && !bestEffort {
+			klog.V(2).InfoS("claimed devices local to different NUMA nodes", "pod", klog.KObj(pod), "node", nodeName)
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, "claimed devices are local to different NUMA nodes")
+		}
+		info.excludedNUMANodes = excludeNonLocalNUMANodes(info.excludedNUMANodes, info.numaNodes, claimed, "not local to the claimed devices")
+	}
+	if numaID, ok := hostPathNUMANode(pod); ok {
+		info.excludedNUMANodes = excludeNonLocalNUMANodes(info.excludedNUMANodes, info.numaNodes, map[int]bool{numaID: true}, "not local to the hostPath devices")
+	}
+	if tm.pcieGroupAlignment {
+		info.pcieGroups = newPCIeGroups(nodeTopology.Zones)
+	}
+	if tm.bestEffortNodeDevices && qos == v1.PodQOSBestEffort && tm.orphanCapacity == nil {
+		info.nodeLevelDevices = nodeLevelDevices(nodeTopology.Zones, nodeInfo)
+	}
+
+	if bestEffort {
+		// never fails
+		handler(tm.withSidecarOverhead(pod), info)
+		storeBestEffortHint(cycleState, nodeName, info.bestEffortHint, info.unalignedContainers)
+		return nil
+	}
+
+	// a node lacking a resource entirely is not an alignment failure, and nothing but a node change can fix it
+	if resName, found := missingNodeResource(pod, info); found {
+		klog.V(2).InfoS("node has none of the requested resource", "pod", klog.KObj(pod), "node", nodeName, "resource", resName)
+		reason := fmt.Sprintf("node has no %s resource", resName)
+		if tm.resourceNameHints {
+			if similar, ok := similarResourceName(resName, util.ResourceList(info.node.Allocatable)); ok {
+				klog.V(2).InfoS("node has a resource with similar name", "pod", klog.KObj(pod), "node", nodeName, "resource", resName, "similar", similar)
+				reason = fmt.Sprintf("%s, but has the similar %s resource", reason, similar)
+			}
+		}
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, reason)
+	}
+	// no point in doing the NUMA math if the node as whole can't fit the pod
+	if resName, found := insufficientNodeResource(pod, info); found {
+		klog.V(2).InfoS("cannot fit pod at node level", "pod", klog.KObj(pod), "node", nodeName, "resource", resName)
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("insufficient node resources: %s", resName))
+	}
+	status = handler(tm.withSidecarOverhead(pod), info)
+	if status != nil && tm.downgrades.isDowngraded(pod) {
+		// only the alignment verdict is waived, like the kubelet with the best-effort policy
+		klog.V(5).InfoS("pod downgraded to best-effort alignment, ignoring the alignment failure", "pod", klog.KObj(pod), "node", nodeName, "reason", status.Message())
 		return nil
 	}
-	status := handler(pod, nodeTopology.Zones, nodeInfo)
 	if status != nil {
-		tm.nrtCache.NodeMaybeOverReserved(nodeName, pod)
+		tm.markNodeMaybeOverReserved(cycleState, pod, nodeName)
+		recordUnalignedResource(cycleState, nodeName, info.unalignedResource)
+		return status
 	}
-	return status
+	setSpanNUMANodes(span, info.chosenNUMANodes)
+	storeNUMAChoiceReasons(cycleState, pod, info)
+	tm.storePlacement(cycleState, pod, info)
+	tm.recordPlacementWaste(cycleState, pod, info)
+	return nil
+}
+
+// burstFactorPercentForQoS returns the burst factor percentage to apply to the pods of the QoS class: only the Burstable pods burst.
+func burstFactorPercentForQoS(percent int64, qos v1.PodQOSClass) int64 {
+	if qos != v1.PodQOSBurstable {
+		return 0
+	}
+	return percent
+}
+
+// missingNodeResource returns the first resource, in name order, the pod requests which is not reported at node level at all.
+func missingNodeResource(pod *v1.Pod, info *filterInfo) (v1.ResourceName, bool) {
+	resources := util.GetPodEffectiveRequest(pod)
+	nodeResources := util.ResourceList(info.node.Allocatable)
+	for _, resName := range sortedResourceNames(resources) {
+		if quantity := resources[resName]; quantity.IsZero() {
+			continue
+		}
+		if _, ok := nodeResources[resName]; !ok {
+			return resName, true
+		}
+	}
+	return "", false
+}
+
+// insufficientNodeResource returns the first resource, in name order, the pod requests in a greater quantity
+// than what is still available at node level. Only the resources whose capacity would be checked during the
+// NUMA evaluation are considered. Resources not reported at node level are also skipped, because this case
+// is handled during the NUMA evaluation as well.
+func insufficientNodeResource(pod *v1.Pod, info *filterInfo) (v1.ResourceName, bool) {
+	resources := util.GetPodEffectiveRequest(pod)
+	// Node() != nil already verified in Filter(), which is the only public entry point
+	allocatable := info.node.Node().Status.Allocatable
+	requested := util.ResourceList(info.node.Requested)
+
+	for _, resName := range sortedResourceNames(resources) {
+		quantity := resources[resName]
+		if quantity.IsZero() {
+			continue
+		}
+		if !info.preferences.requiresAlignment(resName) && info.qosPolicy.capacityIgnored(info.qos, resName) {
+			continue
+		}
+		available, ok := allocatable[resName]
+		if !ok {
+			continue
+		}
+		available.Sub(requested[resName])
+		if available.Cmp(quantity) < 0 {
+			return resName, true
+		}
+	}
+	return "", false
+}
+
+// requestedRequiredResource returns the first resource, in name order, the pod requests and requires to be NUMA-aligned.
+func requestedRequiredResource(pod *v1.Pod, prefs NUMAPreferences) (v1.ResourceName, bool) {
+	resources := util.GetPodEffectiveRequest(pod)
+	for _, resName := range sortedResourceNames(resources) {
+		quantity := resources[resName]
+		if !quantity.IsZero() && prefs.requiresAlignment(resName) {
+			return resName, true
+		}
+	}
+	return "", false
+}
+
+func sortedResourceNames(resources v1.ResourceList) []v1.ResourceName {
+	resNames := make([]v1.ResourceName, 0, len(resources))
+	for resName := range resources {
+		resNames = append(resNames, resName)
+	}
+	sort.Slice(resNames, func(i, j int) bool { return resNames[i] < resNames[j] })
+	return resNames
+}
+
+func isRestartableInitContainer(initContainer *v1.Container) bool {
+	if initContainer.RestartPolicy == nil {
+		return false
+	}
+	return *initContainer.RestartPolicy == v1.ContainerRestartPolicyAlways
 }
 
 // subtractFromNUMA finds the correct NUMA ID's resources and subtract them from `nodes`.
-func subtractFromNUMA(nodes NUMANodeList, numaID int, container v1.Container) {
+func subtractFromNUMA(nodes NUMANodeList, numaID int, resources v1.ResourceList, rounding resourceRounding) {
 	for i := 0; i < len(nodes); i++ {
 		if nodes[i].NUMAID != numaID {
 			continue
 		}
 
 		nRes := nodes[i].Resources
-		for resName, quan := range container.Resources.Requests {
+		for resName, quan := range resources {
 			nodeResQuan := nRes[resName]
-			nodeResQuan.Sub(quan)
+			nodeResQuan.Sub(rounding.roundUp(resName, quan))
 			// we do not expect a negative value here, since this function only called
 			// when resourcesAvailableInAnyNUMANodes function is passed
 			// but let's log here if such unlikely case will occur
@@ -248,6 +950,9 @@ func subtractFromNUMA(nodes NUMANodeList, numaID int, container v1.Container) {
 	}
 }
 
+// filterHandlerFromTopologyManagerConfig returns the handler checking the alignment the kubelet would enforce with the given
+// configuration, or nil if the kubelet would admit the pods regardless of the alignment. The restricted policy, when it must
+// be handled like single-numa-node, is already translated by the configuration resolution.
 func filterHandlerFromTopologyManagerConfig(conf TopologyManagerConfig) filterFn {
 	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
 		return nil
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+const fuzzDevice = "vendor.com/device"
+
+// referenceResourcesAvailableInAnyNUMANodes is a straightforward implementation of resourcesAvailableInAnyNUMANodes:
+// it checks every NUMA ID the bitmask can represent against every requested resource, and picks the lowest fitting one.
+func referenceResourcesAvailableInAnyNUMANodes(info *filterInfo, resources v1.ResourceList, exclusiveCPU bool) (int, bool) {
+	const maxBits = 64
+	nodeResources := util.ResourceList(info.node.Allocatable)
+
+	candidates := make([]bool, maxBits)
+	for i := range candidates {
+		candidates[i] = true
+	}
+
+	for resName, quantity := range resources {
+		if quantity.IsZero() {
+			continue
+		}
+		if _, ok := nodeResources[resName]; !ok {
+			return highestNUMAID, false
+		}
+		if !info.topologyManager.providesHints(resName) {
+			continue
+		}
+
+		required := info.preferences.requiresAlignment(resName) || (resName == v1.ResourceMemory && info.alignMemoryToLimits) ||
+			(resName == v1.ResourceCPU && exclusiveCPU)
+		qos := info.qos
+		if required {
+			qos = v1.PodQOSGuaranteed
+		}
+
+		hasNUMAAffinity := false
+		fits := make([]bool, maxBits)
+		for _, numaNode := range info.numaNodes {
+			numaQuantity, ok := numaNode.Resources[resName]
+			if !ok {
+				continue
+			}
+			hasNUMAAffinity = true
+			if _, excluded := info.excludedNUMANodes[numaNode.NUMAID]; excluded {
+				continue
+			}
+			if !info.isResourceSuitable(qos, resName, quantity, numaQuantity) {
+				continue
+			}
+			if !info.pcieGroups.fits(numaNode.NUMAID, resName, info.rounding.roundUp(resName, quantity)) {
+				continue
+			}
+			fits[numaNode.NUMAID] = true
+		}
+		if !hasNUMAAffinity && !required && (!v1helper.IsNativeResource(resName) || resName == v1.ResourceEphemeralStorage) {
+			continue
+		}
+
+		anyCandidate := false
+		for i := range candidates {
+			candidates[i] = candidates[i] && fits[i]
+			anyCandidate = anyCandidate || candidates[i]
+		}
+		if !anyCandidate {
+			return highestNUMAID, false
+		}
+	}
+
+	for i, candidate := range candidates {
+		if candidate {
+			return i, true
+		}
+	}
+	return highestNUMAID, false
+}
+
+// makeFuzzFilterInfo builds the NUMA nodes from the data, three bytes per NUMA node: cpus, memory in GiB and devices.
+func makeFuzzFilterInfo(numaCount uint8, guaranteed, devOnNUMA, devOnNode bool, numaData []byte) *filterInfo {
+	info := &filterInfo{
+		nodeName: "fuzz-node",
+		qos:      v1.PodQOSBurstable,
+	}
+	if guaranteed {
+		info.qos = v1.PodQOSGuaranteed
+	}
+
+	count := int(numaCount%highestNUMAID) + 1
+	nodeResources := v1.ResourceList{}
+	addToNode := func(resName v1.ResourceName, quantity resource.Quantity) {
+		total := nodeResources[resName]
+		total.Add(quantity)
+		nodeResources[resName] = total
+	}
+	for i := 0; i < count; i++ {
+		var cpus, memGiB, devs byte
+		if len(numaData) >= 3*(i+1) {
+			cpus, memGiB, devs = numaData[3*i]%9, numaData[3*i+1]%9, numaData[3*i+2]%4
+		}
+		resources := v1.ResourceList{
+			v1.ResourceCPU:    *resource.NewQuantity(int64(cpus), resource.DecimalSI),
+			v1.ResourceMemory: *resource.NewQuantity(int64(memGiB)*1024*1024*1024, resource.BinarySI),
+		}
+		if devOnNUMA {
+			resources[fuzzDevice] = *resource.NewQuantity(int64(devs), resource.DecimalSI)
+		}
+		for resName, quantity := range resources {
+			addToNode(resName, quantity)
+		}
+		info.numaNodes = append(info.numaNodes, NUMANode{NUMAID: i, Resources: resources})
+	}
+	if devOnNode && !devOnNUMA {
+		addToNode(fuzzDevice, *resource.NewQuantity(4, resource.DecimalSI))
+	}
+	if !devOnNode {
+		delete(nodeResources, fuzzDevice)
+	}
+
+	info.node = framework.NewNodeInfo()
+	info.node.SetNode(&v1.Node{
+		Status: v1.NodeStatus{
+			Capacity:    nodeResources,
+			Allocatable: nodeResources,
+		},
+	})
+	return info
+}
+
+func FuzzResourcesAvailableInAnyNUMANodes(f *testing.F) {
+	// numaCount, guaranteed, devOnNUMA, devOnNode, exclusiveCPU, cpuReq, memReqGiB, devReq, numaData
+	f.Add(uint8(1), true, false, false, false, uint8(2), uint8(2), uint8(0), []byte{4, 8, 0, 4, 8, 0})
+	f.Add(uint8(2), true, false, false, false, uint8(6), uint8(2), uint8(0), []byte{4, 8, 0, 4, 8, 0, 4, 8, 0})
+	f.Add(uint8(2), true, true, true, false, uint8(2), uint8(2), uint8(2), []byte{4, 8, 1, 4, 8, 3, 4, 8, 0})
+	f.Add(uint8(3), false, true, true, true, uint8(3), uint8(1), uint8(1), []byte{2, 1, 0, 4, 0, 1, 4, 8, 1, 8, 8, 3})
+	f.Add(uint8(1), true, false, true, false, uint8(1), uint8(1), uint8(3), []byte{1, 1, 0, 1, 1, 0})
+	f.Add(uint8(1), true, false, false, false, uint8(0), uint8(0), uint8(1), []byte{})
+	f.Add(uint8(7), true, true, true, false, uint8(0), uint8(0), uint8(0), []byte{0, 0, 0})
+
+	f.Fuzz(func(t *testing.T, numaCount uint8, guaranteed, devOnNUMA, devOnNode, exclusiveCPU bool, cpuReq, memReqGiB, devReq uint8, numaData []byte) {
+		info := makeFuzzFilterInfo(numaCount, guaranteed, devOnNUMA, devOnNode, numaData)
+		resources := v1.ResourceList{
+			v1.ResourceCPU:    *resource.NewQuantity(int64(cpuReq%9), resource.DecimalSI),
+			v1.ResourceMemory: *resource.NewQuantity(int64(memReqGiB%9)*1024*1024*1024, resource.BinarySI),
+			fuzzDevice:        *resource.NewQuantity(int64(devReq%4), resource.DecimalSI),
+		}
+
+		gotID, gotMatch := resourcesAvailableInAnyNUMANodes("fuzz", info, resources, exclusiveCPU)
+		expectedID, expectedMatch := referenceResourcesAvailableInAnyNUMANodes(info, resources, exclusiveCPU)
+		if gotMatch != expectedMatch || gotID != expectedID {
+			t.Errorf("resources=%v NUMA nodes=%v qos=%v: got (%d, %v) expected (%d, %v)",
+				resources, info.numaNodes, info.qos, gotID, gotMatch, expectedID, expectedMatch)
+		}
+	})
+}
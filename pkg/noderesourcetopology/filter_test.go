@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"runtime"
 	"testing"
 
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
@@ -27,8 +28,12 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics/testutil"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
 	tu "sigs.k8s.io/scheduler-plugins/test/util"
 )
@@ -253,7 +258,7 @@ func TestNodeResourceTopology(t *testing.T) {
 			pod: makePodByResourceList(&v1.ResourceList{
 				nicResourceName: *resource.NewQuantity(20, resource.DecimalSI)}),
 			node:       nodes[2],
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: vendor/nic1"),
 		},
 		{
 			name: "Best effort QoS requesting devices, Container Scope Topology policy; pod fit",
@@ -290,7 +295,7 @@ func TestNodeResourceTopology(t *testing.T) {
 					nicResourceName:   *resource.NewQuantity(11, resource.DecimalSI)},
 			),
 			node:       nodes[1],
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: vendor/nic1"),
 		},
 		{
 			name: "Best effort QoS, requesting CPU, memory (enough on NUMA) and devices (not enough), Pod Scope Topology policy; pod doesn't fit",
@@ -426,7 +431,7 @@ func TestNodeResourceTopology(t *testing.T) {
 				v1.ResourceCPU:  *resource.NewQuantity(4, resource.DecimalSI),
 				nicResourceName: *resource.NewQuantity(11, resource.DecimalSI)}),
 			node:       nodes[1],
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: vendor/nic1"),
 		},
 		{
 			name: "Burstable QoS, requesting CPU and devices (not enough), Pod Scope Topology policy; pod doesn't fit",
@@ -474,7 +479,7 @@ func TestNodeResourceTopology(t *testing.T) {
 				v1.ResourceMemory: resource.MustParse("2Gi"),
 				nicResourceName:   *resource.NewQuantity(11, resource.DecimalSI)}),
 			node:       nodes[1],
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: vendor/nic1"),
 		},
 		{
 			name: "Burstable QoS, requesting memory (enough on NUMA) and devices (not enough), Pod Scope Topology policy; pod doesn't fit",
@@ -523,7 +528,7 @@ func TestNodeResourceTopology(t *testing.T) {
 				v1.ResourceMemory: resource.MustParse("4Gi"),
 				nicResourceName:   *resource.NewQuantity(11, resource.DecimalSI)}),
 			node:       nodes[1],
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: vendor/nic1"),
 		},
 		{
 			name: "Burstable QoS, requesting CPU, memory (enough on NUMA) and devices (not enough), Pod Scope Topology policy; pod doesn't fit",
@@ -614,7 +619,7 @@ func TestNodeResourceTopology(t *testing.T) {
 				v1.ResourceMemory:          resource.MustParse("1Gi"),
 				notExistingNICResourceName: *resource.NewQuantity(0, resource.DecimalSI)}, 3),
 			node:       nodes[2],
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: cpu"),
 		},
 		{
 			name: "Guaranteed QoS Topology Scope, minimal, pod fit",
@@ -858,7 +863,7 @@ func TestNodeResourceTopologyMultiContainerPodScope(t *testing.T) {
 				nodeTopologies[0],
 			},
 			avail:      []resourceDescriptor{},
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: hugepages-2Mi"),
 		},
 		{
 			name: "gu pod does not fit - not enough devices available on any NUMA node",
@@ -885,7 +890,7 @@ func TestNodeResourceTopologyMultiContainerPodScope(t *testing.T) {
 				nodeTopologies[0],
 			},
 			avail:      []resourceDescriptor{},
-			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: vendor/nic1"),
 		},
 	}
 
@@ -1178,110 +1183,2279 @@ func TestNodeResourceTopologyMultiContainerContainerScope(t *testing.T) {
 	}
 }
 
-func makeNodeFromNodeResourceTopology(nrt *topologyv1alpha2.NodeResourceTopology) *v1.Node {
-	res := makeResourceListFromZones(nrt.Zones)
-	return &v1.Node{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: nrt.Name,
-		},
-		Status: v1.NodeStatus{
-			Capacity:    res,
-			Allocatable: res,
+func TestNodeResourceTopologyRestartableInitContainers(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
 		},
 	}
-}
 
-func findAvailableResourceByName(resourceInfoList topologyv1alpha2.ResourceInfoList, name string) resource.Quantity {
-	for _, resourceInfo := range resourceInfoList {
-		if resourceInfo.Name == name {
-			return resourceInfo.Available
-		}
+	sidecar := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("3"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
 	}
-	return resource.MustParse("0")
-}
 
-func makePod(name string, options ...func(*v1.Pod)) *v1.Pod {
-	pod := v1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+	tests := []struct {
+		name        string
+		initCntReq  []v1.ResourceList
+		restartable bool
+		wantStatus  *framework.Status
+	}{
+		{
+			name:        "restartable init containers only, one per NUMA node - fit",
+			initCntReq:  []v1.ResourceList{sidecar, sidecar},
+			restartable: true,
+			wantStatus:  nil,
+		},
+		{
+			name:        "restartable init containers only, exceeding the NUMA nodes - not fit",
+			initCntReq:  []v1.ResourceList{sidecar, sidecar, sidecar},
+			restartable: true,
+			wantStatus:  framework.NewStatus(framework.Unschedulable, "cannot align init container"),
+		},
+		{
+			name:        "regular init containers, running serially - fit",
+			initCntReq:  []v1.ResourceList{sidecar, sidecar, sidecar},
+			restartable: false,
+			wantStatus:  nil,
 		},
 	}
-	for _, o := range options {
-		o(&pod)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePod("testpod", withMultiInitContainers(tt.initCntReq))
+			if tt.restartable {
+				restartPolicy := v1.ContainerRestartPolicyAlways
+				for idx := range pod.Spec.InitContainers {
+					pod.Spec.InitContainers[idx].RestartPolicy = &restartPolicy
+				}
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
 	}
-	return &pod
 }
 
-func withMultiContainers(resourcesList []v1.ResourceList) func(*v1.Pod) {
-	return func(pod *v1.Pod) {
-		var containers []v1.Container
+func TestNodeResourceTopologyResourceRounding(t *testing.T) {
+	const chunkedDevice = "vendor.com/chunked-device"
 
-		for idx := range resourcesList {
-			res := cloneResourceList(resourcesList[idx])
-			containers = append(containers, v1.Container{
-				Name: fmt.Sprintf("cnt-%d", idx+1),
-				Resources: v1.ResourceRequirements{
-					Requests: res,
-					Limits:   res,
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(chunkedDevice, "4", "3"),
 				},
-			})
-		}
-		pod.Spec.Containers = containers
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(chunkedDevice, "4", "3"),
+				},
+			},
+		},
 	}
-}
 
-func withMultiInitContainers(resourcesList []v1.ResourceList) func(*v1.Pod) {
-	return func(pod *v1.Pod) {
-		p := &v1.Pod{}
-		f := withMultiContainers(resourcesList)
-		f(p)
-		pod.Spec.InitContainers = p.Spec.Containers
+	tests := []struct {
+		name       string
+		cntReq     []map[string]string
+		rounding   resourceRounding
+		wantStatus *framework.Status
+	}{
+		{
+			name: "no rounding, request fits the available devices",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", chunkedDevice: "3"},
+			},
+			wantStatus: nil,
+		},
+		{
+			name: "rounding, request rounded up exceeds the available devices",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", chunkedDevice: "3"},
+			},
+			rounding:   resourceRounding{chunkedDevice: 2},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+		{
+			name: "rounding, request rounded up fits the available devices",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", chunkedDevice: "1"},
+				{cpu: "1", memory: "1Gi", chunkedDevice: "1"},
+			},
+			rounding:   resourceRounding{chunkedDevice: 2},
+			wantStatus: nil,
+		},
+		{
+			name: "rounding, rounded up requests are subtracted",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", chunkedDevice: "1"},
+				{cpu: "1", memory: "1Gi", chunkedDevice: "1"},
+				{cpu: "1", memory: "1Gi", chunkedDevice: "1"},
+			},
+			rounding:   resourceRounding{chunkedDevice: 2},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
 	}
-}
 
-func cloneResourceList(rl v1.ResourceList) v1.ResourceList {
-	res := make(v1.ResourceList)
-	for name, qty := range rl {
-		res[name] = qty
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
 	}
-	return res
-}
 
-func parseTestUserEntry(entries []testUserEntry) []testEntry {
-	var teList []testEntry
-	for i, e := range entries {
-		irl := parseContainerRes(e.initCntReq)
-		rl := parseContainerRes(e.cntReq)
-		pod := makePod(fmt.Sprintf("testpod%d", i), withMultiInitContainers(irl), withMultiContainers(rl))
-		te := testEntry{
-			name:       e.description,
-			pod:        pod,
-			wantStatus: parseState(e.statusErr),
-		}
-		teList = append(teList, te)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:         nrtcache.NewPassthrough(fakeClient),
+				resourceRounding: tt.rounding,
+			}
+
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(tt.cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
 	}
-	return teList
 }
 
-func parseContainerRes(cntRes []map[string]string) []v1.ResourceList {
-	rll := []v1.ResourceList{}
-	for i := 0; i < len(cntRes); i++ {
-		resMap := cntRes[i]
+func TestNodeResourceTopologyNodeLevelFull(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(nicResourceName, "4", "4"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(nicResourceName, "4", "4"),
+				},
+			},
+		},
+	}
 
-		rl := v1.ResourceList{}
-		for k, v := range resMap {
-			rl[v1.ResourceName(k)] = resource.MustParse(v)
-		}
-		rll = append(rll, rl)
+	// the NRT data is stale and doesn't reflect the pods already running on the node
+	runningPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("12"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+		nicResourceName:   resource.MustParse("2"),
+	})
+
+	tests := []struct {
+		name        string
+		pod         *v1.Pod
+		runningPods []*v1.Pod
+		wantStatus  *framework.Status
+	}{
+		{
+			name: "guaranteed pod, node has enough resources",
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			}),
+			runningPods: []*v1.Pod{runningPod},
+			wantStatus:  nil,
+		},
+		{
+			name: "guaranteed pod, request exceeds the node allocatable",
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				nicResourceName:   resource.MustParse("9"),
+			}),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "insufficient node resources: vendor/nic1"),
+		},
+		{
+			name: "guaranteed pod, request exceeds the node available resources",
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			}),
+			runningPods: []*v1.Pod{runningPod},
+			wantStatus:  framework.NewStatus(framework.Unschedulable, "insufficient node resources: cpu"),
+		},
+		{
+			name: "burstable pod, cpu is not constrained",
+			pod: makePodWithReqByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			}),
+			runningPods: []*v1.Pod{runningPod},
+			wantStatus:  nil,
+		},
 	}
 
-	return rll
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo(tt.runningPods...)
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
 }
 
-func parseState(error string) *framework.Status {
-	if len(error) == 0 {
-		return nil
+func TestNodeResourceTopologyNonPositiveNUMAResources(t *testing.T) {
+	makeNRT := func(name, numa0CPUs, numa1CPUs string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", numa0CPUs),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", numa1CPUs),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
 	}
 
-	return framework.NewStatus(framework.Unschedulable, error)
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "negative cpu on a NUMA node, the other fits",
+			nrt:        makeNRT("negative-fit", "-4", "2"),
+			wantStatus: nil,
+		},
+		{
+			name:       "negative and zero cpu on the NUMA nodes",
+			nrt:        makeNRT("negative-zero", "-4", "0"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:       "zero cpu on both NUMA nodes",
+			nrt:        makeNRT("zero-zero", "0", "0"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	tm := TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := fakeClient.Create(context.Background(), tt.nrt.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			// the node allocatable comes from the kubelet, not from the NRT data
+			node := makeNodeFromNodeResourceTopology(tt.nrt)
+			node.Status.Allocatable = v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("8"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyZeroRequests(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	limitsOnly := func() *v1.Pod {
+		pod := makePod("testpod")
+		pod.Spec.Containers = []v1.Container{
+			{
+				Name: "cnt-1",
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("2"),
+						v1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+			},
+		}
+		return pod
+	}
+	withOverhead := limitsOnly()
+	withOverhead.Spec.Overhead = v1.ResourceList{
+		v1.ResourceCPU: resource.MustParse("250m"),
+	}
+
+	tests := []struct {
+		name          string
+		pod           *v1.Pod
+		wantPlacement bool
+	}{
+		{
+			name: "no requests nor limits",
+			pod:  makePod("testpod"),
+		},
+		{
+			name: "only limits",
+			pod:  limitsOnly(),
+		},
+		{
+			name:          "only limits, with overhead",
+			pod:           withOverhead,
+			wantPlacement: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the placement is only recorded if the pod went through the NUMA alignment
+			recorder := &fakePlacementRecorder{}
+			tm := TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				placementRecorder: recorder,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			state := framework.NewCycleState()
+			if gotStatus := tm.Filter(context.Background(), state, tt.pod, nodeInfo); gotStatus != nil {
+				t.Fatalf("unexpected status: %v", gotStatus)
+			}
+
+			tm.Reserve(context.Background(), state, tt.pod, nrt.Name)
+			if gotPlacement := len(recorder.placements) > 0; gotPlacement != tt.wantPlacement {
+				t.Errorf("placement recorded=%v, want: %v", gotPlacement, tt.wantPlacement)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyMissingNodeResource(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				MakeTopologyResInfo(nicResourceName, "2", "2"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				MakeTopologyResInfo(nicResourceName, "2", "2"),
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		policy     topologyv1alpha2.TopologyManagerPolicy
+		pod        *v1.Pod
+		wantStatus *framework.Status
+	}{
+		{
+			name:   "pod scope, node has none of the resource",
+			policy: topologyv1alpha2.SingleNUMANodePodLevel,
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:             resource.MustParse("2"),
+				v1.ResourceMemory:          resource.MustParse("2Gi"),
+				notExistingNICResourceName: resource.MustParse("1"),
+			}),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "node has no "+notExistingNICResourceName+" resource"),
+		},
+		{
+			name:   "pod scope, node has the resource but can't align it",
+			policy: topologyv1alpha2.SingleNUMANodePodLevel,
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				nicResourceName:   resource.MustParse("3"),
+			}),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:   "container scope, node has none of the resource",
+			policy: topologyv1alpha2.SingleNUMANodeContainerLevel,
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:             resource.MustParse("2"),
+				v1.ResourceMemory:          resource.MustParse("2Gi"),
+				notExistingNICResourceName: resource.MustParse("1"),
+			}),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "node has no "+notExistingNICResourceName+" resource"),
+		},
+		{
+			name:   "container scope, node has the resource but can't align it",
+			policy: topologyv1alpha2.SingleNUMANodeContainerLevel,
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				nicResourceName:   resource.MustParse("3"),
+			}),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nrt := &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+				TopologyPolicies: []string{string(tt.policy)},
+				Zones:            zones,
+			}
+
+			fakeClient, err := tu.NewFakeClient()
+			if err != nil {
+				t.Fatalf("failed to create fake client: %v", err)
+			}
+			if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyMemoryAlignAgainstLimits(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "4Gi", "4Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "4Gi", "4Gi"),
+			},
+		},
+	}
+
+	burstablePod := func(memoryLimit string) *v1.Pod {
+		return makePodWithReqAndLimitByResourceList(
+			&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+			&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse(memoryLimit),
+			},
+		)
+	}
+
+	tests := []struct {
+		name               string
+		policy             topologyv1alpha2.TopologyManagerPolicy
+		alignAgainstLimits bool
+		pod                *v1.Pod
+		wantStatus         *framework.Status
+	}{
+		{
+			name:       "pod scope, requests fit, limits don't, aligning against requests",
+			policy:     topologyv1alpha2.SingleNUMANodePodLevel,
+			pod:        burstablePod("6Gi"),
+			wantStatus: nil,
+		},
+		{
+			name:               "pod scope, requests fit, limits don't, aligning against limits",
+			policy:             topologyv1alpha2.SingleNUMANodePodLevel,
+			alignAgainstLimits: true,
+			pod:                burstablePod("6Gi"),
+			wantStatus:         framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:               "pod scope, limits fit, aligning against limits",
+			policy:             topologyv1alpha2.SingleNUMANodePodLevel,
+			alignAgainstLimits: true,
+			pod:                burstablePod("3Gi"),
+			wantStatus:         nil,
+		},
+		{
+			name:       "container scope, requests fit, limits don't, aligning against requests",
+			policy:     topologyv1alpha2.SingleNUMANodeContainerLevel,
+			pod:        burstablePod("6Gi"),
+			wantStatus: nil,
+		},
+		{
+			name:               "container scope, requests fit, limits don't, aligning against limits",
+			policy:             topologyv1alpha2.SingleNUMANodeContainerLevel,
+			alignAgainstLimits: true,
+			pod:                burstablePod("6Gi"),
+			wantStatus:         framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+		{
+			name:               "guaranteed pod is not affected",
+			policy:             topologyv1alpha2.SingleNUMANodePodLevel,
+			alignAgainstLimits: true,
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("3Gi"),
+			}),
+			wantStatus: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nrt := &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+				TopologyPolicies: []string{string(tt.policy)},
+				Zones:            zones,
+			}
+
+			fakeClient, err := tu.NewFakeClient()
+			if err != nil {
+				t.Fatalf("failed to create fake client: %v", err)
+			}
+			if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+
+			tm := TopologyMatch{
+				nrtCache:                 nrtcache.NewPassthrough(fakeClient),
+				memoryAlignAgainstLimits: tt.alignAgainstLimits,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyContainerCPUExclusivity(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+
+	// burstable pod: the first container requests equal limits, the second container has no limits
+	burstablePod := func(exclusiveCPUs string) *v1.Pod {
+		pod := makePod("mixed", withMultiContainers(parseContainerRes([]map[string]string{
+			{cpu: exclusiveCPUs, memory: "1Gi"},
+			{cpu: "1", memory: "1Gi"},
+		})))
+		pod.Spec.Containers[1].Resources.Limits = nil
+		return pod
+	}
+
+	tests := []struct {
+		name           string
+		cpuExclusivity bool
+		pod            *v1.Pod
+		wantStatus     *framework.Status
+	}{
+		{
+			name:       "option disabled, CPU capacity ignored",
+			pod:        burstablePod("6"),
+			wantStatus: nil,
+		},
+		{
+			name:           "option enabled, guaranteed-like container exceeds the NUMA CPU capacity",
+			cpuExclusivity: true,
+			pod:            burstablePod("6"),
+			wantStatus:     framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+		{
+			name:           "option enabled, guaranteed-like container fits the NUMA CPU capacity",
+			cpuExclusivity: true,
+			pod:            burstablePod("3"),
+			wantStatus:     nil,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:                nrtcache.NewPassthrough(fakeClient),
+				containerCPUExclusivity: tt.cpuExclusivity,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyFractionalCPUShared(t *testing.T) {
+	// only the first NUMA node fits the memory of the single container pods, and only the second one has CPUs left
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "0"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "2"),
+						MakeTopologyResInfo(memory, "8Gi", "2Gi"),
+					},
+				},
+			},
+		}
+	}
+	podLevel := makeNRT("pod-level", topologyv1alpha2.SingleNUMANodePodLevel)
+	containerLevel := makeNRT("container-level", topologyv1alpha2.SingleNUMANodeContainerLevel)
+
+	guaranteedPod := func(cntReq ...map[string]string) *v1.Pod {
+		return makePod("guaranteed", withMultiContainers(parseContainerRes(cntReq)))
+	}
+	cannotAlign := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+
+	tests := []struct {
+		name        string
+		nrt         *topologyv1alpha2.NodeResourceTopology
+		shared      bool
+		annotations map[string]string
+		pod         *v1.Pod
+		wantStatus  *framework.Status
+	}{
+		{
+			name:       "option disabled, fractional CPUs checked",
+			nrt:        podLevel,
+			pod:        guaranteedPod(map[string]string{cpu: "500m", memory: "3Gi"}),
+			wantStatus: cannotAlign,
+		},
+		{
+			name:   "option enabled, fractional CPUs shared",
+			nrt:    podLevel,
+			shared: true,
+			pod:    guaranteedPod(map[string]string{cpu: "500m", memory: "3Gi"}),
+		},
+		{
+			name:       "option enabled, integer CPUs checked",
+			nrt:        podLevel,
+			shared:     true,
+			pod:        guaranteedPod(map[string]string{cpu: "2000m", memory: "3Gi"}),
+			wantStatus: cannotAlign,
+		},
+		{
+			name:        "option enabled, CPU alignment required by the pod",
+			nrt:         podLevel,
+			shared:      true,
+			annotations: map[string]string{AnnotationRequiredResources: cpu},
+			pod:         guaranteedPod(map[string]string{cpu: "500m", memory: "3Gi"}),
+			wantStatus:  cannotAlign,
+		},
+		{
+			name: "option disabled, mixed containers",
+			nrt:  podLevel,
+			pod: guaranteedPod(
+				map[string]string{cpu: "2", memory: "1Gi"},
+				map[string]string{cpu: "500m", memory: "512Mi"},
+			),
+			wantStatus: cannotAlign,
+		},
+		{
+			name:   "option enabled, mixed containers",
+			nrt:    podLevel,
+			shared: true,
+			pod: guaranteedPod(
+				map[string]string{cpu: "2", memory: "1Gi"},
+				map[string]string{cpu: "500m", memory: "512Mi"},
+			),
+		},
+		{
+			name:   "option enabled, container scope",
+			nrt:    containerLevel,
+			shared: true,
+			pod:    guaranteedPod(map[string]string{cpu: "500m", memory: "3Gi"}),
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{podLevel, containerLevel} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:            nrtcache.NewPassthrough(fakeClient),
+				fractionalCPUShared: tt.shared,
+			}
+			tt.pod.Annotations = tt.annotations
+			node := makeNodeFromNodeResourceTopology(tt.nrt)
+			// enough CPUs at node level for all the pods, so only the NUMA alignment can reject them
+			node.Status.Allocatable[v1.ResourceCPU] = resource.MustParse("4")
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyStaticCPUManagerPolicy(t *testing.T) {
+	// the NUMA node 0 has 2 whole CPUs free, the NUMA node 1 none
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "2500m"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "0"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	podLevel := makeNRT("static-pod-level", topologyv1alpha2.SingleNUMANodePodLevel)
+	containerLevel := makeNRT("static-container-level", topologyv1alpha2.SingleNUMANodeContainerLevel)
+
+	guaranteedPod := func(cntReq ...map[string]string) *v1.Pod {
+		return makePod("guaranteed", withMultiContainers(parseContainerRes(cntReq)))
+	}
+	cannotAlign := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+	cannotAlignContainer := framework.NewStatus(framework.Unschedulable, "cannot align container")
+
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		static     bool
+		pod        *v1.Pod
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "option disabled, millicores checked",
+			nrt:        podLevel,
+			pod:        guaranteedPod(map[string]string{cpu: "2800m", memory: "1Gi"}),
+			wantStatus: cannotAlign,
+		},
+		{
+			name:       "option enabled, fractional CPUs checked on the millicores",
+			nrt:        podLevel,
+			static:     true,
+			pod:        guaranteedPod(map[string]string{cpu: "2800m", memory: "1Gi"}),
+			wantStatus: cannotAlign,
+		},
+		{
+			name:   "option enabled, fractional CPUs fit the millicores",
+			nrt:    podLevel,
+			static: true,
+			pod:    guaranteedPod(map[string]string{cpu: "2300m", memory: "1Gi"}),
+		},
+		{
+			name:       "option enabled, integer CPUs exceed the whole CPUs",
+			nrt:        podLevel,
+			static:     true,
+			pod:        guaranteedPod(map[string]string{cpu: "3", memory: "1Gi"}),
+			wantStatus: cannotAlign,
+		},
+		{
+			name:   "option enabled, integer CPUs fit the whole CPUs",
+			nrt:    podLevel,
+			static: true,
+			pod:    guaranteedPod(map[string]string{cpu: "2", memory: "1Gi"}),
+		},
+		{
+			name:   "option enabled, integer CPUs of the pod made of fractional containers",
+			nrt:    podLevel,
+			static: true,
+			pod: guaranteedPod(
+				map[string]string{cpu: "1500m", memory: "1Gi"},
+				map[string]string{cpu: "1500m", memory: "1Gi"},
+			),
+			wantStatus: cannotAlign,
+		},
+		{
+			name:       "option disabled, container scope",
+			nrt:        containerLevel,
+			pod:        guaranteedPod(map[string]string{cpu: "1500m", memory: "1Gi"}, map[string]string{cpu: "1500m", memory: "1Gi"}),
+			wantStatus: cannotAlignContainer,
+		},
+		{
+			name:       "option enabled, container scope, fractional CPUs checked on the millicores",
+			nrt:        containerLevel,
+			static:     true,
+			pod:        guaranteedPod(map[string]string{cpu: "1500m", memory: "1Gi"}, map[string]string{cpu: "1500m", memory: "1Gi"}),
+			wantStatus: cannotAlignContainer,
+		},
+		{
+			name:   "option enabled, container scope, integer and fractional CPUs",
+			nrt:    containerLevel,
+			static: true,
+			pod:    guaranteedPod(map[string]string{cpu: "2", memory: "1Gi"}, map[string]string{cpu: "500m", memory: "1Gi"}),
+		},
+		{
+			name:       "option enabled, container scope, integer CPUs exceed the whole CPUs",
+			nrt:        containerLevel,
+			static:     true,
+			pod:        guaranteedPod(map[string]string{cpu: "1", memory: "1Gi"}, map[string]string{cpu: "2", memory: "1Gi"}),
+			wantStatus: cannotAlignContainer,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{podLevel, containerLevel} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:         nrtcache.NewPassthrough(fakeClient),
+				staticCPUManager: tt.static,
+			}
+			node := makeNodeFromNodeResourceTopology(tt.nrt)
+			// enough CPUs at node level for all the pods, so only the NUMA alignment can reject them
+			node.Status.Allocatable[v1.ResourceCPU] = resource.MustParse("4")
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyGuaranteedPodsRejectedOnNonePolicy(t *testing.T) {
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	nonePolicy := makeNRT("none-policy", topologyv1alpha2.None)
+	singleNUMA := makeNRT("single-numa-policy", topologyv1alpha2.SingleNUMANodePodLevel)
+	restricted := makeNRT("restricted-policy", topologyv1alpha2.RestrictedPodLevel)
+
+	cntReq := []map[string]string{{cpu: "2", memory: "1Gi"}}
+	guaranteedPod := makePod("guaranteed", withMultiContainers(parseContainerRes(cntReq)))
+	burstablePod := makePod("burstable", withMultiContainers(parseContainerRes(cntReq)))
+	burstablePod.Spec.Containers[0].Resources.Limits = nil
+	rejected := framework.NewStatus(framework.UnschedulableAndUnresolvable, "guaranteed pods not allowed: node topology manager policy is none")
+
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		enabled    bool
+		policies   []string
+		pod        *v1.Pod
+		wantStatus *framework.Status
+	}{
+		{
+			name: "option disabled, guaranteed pod on none policy",
+			nrt:  nonePolicy,
+			pod:  guaranteedPod,
+		},
+		{
+			name:       "option enabled, guaranteed pod on none policy",
+			nrt:        nonePolicy,
+			enabled:    true,
+			pod:        guaranteedPod,
+			wantStatus: rejected,
+		},
+		{
+			name:    "option enabled, burstable pod on none policy",
+			nrt:     nonePolicy,
+			enabled: true,
+			pod:     burstablePod,
+		},
+		{
+			name:    "option enabled, guaranteed pod on single-numa-node policy",
+			nrt:     singleNUMA,
+			enabled: true,
+			pod:     guaranteedPod,
+		},
+		{
+			name:     "option enabled, guaranteed pod on policy not enabled",
+			nrt:      restricted,
+			enabled:  true,
+			policies: []string{string(kubeletconfig.SingleNumaNodeTopologyManagerPolicy)},
+			pod:      guaranteedPod,
+		},
+		{
+			name:       "option enabled, guaranteed pod on none policy not enabled",
+			nrt:        nonePolicy,
+			enabled:    true,
+			policies:   []string{string(kubeletconfig.SingleNumaNodeTopologyManagerPolicy)},
+			pod:        guaranteedPod,
+			wantStatus: rejected,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{nonePolicy, singleNUMA, restricted} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:                nrtcache.NewPassthrough(fakeClient),
+				guaranteedOffNonePolicy: tt.enabled,
+			}
+			if len(tt.policies) > 0 {
+				tm.enabledPolicies = sets.New[string](tt.policies...)
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyBurstFactor(t *testing.T) {
+	// the NUMA node 0 as the pods requesting 1 CPU each get packed on it, the NUMA node 1 being full
+	nrts := make([]*topologyv1alpha2.NodeResourceTopology, 0, 4)
+	for packed := 0; packed < 4; packed++ {
+		nrts = append(nrts, &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: fmt.Sprintf("packed-%d", packed)},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", fmt.Sprintf("%d", 4-packed)),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "0"),
+						MakeTopologyResInfo(memory, "8Gi", "0"),
+					},
+				},
+			},
+		})
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the CPUs of the pods are required to be aligned, so their NUMA capacity is checked regardless of the QoS
+	burstablePod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "burstable",
+			Annotations: map[string]string{AnnotationRequiredResources: cpu},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: containerName,
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("1"),
+							v1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+						Limits: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("2"),
+							v1.ResourceMemory: resource.MustParse("2Gi"),
+						},
+					},
+				},
+			},
+		},
+	}
+	guaranteedPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+
+	tests := []struct {
+		name         string
+		burstPercent int64
+		pod          *v1.Pod
+		expected     int
+	}{
+		{
+			name:     "no burst factor",
+			pod:      burstablePod,
+			expected: 4,
+		},
+		{
+			name:         "burst factor",
+			burstPercent: 125,
+			pod:          burstablePod,
+			expected:     3,
+		},
+		{
+			name:         "burst factor, guaranteed pods unaffected",
+			burstPercent: 125,
+			pod:          guaranteedPod,
+			expected:     4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:           nrtcache.NewPassthrough(fakeClient),
+				burstFactorPercent: tt.burstPercent,
+			}
+			packed := 0
+			for _, nrt := range nrts {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+				if status := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo); status != nil {
+					break
+				}
+				packed++
+			}
+			if packed != tt.expected {
+				t.Errorf("packed pods: got=%d expected=%d", packed, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyContainerScopeForceSameNUMA(t *testing.T) {
+	makeNRT := func(name, numa1CPUs string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, numa1CPUs, numa1CPUs),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	// no NUMA node fits both containers
+	tight := makeNRT("tight", "4")
+	// only the second NUMA node fits both containers
+	roomy := makeNRT("roomy", "8")
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{tight, roomy} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pod := makePod("testpod", withMultiContainers(parseContainerRes([]map[string]string{
+		{cpu: "3", memory: "1Gi"},
+		{cpu: "3", memory: "1Gi"},
+	})))
+
+	tests := []struct {
+		name          string
+		nrt           *topologyv1alpha2.NodeResourceTopology
+		sameNUMA      bool
+		wantStatus    *framework.Status
+		wantNUMANodes map[string]int
+	}{
+		{
+			name:          "containers spread across NUMA nodes",
+			nrt:           tight,
+			wantNUMANodes: map[string]int{"cnt-1": 0, "cnt-2": 1},
+		},
+		{
+			name:       "option enabled, no NUMA node fits all the containers",
+			nrt:        tight,
+			sameNUMA:   true,
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align containers on the same NUMA node"),
+		},
+		{
+			name:          "option disabled, first container on the first NUMA node",
+			nrt:           roomy,
+			wantNUMANodes: map[string]int{"cnt-1": 0, "cnt-2": 1},
+		},
+		{
+			name:          "option enabled, containers co-located",
+			nrt:           roomy,
+			sameNUMA:      true,
+			wantNUMANodes: map[string]int{"cnt-1": 1, "cnt-2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakePlacementRecorder{}
+			tm := TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				containerScopeSameNUMA: tt.sameNUMA,
+				placementRecorder:      recorder,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			gotStatus := tm.Filter(context.Background(), state, pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Fatalf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+			if gotStatus != nil {
+				return
+			}
+
+			tm.Reserve(context.Background(), state, pod, tt.nrt.Name)
+			if len(recorder.placements) != 1 || !reflect.DeepEqual(recorder.placements[0].containerNUMANodes, tt.wantNUMANodes) {
+				t.Errorf("placements=%v, want NUMA nodes: %v", recorder.placements, tt.wantNUMANodes)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyContainerScopeInitSameNUMA(t *testing.T) {
+	makeNRT := func(name, numa0Memory string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, numa0Memory, numa0Memory),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	// only the second NUMA node fits the app container, both fit the init container
+	shared := makeNRT("shared", "8Gi")
+	// only the first NUMA node fits the init container
+	apart := makeNRT("apart", "16Gi")
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{shared, apart} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	makeInitPod := func(initMemory string) *v1.Pod {
+		pod := makePod("testpod",
+			withMultiInitContainers(parseContainerRes([]map[string]string{{cpu: "2", memory: initMemory}})),
+			withMultiContainers(parseContainerRes([]map[string]string{{cpu: "6", memory: "1Gi"}})),
+		)
+		pod.Spec.InitContainers[0].Name = "init"
+		return pod
+	}
+
+	tests := []struct {
+		name          string
+		nrt           *topologyv1alpha2.NodeResourceTopology
+		pod           *v1.Pod
+		sameNUMA      bool
+		wantStatus    *framework.Status
+		wantNUMANodes map[string]int
+	}{
+		{
+			name:          "option disabled, init container on the first NUMA node",
+			nrt:           shared,
+			pod:           makeInitPod("1Gi"),
+			wantNUMANodes: map[string]int{"init": 0, "cnt-1": 1},
+		},
+		{
+			name:          "option enabled, init container on the NUMA node of the app container",
+			nrt:           shared,
+			pod:           makeInitPod("1Gi"),
+			sameNUMA:      true,
+			wantNUMANodes: map[string]int{"init": 1, "cnt-1": 1},
+		},
+		{
+			name:          "option disabled, containers on different NUMA nodes",
+			nrt:           apart,
+			pod:           makeInitPod("12Gi"),
+			wantNUMANodes: map[string]int{"init": 0, "cnt-1": 1},
+		},
+		{
+			name:       "option enabled, no common NUMA node",
+			nrt:        apart,
+			pod:        makeInitPod("12Gi"),
+			sameNUMA:   true,
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align init containers with the app containers"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakePlacementRecorder{}
+			tm := TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				initContainersSameNUMA: tt.sameNUMA,
+				placementRecorder:      recorder,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			gotStatus := tm.Filter(context.Background(), state, tt.pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Fatalf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+			if gotStatus != nil {
+				return
+			}
+
+			tm.Reserve(context.Background(), state, tt.pod, tt.nrt.Name)
+			if len(recorder.placements) != 1 || !reflect.DeepEqual(recorder.placements[0].containerNUMANodes, tt.wantNUMANodes) {
+				t.Errorf("placements=%v, want NUMA nodes: %v", recorder.placements, tt.wantNUMANodes)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyRestrictedAsSingleNUMA(t *testing.T) {
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	podLevel := makeNRT("restricted-pod", topologyv1alpha2.RestrictedPodLevel)
+	containerLevel := makeNRT("restricted-container", topologyv1alpha2.RestrictedContainerLevel)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{podLevel, containerLevel} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// fits on the node, but on no single NUMA node
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+
+	tests := []struct {
+		name                   string
+		nrt                    *topologyv1alpha2.NodeResourceTopology
+		restrictedAsSingleNUMA bool
+		wantConfig             TopologyManagerConfig
+		wantStatus             *framework.Status
+	}{
+		{
+			name: "pod scope, not filtered",
+			nrt:  podLevel,
+			wantConfig: TopologyManagerConfig{
+				Policy: kubeletconfig.RestrictedTopologyManagerPolicy,
+				Scope:  kubeletconfig.PodTopologyManagerScope,
+			},
+		},
+		{
+			name:                   "pod scope, handled as single-numa-node",
+			nrt:                    podLevel,
+			restrictedAsSingleNUMA: true,
+			wantConfig: TopologyManagerConfig{
+				Policy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:  kubeletconfig.PodTopologyManagerScope,
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name: "container scope, not filtered",
+			nrt:  containerLevel,
+			wantConfig: TopologyManagerConfig{
+				Policy: kubeletconfig.RestrictedTopologyManagerPolicy,
+				Scope:  kubeletconfig.ContainerTopologyManagerScope,
+			},
+		},
+		{
+			name:                   "container scope, handled as single-numa-node",
+			nrt:                    containerLevel,
+			restrictedAsSingleNUMA: true,
+			wantConfig: TopologyManagerConfig{
+				Policy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+				Scope:  kubeletconfig.ContainerTopologyManagerScope,
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				restrictedAsSingleNUMA: tt.restrictedAsSingleNUMA,
+			}
+			if gotConfig := tm.topologyManagerConfig(tt.nrt); !reflect.DeepEqual(gotConfig, tt.wantConfig) {
+				t.Errorf("config does not match: %+v, want: %+v", gotConfig, tt.wantConfig)
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyEnabledPolicies(t *testing.T) {
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	restricted := makeNRT("restricted", topologyv1alpha2.RestrictedPodLevel)
+	singleNUMA := makeNRT("single-numa-node", topologyv1alpha2.SingleNUMANodePodLevel)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{restricted, singleNUMA} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// fits on the node, but on no single NUMA node
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+
+	tests := []struct {
+		name            string
+		nrt             *topologyv1alpha2.NodeResourceTopology
+		enabledPolicies []string
+		wantPolicy      string
+		wantStatus      *framework.Status
+	}{
+		{
+			name:       "all policies enabled, restricted handled as single-numa-node",
+			nrt:        restricted,
+			wantPolicy: kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:            "restricted not enabled",
+			nrt:             restricted,
+			enabledPolicies: []string{kubeletconfig.SingleNumaNodeTopologyManagerPolicy},
+			wantPolicy:      kubeletconfig.NoneTopologyManagerPolicy,
+		},
+		{
+			name:            "restricted enabled",
+			nrt:             restricted,
+			enabledPolicies: []string{kubeletconfig.SingleNumaNodeTopologyManagerPolicy, kubeletconfig.RestrictedTopologyManagerPolicy},
+			wantPolicy:      kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+			wantStatus:      framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:            "single-numa-node enabled",
+			nrt:             singleNUMA,
+			enabledPolicies: []string{kubeletconfig.SingleNumaNodeTopologyManagerPolicy},
+			wantPolicy:      kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+			wantStatus:      framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:            "single-numa-node not enabled",
+			nrt:             singleNUMA,
+			enabledPolicies: []string{kubeletconfig.RestrictedTopologyManagerPolicy},
+			wantPolicy:      kubeletconfig.NoneTopologyManagerPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				restrictedAsSingleNUMA: true,
+				enabledPolicies:        sets.New(tt.enabledPolicies...),
+			}
+			if gotConfig := tm.topologyManagerConfig(tt.nrt); gotConfig.Policy != tt.wantPolicy {
+				t.Errorf("policy does not match: %q, want: %q", gotConfig.Policy, tt.wantPolicy)
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSingleNUMANodeDeviceAllocation(t *testing.T) {
+	const exclusiveDevice = "vendor.com/gpu"
+	const sharedDevice = "vendor.com/vgpu"
+
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(exclusiveDevice, "2", "2"),
+					MakeTopologyResInfo(sharedDevice, "1", "1"),
+				},
+			},
+			{
+				// no CPUs left for the containers
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "0"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(exclusiveDevice, "2", "2"),
+				},
+			},
+		},
+	}
+	node := makeNodeFromNodeResourceTopology(nrt)
+	// the device plugin advertises the shared device many times at node level
+	node.Status.Allocatable[sharedDevice] = resource.MustParse("8")
+
+	sharedSpec := func(sharingCap int64) []apiconfig.DeviceAllocationSpec {
+		return []apiconfig.DeviceAllocationSpec{
+			{Name: exclusiveDevice, Mode: apiconfig.DeviceAllocationExclusive},
+			{Name: sharedDevice, Mode: apiconfig.DeviceAllocationShared, SharingCap: sharingCap},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		cntReq     []map[string]string
+		allocation []apiconfig.DeviceAllocationSpec
+		wantStatus *framework.Status
+	}{
+		{
+			name: "exclusive devices fit",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", exclusiveDevice: "1"},
+				{cpu: "1", memory: "1Gi", exclusiveDevice: "1"},
+			},
+			allocation: sharedSpec(0),
+			wantStatus: nil,
+		},
+		{
+			name: "exclusive devices are subtracted",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", exclusiveDevice: "1"},
+				{cpu: "1", memory: "1Gi", exclusiveDevice: "1"},
+				{cpu: "1", memory: "1Gi", exclusiveDevice: "1"},
+			},
+			allocation: sharedSpec(0),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+		{
+			name: "shared devices not configured, counted as exclusive",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", sharedDevice: "1"},
+				{cpu: "1", memory: "1Gi", sharedDevice: "1"},
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+		{
+			name: "shared devices are not subtracted",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", sharedDevice: "1"},
+				{cpu: "1", memory: "1Gi", sharedDevice: "1"},
+				{cpu: "1", memory: "1Gi", sharedDevice: "1"},
+			},
+			allocation: sharedSpec(0),
+			wantStatus: nil,
+		},
+		{
+			name: "shared devices within the sharing cap",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", sharedDevice: "2"},
+			},
+			allocation: sharedSpec(2),
+			wantStatus: nil,
+		},
+		{
+			name: "shared devices exceeding the sharing cap",
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "1Gi", sharedDevice: "3"},
+			},
+			allocation: sharedSpec(2),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container"),
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:      nrtcache.NewPassthrough(fakeClient),
+				sharedDevices: newSharedDevices(tt.allocation),
+			}
+
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(tt.cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSingleNUMANodeHintProviders(t *testing.T) {
+	const device = "vendor.com/gpu"
+
+	makeNRT := func(name string, attrs topologyv1alpha2.AttributeList) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Attributes: append(topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: "single-numa-node"},
+				{Name: AttributeScope, Value: "pod"},
+			}, attrs...),
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+						MakeTopologyResInfo(device, "1", "1"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+						MakeTopologyResInfo(device, "1", "1"),
+					},
+				},
+			},
+		}
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("all-managers", nil),
+		makeNRT("device-manager-only", topologyv1alpha2.AttributeList{
+			{Name: AttributeHintProviders, Value: "device"},
+		}),
+		makeNRT("cpu-and-device-managers", topologyv1alpha2.AttributeList{
+			{Name: AttributeHintProviders, Value: "cpu,device"},
+		}),
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		nodeName   string
+		cntReq     []map[string]string
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "CPU spanning NUMA nodes, all managers",
+			nodeName:   "all-managers",
+			cntReq:     []map[string]string{{cpu: "6", memory: "1Gi", device: "1"}},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:       "CPU spanning NUMA nodes, device manager only",
+			nodeName:   "device-manager-only",
+			cntReq:     []map[string]string{{cpu: "6", memory: "10Gi", device: "1"}},
+			wantStatus: nil,
+		},
+		{
+			name:       "devices still constrain, device manager only",
+			nodeName:   "device-manager-only",
+			cntReq:     []map[string]string{{cpu: "1", memory: "1Gi", device: "2"}},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:       "memory spanning NUMA nodes, CPU and device managers",
+			nodeName:   "cpu-and-device-managers",
+			cntReq:     []map[string]string{{cpu: "2", memory: "10Gi", device: "1"}},
+			wantStatus: nil,
+		},
+		{
+			name:       "CPU spanning NUMA nodes, CPU and device managers",
+			nodeName:   "cpu-and-device-managers",
+			cntReq:     []map[string]string{{cpu: "6", memory: "1Gi", device: "1"}},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+
+			var nodeNRT *topologyv1alpha2.NodeResourceTopology
+			for _, nrt := range nrts {
+				if nrt.Name == tt.nodeName {
+					nodeNRT = nrt
+				}
+			}
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(tt.cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nodeNRT))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNodeResourceTopologyBitmaskEmptyMetric(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "4Gi", "2Gi"),
+					MakeTopologyResInfo(nicResourceName, "2", "1"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "4Gi", "2Gi"),
+					MakeTopologyResInfo(nicResourceName, "2", "1"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	registerFilterMetrics()
+
+	// the node-level availability fits all the pods, each NUMA node falls short of a single resource
+	tests := []struct {
+		name     string
+		requests v1.ResourceList
+		resource v1.ResourceName
+	}{
+		{
+			name: "memory",
+			requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1"),
+				v1.ResourceMemory: resource.MustParse("3Gi"),
+			},
+			resource: v1.ResourceMemory,
+		},
+		{
+			name: "device",
+			requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+				nicResourceName:   resource.MustParse("2"),
+			},
+			resource: nicResourceName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			counted := []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, nicResourceName}
+			before := make(map[v1.ResourceName]float64)
+			for _, resName := range counted {
+				before[resName], _ = testutil.GetCounterMetricValue(alignmentBitmaskEmptyTotal.WithLabelValues(string(resName)))
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			wantStatus := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), makePodByResourceList(&tt.requests), nodeInfo)
+			if !reflect.DeepEqual(gotStatus, wantStatus) {
+				t.Fatalf("status does not match: %v, want: %v", gotStatus, wantStatus)
+			}
+
+			for _, resName := range counted {
+				after, err := testutil.GetCounterMetricValue(alignmentBitmaskEmptyTotal.WithLabelValues(string(resName)))
+				if err != nil {
+					t.Fatalf("cannot read the metric: %v", err)
+				}
+				var expected float64
+				if resName == tt.resource {
+					expected = 1
+				}
+				if delta := after - before[resName]; delta != expected {
+					t.Errorf("resource %q: metric increased by %v expected %v", resName, delta, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceRoundingRoundUp(t *testing.T) {
+	rr := newResourceRounding([]apiconfig.ResourceRoundingSpec{
+		{Name: "vendor.com/dev", Multiple: 4},
+	})
+
+	tests := []struct {
+		resName  v1.ResourceName
+		quantity string
+		expected string
+	}{
+		{resName: "vendor.com/dev", quantity: "0", expected: "0"},
+		{resName: "vendor.com/dev", quantity: "1", expected: "4"},
+		{resName: "vendor.com/dev", quantity: "4", expected: "4"},
+		{resName: "vendor.com/dev", quantity: "5", expected: "8"},
+		{resName: "vendor.com/other", quantity: "5", expected: "5"},
+	}
+
+	for _, tt := range tests {
+		got := rr.roundUp(tt.resName, resource.MustParse(tt.quantity))
+		if got.Cmp(resource.MustParse(tt.expected)) != 0 {
+			t.Errorf("resource=%s quantity=%s got=%s expected=%s", tt.resName, tt.quantity, got.String(), tt.expected)
+		}
+	}
+}
+
+func makeNodeFromNodeResourceTopology(nrt *topologyv1alpha2.NodeResourceTopology) *v1.Node {
+	res := makeResourceListFromZones(nrt.Zones)
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nrt.Name,
+		},
+		Status: v1.NodeStatus{
+			Capacity:    res,
+			Allocatable: res,
+		},
+	}
+}
+
+func findAvailableResourceByName(resourceInfoList topologyv1alpha2.ResourceInfoList, name string) resource.Quantity {
+	for _, resourceInfo := range resourceInfoList {
+		if resourceInfo.Name == name {
+			return resourceInfo.Available
+		}
+	}
+	return resource.MustParse("0")
+}
+
+func makePod(name string, options ...func(*v1.Pod)) *v1.Pod {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	for _, o := range options {
+		o(&pod)
+	}
+	return &pod
+}
+
+func withMultiContainers(resourcesList []v1.ResourceList) func(*v1.Pod) {
+	return func(pod *v1.Pod) {
+		var containers []v1.Container
+
+		for idx := range resourcesList {
+			res := cloneResourceList(resourcesList[idx])
+			containers = append(containers, v1.Container{
+				Name: fmt.Sprintf("cnt-%d", idx+1),
+				Resources: v1.ResourceRequirements{
+					Requests: res,
+					Limits:   res,
+				},
+			})
+		}
+		pod.Spec.Containers = containers
+	}
+}
+
+func withMultiInitContainers(resourcesList []v1.ResourceList) func(*v1.Pod) {
+	return func(pod *v1.Pod) {
+		p := &v1.Pod{}
+		f := withMultiContainers(resourcesList)
+		f(p)
+		pod.Spec.InitContainers = p.Spec.Containers
+	}
+}
+
+func cloneResourceList(rl v1.ResourceList) v1.ResourceList {
+	res := make(v1.ResourceList)
+	for name, qty := range rl {
+		res[name] = qty
+	}
+	return res
+}
+
+func parseTestUserEntry(entries []testUserEntry) []testEntry {
+	var teList []testEntry
+	for i, e := range entries {
+		irl := parseContainerRes(e.initCntReq)
+		rl := parseContainerRes(e.cntReq)
+		pod := makePod(fmt.Sprintf("testpod%d", i), withMultiInitContainers(irl), withMultiContainers(rl))
+		te := testEntry{
+			name:       e.description,
+			pod:        pod,
+			wantStatus: parseState(e.statusErr),
+		}
+		teList = append(teList, te)
+	}
+	return teList
+}
+
+func parseContainerRes(cntRes []map[string]string) []v1.ResourceList {
+	rll := []v1.ResourceList{}
+	for i := 0; i < len(cntRes); i++ {
+		resMap := cntRes[i]
+
+		rl := v1.ResourceList{}
+		for k, v := range resMap {
+			rl[v1.ResourceName(k)] = resource.MustParse(v)
+		}
+		rll = append(rll, rl)
+	}
+
+	return rll
+}
+
+func parseState(error string) *framework.Status {
+	if len(error) == 0 {
+		return nil
+	}
+
+	return framework.NewStatus(framework.Unschedulable, error)
+}
+
+func TestFilterHandlerFromTopologyManagerConfig(t *testing.T) {
+	// functions can't be compared, their names can
+	handlerName := func(handler filterFn) string {
+		if handler == nil {
+			return "<nil>"
+		}
+		return runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	}
+
+	policies := []string{
+		kubeletconfig.NoneTopologyManagerPolicy,
+		kubeletconfig.BestEffortTopologyManagerPolicy,
+		kubeletconfig.RestrictedTopologyManagerPolicy,
+		kubeletconfig.SingleNumaNodeTopologyManagerPolicy,
+	}
+	scopes := []string{
+		kubeletconfig.PodTopologyManagerScope,
+		kubeletconfig.ContainerTopologyManagerScope,
+		"unknown",
+	}
+	expected := map[string]filterFn{
+		kubeletconfig.SingleNumaNodeTopologyManagerPolicy + "/" + kubeletconfig.PodTopologyManagerScope:       singleNUMAPodLevelHandler,
+		kubeletconfig.SingleNumaNodeTopologyManagerPolicy + "/" + kubeletconfig.ContainerTopologyManagerScope: singleNUMAContainerLevelHandler,
+	}
+
+	for _, restrictedAsSingleNUMA := range []bool{false, true} {
+		for _, policy := range policies {
+			for _, scope := range scopes {
+				conf := TopologyManagerConfig{Policy: policy, Scope: scope}
+				// the restricted policy is handled as single-numa-node when resolving the configuration of the node
+				tm := TopologyMatch{
+					policyResolver:         OverlayPolicyResolver{Config: conf},
+					restrictedAsSingleNUMA: restrictedAsSingleNUMA,
+				}
+				effectivePolicy := policy
+				if restrictedAsSingleNUMA && policy == kubeletconfig.RestrictedTopologyManagerPolicy {
+					effectivePolicy = kubeletconfig.SingleNumaNodeTopologyManagerPolicy
+				}
+
+				got := handlerName(filterHandlerFromTopologyManagerConfig(tm.topologyManagerConfig(&topologyv1alpha2.NodeResourceTopology{})))
+				want := handlerName(expected[effectivePolicy+"/"+scope])
+				if got != want {
+					t.Errorf("policy=%s scope=%s restrictedAsSingleNUMA=%v: handler=%s expected=%s", policy, scope, restrictedAsSingleNUMA, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFilterExemptNamespaces(t *testing.T) {
+	// no NUMA node can host the pod
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		wantStatus *framework.Status
+	}{
+		{
+			name:      "exempt namespace",
+			namespace: "kube-system",
+		},
+		{
+			name:       "not exempt namespace",
+			namespace:  "default",
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("3"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			})
+			pod.Namespace = tt.namespace
+
+			tm := TopologyMatch{
+				nrtCache:         nrtcache.NewPassthrough(fakeClient),
+				exemptNamespaces: sets.New("kube-system", "kube-public"),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			state := framework.NewCycleState()
+			gotStatus := tm.Filter(context.Background(), state, pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+			_, err := state.Read(overReservedStateKey(nrt.Name))
+			if overReserved := err == nil; overReserved != (tt.wantStatus != nil) {
+				t.Errorf("node marked maybe over-reserved: %v", overReserved)
+			}
+		})
+	}
 }
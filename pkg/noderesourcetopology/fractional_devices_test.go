@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+// gpuSlice is a device some producers report in fractions, e.g. "0.5" for half of a shared GPU.
+const gpuSlice = "vendor.com/gpu-slice"
+
+func TestFilterFractionalDeviceQuantities(t *testing.T) {
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy, numa0Slices, numa1Slices string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+						MakeTopologyResInfo(gpuSlice, "1.5", numa0Slices),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+						MakeTopologyResInfo(gpuSlice, "1.5", numa1Slices),
+					},
+				},
+			},
+		}
+	}
+	// 1.5 slices available in total on each node, split differently across the NUMA nodes
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("container-scope", topologyv1alpha2.SingleNUMANodeContainerLevel, "1", "0.5"),
+		makeNRT("pod-scope-split", topologyv1alpha2.SingleNUMANodePodLevel, "0.5", "1"),
+		makeNRT("pod-scope-fragmented", topologyv1alpha2.SingleNUMANodePodLevel, "0.75", "0.75"),
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		containers int
+		expected   framework.Code
+	}{
+		{
+			name:       "container scope, slices of both NUMA nodes used up",
+			nrt:        nrts[0],
+			containers: 3,
+			expected:   framework.Success,
+		},
+		{
+			name:       "container scope, one slice too many",
+			nrt:        nrts[0],
+			containers: 4,
+			expected:   framework.Unschedulable,
+		},
+		{
+			name:       "pod scope, fitting the NUMA node with most slices",
+			nrt:        nrts[1],
+			containers: 2,
+			expected:   framework.Success,
+		},
+		{
+			name:       "pod scope, slices fragmented across the NUMA nodes",
+			nrt:        nrts[2],
+			containers: 2,
+			expected:   framework.Unschedulable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			resources := make([]v1.ResourceList, 0, tt.containers)
+			for i := 0; i < tt.containers; i++ {
+				resources = append(resources, v1.ResourceList{
+					v1.ResourceCPU:            resource.MustParse("1"),
+					v1.ResourceMemory:         resource.MustParse("1Gi"),
+					v1.ResourceName(gpuSlice): resource.MustParse("0.5"),
+				})
+			}
+			pod := makePodByResourceLists(resources...)
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if status.Code() != tt.expected {
+				t.Errorf("unexpected status: got %v, expected code %v", status, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSubtractFromNUMAFractionalQuantities(t *testing.T) {
+	numaNodes := NUMANodeList{
+		{
+			NUMAID: 0,
+			Resources: v1.ResourceList{
+				v1.ResourceName(gpuSlice): resource.MustParse("1.5"),
+			},
+		},
+	}
+	slice := resource.MustParse("0.5")
+
+	for _, expected := range []string{"1", "0.5", "0"} {
+		available := numaNodes[0].Resources[gpuSlice]
+		if !isResourceSetSuitable(qosResourcePolicy{}, v1.PodQOSGuaranteed, gpuSlice, slice, available, nil) {
+			t.Fatalf("slice not fitting in %s available", available.String())
+		}
+		subtractFromNUMA(numaNodes, 0, v1.ResourceList{gpuSlice: slice}, nil)
+		got := numaNodes[0].Resources[gpuSlice]
+		if got.Cmp(resource.MustParse(expected)) != 0 {
+			t.Fatalf("available=%s expected=%s", got.String(), expected)
+		}
+	}
+
+	available := numaNodes[0].Resources[gpuSlice]
+	if available.Sign() != 0 {
+		t.Errorf("slices drifted to %s once used up", available.String())
+	}
+	if isResourceSetSuitable(qosResourcePolicy{}, v1.PodQOSGuaranteed, gpuSlice, slice, available, nil) {
+		t.Errorf("slice fitting once the slices are used up")
+	}
+}
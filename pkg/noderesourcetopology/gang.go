@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/resourcerequests"
+)
+
+// AlignGang evaluates the pods of a gang jointly against the NUMA nodes of a node with the given topology, returning
+// the NUMA node assigned to each pod, in the order of the pods, -1 for the pods with nothing to align. Unlike placing
+// the pods one by one, which can fragment the NUMA nodes and fail the gang, the assignments of the pods placed first
+// are revisited until all the pods fit, each assigned pod consuming the resources of its NUMA node. The checks are the
+// ones of the filter, with the default args. Meant for the coscheduling integrations, which are expected to make the
+// kubelet honor the assignment. The search is exhaustive, so the gangs should be small.
+// Returns an error if the NRT object is invalid, the kubelet wouldn't assign the pods to a single NUMA node each on such
+// a node, or the pods can't be aligned together.
+func AlignGang(nodeTopology *topologyv1alpha2.NodeResourceTopology, pods []*v1.Pod) ([]int, error) {
+	if errs := ValidateNRT(nodeTopology); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	conf := ResolveConfig(nodeTopology)
+	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy || conf.Scope != kubeletconfig.PodTopologyManagerScope {
+		return nil, fmt.Errorf("topology manager policy %q with scope %q doesn't assign the pods to a single NUMA node", conf.Policy, conf.Scope)
+	}
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeTopology.Name},
+		Status: v1.NodeStatus{
+			Capacity:    makeResourceListFromZones(nodeTopology.Zones),
+			Allocatable: makeResourceListFromZones(nodeTopology.Zones),
+		},
+	})
+	numaNodes := createNUMANodeList(nodeTopology.Zones)
+	numaIDs := make([]int, 0, len(numaNodes))
+	for _, numaNode := range numaNodes {
+		numaIDs = append(numaIDs, numaNode.NUMAID)
+	}
+	sort.Ints(numaIDs)
+
+	ga := &gangAlignment{
+		nodeTopology: nodeTopology,
+		nodeInfo:     nodeInfo,
+		conf:         conf,
+		numaIDs:      numaIDs,
+		pods:         pods,
+		assignment:   make([]int, len(pods)),
+	}
+	if !ga.align(0, numaNodes) {
+		return nil, fmt.Errorf("cannot align the %d pods of the gang together", len(pods))
+	}
+	return ga.assignment, nil
+}
+
+type gangAlignment struct {
+	nodeTopology *topologyv1alpha2.NodeResourceTopology
+	nodeInfo     *framework.NodeInfo
+	conf         TopologyManagerConfig
+	numaIDs      []int
+	pods         []*v1.Pod
+	assignment   []int
+}
+
+// align assigns a NUMA node to the pods from the given index on, trying the NUMA nodes lowest ID first, and backtracks
+// if the following pods don't fit anymore. Returns true if all the pods were assigned.
+func (ga *gangAlignment) align(idx int, numaNodes NUMANodeList) bool {
+	if idx == len(ga.pods) {
+		return true
+	}
+	pod := ga.pods[idx]
+	qos := v1qos.GetPodQOS(pod)
+	// like the filter, the pods with nothing to align always fit
+	if (qos == v1.PodQOSBestEffort && !resourcerequests.IncludeNonNative(pod)) || resourcerequests.AreZeroForPod(pod) {
+		ga.assignment[idx] = -1
+		return ga.align(idx+1, numaNodes)
+	}
+
+	for _, numaID := range ga.numaIDs {
+		info := &filterInfo{
+			nodeName:          ga.nodeTopology.Name,
+			node:              ga.nodeInfo,
+			topologyManager:   ga.conf,
+			qos:               qos,
+			numaNodes:         copyNUMANodeList(numaNodes),
+			preferences:       numaPreferencesFromAnnotations(pod),
+			excludedNUMANodes: untoleratedNUMANodes(pod, ga.nodeTopology.Zones),
+		}
+		if _, missing := missingNodeResource(pod, info); missing {
+			return false
+		}
+		info.excludedNUMANodes = excludeNonLocalNUMANodes(info.excludedNUMANodes, info.numaNodes, map[int]bool{numaID: true}, "not the candidate NUMA node")
+		if status := singleNUMAPodLevelHandler(pod, info); status != nil {
+			continue
+		}
+		// the pods whose resources don't constrain the NUMA node are assigned the lowest one only
+		if info.chosenNUMANodes[0] != numaID {
+			continue
+		}
+		subtractFromNUMA(info.numaNodes, numaID, info.podAlignmentResources(pod), info.rounding)
+		ga.assignment[idx] = numaID
+		if ga.align(idx+1, info.numaNodes) {
+			return true
+		}
+	}
+	return false
+}
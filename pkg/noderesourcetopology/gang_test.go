@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAlignGang(t *testing.T) {
+	makeNode := func(policy topologyv1alpha2.TopologyManagerPolicy, numaCPUs ...string) *topologyv1alpha2.NodeResourceTopology {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+			TopologyPolicies: []string{string(policy)},
+		}
+		for numaID, cpus := range numaCPUs {
+			nrt.Zones = append(nrt.Zones, topologyv1alpha2.Zone{
+				Name: "node-" + string(rune('0'+numaID)),
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, cpus, cpus),
+					MakeTopologyResInfo(memory, "64Gi", "64Gi"),
+				},
+			})
+		}
+		return nrt
+	}
+	makeGang := func(cpus ...string) []*v1.Pod {
+		pods := make([]*v1.Pod, 0, len(cpus))
+		for _, podCPUs := range cpus {
+			pods = append(pods, makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(podCPUs),
+				v1.ResourceMemory: resource.MustParse("4Gi"),
+			}))
+		}
+		return pods
+	}
+
+	tests := []struct {
+		name        string
+		nrt         *topologyv1alpha2.NodeResourceTopology
+		pods        []*v1.Pod
+		expected    []int
+		expectedErr bool
+	}{
+		{
+			name:     "one pod per NUMA node",
+			nrt:      makeNode(topologyv1alpha2.SingleNUMANodePodLevel, "8", "8"),
+			pods:     makeGang("6", "6"),
+			expected: []int{0, 1},
+		},
+		{
+			name:     "smaller pod moved to the smaller NUMA node",
+			nrt:      makeNode(topologyv1alpha2.SingleNUMANodePodLevel, "4", "2"),
+			pods:     makeGang("2", "4"),
+			expected: []int{1, 0},
+		},
+		{
+			name:     "pods packed",
+			nrt:      makeNode(topologyv1alpha2.SingleNUMANodePodLevel, "6", "4"),
+			pods:     makeGang("2", "2", "4", "2"),
+			expected: []int{0, 0, 1, 0},
+		},
+		{
+			name:     "pods with nothing to align",
+			nrt:      makeNode(topologyv1alpha2.SingleNUMANodePodLevel, "4", "2"),
+			pods:     append(makeGang("2", "4"), makePodByResourceList(&v1.ResourceList{})),
+			expected: []int{1, 0, -1},
+		},
+		{
+			name:        "gang too large",
+			nrt:         makeNode(topologyv1alpha2.SingleNUMANodePodLevel, "4", "2"),
+			pods:        makeGang("2", "4", "2"),
+			expectedErr: true,
+		},
+		{
+			name:        "container scope",
+			nrt:         makeNode(topologyv1alpha2.SingleNUMANodeContainerLevel, "4", "2"),
+			pods:        makeGang("2", "4"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AlignGang(tt.nrt, tt.pods)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("assignment: got=%v expected=%v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAlignGangWhereSequentialPlacementFails(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					MakeTopologyResInfo(nicResourceName, "2", "2"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+				},
+			},
+		},
+	}
+	// the CPU-only member lands first on the NUMA node with the NICs, leaving too few CPUs to the NIC member
+	pods := []*v1.Pod{
+		makePodByResourceList(&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("4"),
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+		}),
+		makePodByResourceList(&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("6"),
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+			nicResourceName:   resource.MustParse("2"),
+		}),
+	}
+
+	aligned, err := AlignablePods(nrt, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aligned != 1 {
+		t.Fatalf("sequential placement aligned %d pods, expected 1", aligned)
+	}
+
+	got, err := AlignGang(nrt, pods)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int{1, 0}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("assignment: got=%v expected=%v", got, expected)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// AnnotationGatedNUMAPreference is the comma-separated list of the IDs of the NUMA nodes the pod prefers to be placed
+// on, e.g. "0" or "0,1". Expected to be set by the controller which removes the scheduling gate of the pod, once it
+// computed the preference.
+const AnnotationGatedNUMAPreference = AnnotationKeyPrefix + "gated-numa-preference"
+
+// gatedNUMAPreferenceComponent scores the fit of the pod on the NUMA nodes it prefers. It doesn't apply to the pods
+// without a well-formed preference.
+func (tm *TopologyMatch) gatedNUMAPreferenceComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	val, ok := pod.Annotations[AnnotationGatedNUMAPreference]
+	if !ok {
+		return 0, false, nil
+	}
+	ids, err := parseNUMANodeIDs(val)
+	if err != nil || len(ids) == 0 {
+		klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationGatedNUMAPreference, "value", val, "err", err)
+		return 0, false, nil
+	}
+	return gatedNUMAPreferenceScore(pod, createNUMANodeList(zones), ids), true, nil
+}
+
+// gatedNUMAPreferenceScore gives the maximum score if the pod fits on one of the preferred NUMA nodes, and the minimum
+// score otherwise, including on the nodes not having any of them.
+func gatedNUMAPreferenceScore(pod *v1.Pod, numaNodes NUMANodeList, numaIDs []int) int64 {
+	requests := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	for _, numaNode := range numaNodes {
+		for _, id := range numaIDs {
+			if numaNode.NUMAID == id && numaFitsRequests(requests, numaNode.Resources) {
+				return framework.MaxNodeScore
+			}
+		}
+	}
+	return framework.MinNodeScore
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// AnnotationHostPathNUMANode is the ID of the NUMA node the local devices the pod uses through hostPath volumes
+// are attached to, e.g. "1". The kubelet doesn't know the NUMA locality such devices get from their node path,
+// so the pod declares it, and the filter aligns the other resources of the pod on the same NUMA node.
+const AnnotationHostPathNUMANode = AnnotationKeyPrefix + "hostpath-numa-node"
+
+// hostPathNUMANode returns the NUMA node the pod declares local to its hostPath devices.
+// Returns false if the pod declares none, or the annotation is malformed.
+func hostPathNUMANode(pod *v1.Pod) (int, bool) {
+	val, ok := pod.Annotations[AnnotationHostPathNUMANode]
+	if !ok {
+		return 0, false
+	}
+	numaID, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil || numaID < 0 {
+		klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationHostPathNUMANode, "value", val)
+		return 0, false
+	}
+	return numaID, true
+}
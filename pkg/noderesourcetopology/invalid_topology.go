@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sync"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	invalidTopologyTransient  = "transient"
+	invalidTopologyPersistent = "persistent"
+)
+
+var invalidTopologyRejectionsTotal = metrics.NewCounterVec(
+	&metrics.CounterOpts{
+		Subsystem:      "scheduler_plugins",
+		Name:           "noderesourcetopology_invalid_topology_rejections_total",
+		Help:           "Number of pods rejected by the filter because of invalid node topology data, by persistence of the invalidity.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"persistence"},
+)
+
+// invalidTopologies counts, for each node, the consecutive observations of invalid NRT data by the filter, to tell the
+// transient invalidity from the persistent one. The filter runs for every node, and the data is valid most of the time,
+// so checking valid data only reads the map.
+type invalidTopologies struct {
+	// consecutive observations of invalid data after which the invalidity is persistent
+	threshold int64
+	// node name -> *int64 count of the consecutive observations of invalid data
+	nodes sync.Map
+}
+
+func newInvalidTopologies(threshold int64) *invalidTopologies {
+	return &invalidTopologies{
+		threshold: threshold,
+	}
+}
+
+// forgetDeletedNodes makes the tracker drop the streaks of the nodes deleted from the cluster.
+func (it *invalidTopologies) forgetDeletedNodes(nodeInformer k8scache.SharedInformer) {
+	nodeInformer.AddEventHandler(k8scache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(k8scache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			node, ok := obj.(*v1.Node)
+			if !ok {
+				return
+			}
+			it.nodes.Delete(node.Name)
+		},
+	})
+}
+
+// invalid records an observation of invalid NRT data for the node, and returns the status rejecting the pod,
+// tagged with the persistence of the invalidity.
+func (it *invalidTopologies) invalid(nodeName string) *framework.Status {
+	persistence := invalidTopologyTransient
+	if it != nil {
+		count, _ := it.nodes.LoadOrStore(nodeName, new(int64))
+		if atomic.AddInt64(count.(*int64), 1) >= it.threshold {
+			persistence = invalidTopologyPersistent
+		}
+	}
+	klog.V(2).InfoS("invalid topology data", "node", nodeName, "persistence", persistence)
+	invalidTopologyRejectionsTotal.WithLabelValues(persistence).Inc()
+	return framework.NewStatus(framework.Unschedulable, "invalid node topology data", persistence)
+}
+
+// valid records an observation of valid NRT data for the node, resetting its count of invalid observations.
+func (it *invalidTopologies) valid(nodeName string) {
+	if it == nil {
+		return
+	}
+	if _, ok := it.nodes.Load(nodeName); ok {
+		it.nodes.Delete(nodeName)
+	}
+}
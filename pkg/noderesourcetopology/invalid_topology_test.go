@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// resyncingNRTCache serves the NRT object only when it is not waiting for a resync.
+type resyncingNRTCache struct {
+	staticNRTCache
+	resyncing bool
+}
+
+func (c *resyncingNRTCache) GetCachedNRTCopy(ctx context.Context, nodeName string, pod *v1.Pod) (*topologyv1alpha2.NodeResourceTopology, bool) {
+	if c.resyncing {
+		return nil, false
+	}
+	return c.staticNRTCache.GetCachedNRTCopy(ctx, nodeName, pod)
+}
+
+func TestFilterInvalidTopologyPersistence(t *testing.T) {
+	registerFilterMetrics()
+
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	nrtCache := &resyncingNRTCache{staticNRTCache: staticNRTCache{nodeTopology: nrt}}
+	tm := TopologyMatch{
+		nrtCache:          nrtCache,
+		invalidTopologies: newInvalidTopologies(3),
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	filter := func() *framework.Status {
+		return tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+	}
+	rejections := func(persistence string) float64 {
+		value, err := testutil.GetCounterMetricValue(invalidTopologyRejectionsTotal.WithLabelValues(persistence))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	transientBefore, persistentBefore := rejections(invalidTopologyTransient), rejections(invalidTopologyPersistent)
+
+	transient := framework.NewStatus(framework.Unschedulable, "invalid node topology data", invalidTopologyTransient)
+	persistent := framework.NewStatus(framework.Unschedulable, "invalid node topology data", invalidTopologyPersistent)
+
+	// a blip shorter than the threshold, ended by valid data
+	nrtCache.resyncing = true
+	for i := 0; i < 2; i++ {
+		if status := filter(); !status.Equal(transient) {
+			t.Fatalf("observation %d: unexpected status: %v", i, status)
+		}
+	}
+	nrtCache.resyncing = false
+	if status := filter(); status != nil {
+		t.Fatalf("unexpected status with valid data: %v", status)
+	}
+
+	// the count starts again, and turns persistent at the threshold
+	nrtCache.resyncing = true
+	for i := 0; i < 2; i++ {
+		if status := filter(); !status.Equal(transient) {
+			t.Fatalf("observation %d: unexpected status before the threshold: %v", i, status)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if status := filter(); !status.Equal(persistent) {
+			t.Fatalf("observation %d: unexpected status once persistent: %v", i, status)
+		}
+	}
+
+	if got := rejections(invalidTopologyTransient) - transientBefore; got != 4 {
+		t.Errorf("transient rejections: got=%v expected=4", got)
+	}
+	if got := rejections(invalidTopologyPersistent) - persistentBefore; got != 2 {
+		t.Errorf("persistent rejections: got=%v expected=2", got)
+	}
+}
+
+func TestInvalidTopologiesForgetDeletedNodes(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	clientset := fake.NewSimpleClientset(node)
+	it := newInvalidTopologies(3)
+	it.invalid(node.Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	it.forgetDeletedNodes(informerFactory.Core().V1().Nodes().Informer())
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	if err := clientset.CoreV1().Nodes().Delete(ctx, node.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, wait.ForeverTestTimeout, true, func(context.Context) (bool, error) {
+		_, ok := it.nodes.Load(node.Name)
+		return !ok, nil
+	})
+	if err != nil {
+		t.Errorf("invalid observations of the deleted node not forgotten")
+	}
+}
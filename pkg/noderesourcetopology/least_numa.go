@@ -18,6 +18,7 @@ package noderesourcetopology
 
 import (
 	"fmt"
+	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -36,7 +37,7 @@ const (
 	maxDistanceValue = 255
 )
 
-func leastNUMAContainerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+func leastNUMAContainerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, prefs NUMAPreferences, maxCandidates int64) (int64, *framework.Status) {
 	nodes := createNUMANodeList(zones)
 	qos := v1qos.GetPodQOS(pod)
 
@@ -50,7 +51,7 @@ func leastNUMAContainerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList)
 			continue
 		}
 		identifier := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container.Name)
-		numaNodes, isMinAvgDistance := numaNodesRequired(identifier, qos, nodes, container.Resources.Requests)
+		numaNodes, isMinAvgDistance := numaNodesRequired(identifier, qos, nodes, container.Resources.Requests, maxCandidates)
 		// container's resources can't fit onto node, return MinNodeScore for whole pod
 		if numaNodes == nil {
 			// score plugin should be running after resource filter plugin so we should always find sufficient amount of NUMA nodes
@@ -75,10 +76,10 @@ func leastNUMAContainerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList)
 		return framework.MaxNodeScore, nil
 	}
 
-	return normalizeScore(maxNUMANodesCount, allContainersMinAvgDistance), nil
+	return normalizeScore(prefs.effectiveNUMANodesCount(maxNUMANodesCount), allContainersMinAvgDistance), nil
 }
 
-func leastNUMAPodScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+func leastNUMAPodScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, prefs NUMAPreferences, maxCandidates int64) (int64, *framework.Status) {
 	nodes := createNUMANodeList(zones)
 	qos := v1qos.GetPodQOS(pod)
 
@@ -90,7 +91,7 @@ func leastNUMAPodScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64
 		return framework.MaxNodeScore, nil
 	}
 
-	numaNodes, isMinAvgDistance := numaNodesRequired(identifier, qos, nodes, resources)
+	numaNodes, isMinAvgDistance := numaNodesRequired(identifier, qos, nodes, resources, maxCandidates)
 	// pod's resources can't fit onto node, return MinNodeScore
 	if numaNodes == nil {
 		// score plugin should be running after resource filter plugin so we should always find sufficient amount of NUMA nodes
@@ -98,7 +99,7 @@ func leastNUMAPodScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64
 		return framework.MinNodeScore, nil
 	}
 
-	return normalizeScore(numaNodes.Count(), isMinAvgDistance), nil
+	return normalizeScore(prefs.effectiveNUMANodesCount(numaNodes.Count()), isMinAvgDistance), nil
 }
 
 func normalizeScore(numaNodesCount int, isMinAvgDistance bool) int64 {
@@ -169,9 +170,20 @@ func combineResources(numaNodes NUMANodeList, combination []int) v1.ResourceList
 // numaNodesRequired returns bitmask with minimal NUMA nodes required to run given resources
 // or nil when resources can't be fitted onto the worker node
 // second value returned is a boolean indicating if bitmask is optimal from distance perspective
-func numaNodesRequired(identifier string, qos v1.PodQOSClass, numaNodes NUMANodeList, resources v1.ResourceList) (bitmask.BitMask, bool) {
+// if maxCandidates > 0, at most maxCandidates combinations are evaluated, the smaller and closer ones first
+func numaNodesRequired(identifier string, qos v1.PodQOSClass, numaNodes NUMANodeList, resources v1.ResourceList, maxCandidates int64) (bitmask.BitMask, bool) {
+	var evaluated int64
 	for bitmaskLen := 1; bitmaskLen <= len(numaNodes); bitmaskLen++ {
 		numaNodesCombination := combin.Combinations(len(numaNodes), bitmaskLen)
+		if maxCandidates > 0 {
+			remaining := maxCandidates - evaluated
+			if remaining <= 0 {
+				klog.V(4).InfoS("candidate NUMA node sets truncated", "identifier", identifier, "evaluated", evaluated, "numaNodesCount", bitmaskLen)
+				return nil, false
+			}
+			numaNodesCombination = closestCombinations(numaNodes, numaNodesCombination, remaining)
+			evaluated += int64(len(numaNodesCombination))
+		}
 		suitableCombination, isMinDistance := findSuitableCombination(identifier, qos, numaNodes, resources, numaNodesCombination)
 		// we have found suitable combination for given bitmaskLen
 		if suitableCombination != nil {
@@ -186,6 +198,30 @@ func numaNodesRequired(identifier string, qos v1.PodQOSClass, numaNodes NUMANode
 	return nil, false
 }
 
+// closestCombinations returns at most maxCount combinations from numaNodesCombination, the ones with the lowest
+// average distance between their nodes, keeping the generation order among the ones at the same distance
+func closestCombinations(numaNodes NUMANodeList, numaNodesCombination [][]int, maxCount int64) [][]int {
+	if int64(len(numaNodesCombination)) <= maxCount {
+		return numaNodesCombination
+	}
+	distances := make([]float32, len(numaNodesCombination))
+	for idx, combination := range numaNodesCombination {
+		distances[idx] = nodesAvgDistance(numaNodes, combination...)
+	}
+	order := make([]int, len(numaNodesCombination))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return distances[order[i]] < distances[order[j]]
+	})
+	closest := make([][]int, 0, maxCount)
+	for _, idx := range order[:maxCount] {
+		closest = append(closest, numaNodesCombination[idx])
+	}
+	return closest
+}
+
 // findSuitableCombination returns combination from numaNodesCombination that can fit resources, otherwise return nil
 // second value returned is a boolean indicating if returned combination is optimal from distance perspective
 // this function will always return combination that provides minimal average distance between nodes in combination
@@ -226,7 +262,7 @@ func checkResourcesFit(identifier string, qos v1.PodQOSClass, resources v1.Resou
 			klog.V(4).InfoS("ignoring zero-qty resource request", "identifier", identifier, "resource", resource)
 			continue
 		}
-		if combinationQuantity := combinationResources[resource]; !isResourceSetSuitable(qos, resource, quantity, combinationQuantity) {
+		if combinationQuantity := combinationResources[resource]; !isResourceSetSuitable(qosResourcePolicy{}, qos, resource, quantity, combinationQuantity, nil) {
 			return false
 		}
 	}
@@ -683,7 +683,7 @@ func TestNUMANodesRequired(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
-			bm, isMinDistance := numaNodesRequired("test", v1.PodQOSGuaranteed, tc.numaNodes, tc.podResources)
+			bm, isMinDistance := numaNodesRequired("test", v1.PodQOSGuaranteed, tc.numaNodes, tc.podResources, 0)
 
 			if bm != nil && !bm.IsEqual(tc.expectedBitmask) {
 				t.Errorf("wrong bitmask expected: %d got: %d", tc.expectedBitmask, bm)
@@ -908,3 +908,99 @@ func TestMinDistance(t *testing.T) {
 		})
 	}
 }
+
+// makeSocketNUMANodes returns count NUMA nodes with the given cpus each, grouped by two per socket:
+// the distance is 12 within a socket and 20 across sockets.
+func makeSocketNUMANodes(cpus []int64) NUMANodeList {
+	numaNodes := make(NUMANodeList, 0, len(cpus))
+	for numaID, numaCPUs := range cpus {
+		costs := make(map[int]int, len(cpus))
+		for otherID := range cpus {
+			switch {
+			case otherID == numaID:
+				costs[otherID] = 10
+			case otherID/2 == numaID/2:
+				costs[otherID] = 12
+			default:
+				costs[otherID] = 20
+			}
+		}
+		numaNodes = append(numaNodes, NUMANode{
+			NUMAID: numaID,
+			Resources: v1.ResourceList{
+				v1.ResourceCPU: *resource.NewQuantity(numaCPUs, resource.DecimalSI),
+			},
+			Costs: costs,
+		})
+	}
+	return numaNodes
+}
+
+func TestNUMANodesRequiredMaxCandidates(t *testing.T) {
+	// the NUMA nodes of the first socket can't host the pod together, the ones of the second socket can
+	numaNodes := makeSocketNUMANodes([]int64{2, 2, 4, 4})
+	podResources := v1.ResourceList{
+		v1.ResourceCPU: *resource.NewQuantity(6, resource.DecimalSI),
+	}
+
+	testCases := []struct {
+		description         string
+		maxCandidates       int64
+		expectedBitmask     bitmask.BitMask
+		expectedMinDistance bool
+	}{
+		{
+			description:         "all the sets evaluated",
+			expectedBitmask:     NewTestBitmask(2, 3),
+			expectedMinDistance: true,
+		},
+		{
+			description:   "only the single NUMA nodes evaluated",
+			maxCandidates: 4,
+		},
+		{
+			description:   "only the closest pair evaluated",
+			maxCandidates: 5,
+		},
+		{
+			description:         "the two closest pairs evaluated",
+			maxCandidates:       6,
+			expectedBitmask:     NewTestBitmask(2, 3),
+			expectedMinDistance: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			bm, isMinDistance := numaNodesRequired("test", v1.PodQOSGuaranteed, numaNodes, podResources, tc.maxCandidates)
+			if tc.expectedBitmask == nil {
+				if bm != nil {
+					t.Errorf("expected no bitmask, got: %v", bm)
+				}
+				return
+			}
+			if bm == nil || !bm.IsEqual(tc.expectedBitmask) {
+				t.Errorf("wrong bitmask expected: %v got: %v", tc.expectedBitmask, bm)
+			}
+			if isMinDistance != tc.expectedMinDistance {
+				t.Errorf("wrong isMinDistance expected: %t got: %t", tc.expectedMinDistance, isMinDistance)
+			}
+		})
+	}
+}
+
+func BenchmarkNUMANodesRequired(b *testing.B) {
+	// the pod needs at least four of the eight NUMA nodes
+	numaNodes := makeSocketNUMANodes([]int64{4, 4, 4, 4, 4, 4, 4, 4})
+	podResources := v1.ResourceList{
+		v1.ResourceCPU: *resource.NewQuantity(16, resource.DecimalSI),
+	}
+
+	for _, maxCandidates := range []int64{0, 64, 16} {
+		b.Run(fmt.Sprintf("max candidates %d", maxCandidates), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				numaNodesRequired("bench", v1.PodQOSGuaranteed, numaNodes, podResources, maxCandidates)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// requiresMemorySpread returns true if the memory of the pod must be spread across more NUMA nodes.
+func (np NUMAPreferences) requiresMemorySpread() bool {
+	return np.MinMemoryNUMANodes > 1
+}
+
+// memorySpreadNUMANodes returns how many NUMA nodes, among the ones not excluded, can host an even share of the pod memory
+// spread across the minimum required NUMA nodes.
+func memorySpreadNUMANodes(pod *v1.Pod, zones topologyv1alpha2.ZoneList, prefs NUMAPreferences) int {
+	excluded := untoleratedNUMANodes(pod, zones)
+	memory := util.GetPodEffectiveRequest(pod)[v1.ResourceMemory]
+	share := resource.NewQuantity((memory.Value()+int64(prefs.MinMemoryNUMANodes)-1)/int64(prefs.MinMemoryNUMANodes), resource.BinarySI)
+
+	count := 0
+	for _, numaNode := range createNUMANodeList(zones) {
+		if _, ok := excluded[numaNode.NUMAID]; ok {
+			continue
+		}
+		available, ok := numaNode.Resources[v1.ResourceMemory]
+		if !ok || available.Sign() <= 0 || available.Cmp(*share) < 0 {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// filterMemorySpread admits the pod if its memory can be evenly spread across the minimum required NUMA nodes.
+// The single-numa-node policy never spreads the memory, so the nodes using it can't host the pod.
+func filterMemorySpread(pod *v1.Pod, conf TopologyManagerConfig, zones topologyv1alpha2.ZoneList, prefs NUMAPreferences) *framework.Status {
+	if conf.Policy == kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, "cannot spread memory with single-numa-node policy")
+	}
+	if count := memorySpreadNUMANodes(pod, zones, prefs); count < prefs.MinMemoryNUMANodes {
+		klog.V(2).InfoS("cannot spread memory", "pod", klog.KObj(pod), "numaNodes", count, "required", prefs.MinMemoryNUMANodes)
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("cannot spread memory across %d NUMA nodes", prefs.MinMemoryNUMANodes))
+	}
+	return nil
+}
+
+// memorySpreadScore favors the nodes on which the pod memory can be spread across more NUMA nodes.
+func memorySpreadScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, prefs NUMAPreferences) int64 {
+	numaNodes := len(createNUMANodeList(zones))
+	if numaNodes == 0 {
+		return 0
+	}
+	count := memorySpreadNUMANodes(pod, zones, prefs)
+	return int64(count) * framework.MaxNodeScore / int64(numaNodes)
+}
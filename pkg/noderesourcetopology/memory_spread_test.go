@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makeMemorySpreadNRT(name, policy string, memoryPerNUMA ...string) *topologyv1alpha2.NodeResourceTopology {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Attributes: topologyv1alpha2.AttributeList{
+			{Name: AttributePolicy, Value: policy},
+			{Name: AttributeScope, Value: kubeletconfig.PodTopologyManagerScope},
+		},
+	}
+	for i, available := range memoryPerNUMA {
+		nrt.Zones = append(nrt.Zones, topologyv1alpha2.Zone{
+			Name: fmt.Sprintf("node-%d", i),
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "8", "8"),
+				MakeTopologyResInfo(memory, "16Gi", available),
+			},
+		})
+	}
+	return nrt
+}
+
+func TestMemorySpread(t *testing.T) {
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeMemorySpreadNRT("two-numa", kubeletconfig.RestrictedTopologyManagerPolicy, "8Gi", "8Gi"),
+		makeMemorySpreadNRT("unbalanced", kubeletconfig.RestrictedTopologyManagerPolicy, "16Gi", "4Gi"),
+		makeMemorySpreadNRT("single-numa", kubeletconfig.SingleNumaNodeTopologyManagerPolicy, "8Gi", "8Gi"),
+		makeMemorySpreadNRT("four-numa", kubeletconfig.BestEffortTopologyManagerPolicy, "8Gi", "8Gi", "8Gi", "8Gi"),
+		makeMemorySpreadNRT("four-numa-busy", kubeletconfig.BestEffortTopologyManagerPolicy, "8Gi", "8Gi", "2Gi", "2Gi"),
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 6Gi per NUMA node when spread across 2 NUMA nodes
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("12Gi"),
+	})
+	pod.Annotations = map[string]string{AnnotationMinMemoryNUMANodes: "2"}
+
+	tm := &TopologyMatch{
+		nrtCache:          nrtcache.NewPassthrough(fakeClient),
+		scoreStrategyFunc: leastAllocatedScoreStrategy,
+		scoreStrategyType: apiconfig.LeastAllocated,
+	}
+
+	filterTests := []struct {
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		wantStatus *framework.Status
+	}{
+		{
+			nrt:        nrts[0],
+			wantStatus: nil,
+		},
+		{
+			nrt:        nrts[1],
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot spread memory across 2 NUMA nodes"),
+		},
+		{
+			nrt:        nrts[2],
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "cannot spread memory with single-numa-node policy"),
+		},
+	}
+	for _, tt := range filterTests {
+		t.Run("filter "+tt.nrt.Name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+
+	scoreTests := []struct {
+		nodeName string
+		expected int64
+	}{
+		{nodeName: "four-numa", expected: 100},
+		{nodeName: "four-numa-busy", expected: 50},
+	}
+	for _, tt := range scoreTests {
+		t.Run("score "+tt.nodeName, func(t *testing.T) {
+			score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, tt.nodeName)
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected status: %v", status)
+			}
+			if score != tt.expected {
+				t.Errorf("score=%d expected=%d", score, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// pseudoNodeResourceTopology returns the NRT data assumed for a node without NodeResourceTopology object: a single
+// NUMA node holding the allocatable resources of the node, minus the ones its pods request, with the given topology
+// manager configuration. The pods and ephemeral-storage resources have no NUMA affinity and are left out.
+func pseudoNodeResourceTopology(nodeInfo *framework.NodeInfo, conf *apiconfig.TopologyManagerOverlay) *topologyv1alpha2.NodeResourceTopology {
+	allocatable := util.ResourceList(nodeInfo.Allocatable)
+	requested := util.ResourceList(nodeInfo.Requested)
+
+	var resources topologyv1alpha2.ResourceInfoList
+	for _, resName := range sortedResourceNames(allocatable) {
+		if resName == v1.ResourcePods || resName == v1.ResourceEphemeralStorage {
+			continue
+		}
+		quantity := allocatable[resName]
+		available := quantity.DeepCopy()
+		if used, ok := requested[resName]; ok {
+			available.Sub(used)
+		}
+		if available.Sign() < 0 {
+			available.Set(0)
+		}
+		resources = append(resources, topologyv1alpha2.ResourceInfo{
+			Name:        string(resName),
+			Capacity:    quantity,
+			Allocatable: quantity,
+			Available:   available,
+		})
+	}
+
+	return &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeInfo.Node().Name},
+		Attributes: topologyv1alpha2.AttributeList{
+			{Name: AttributePolicy, Value: conf.Policy},
+			{Name: AttributeScope, Value: conf.Scope},
+		},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name:      "node-0",
+				Type:      "Node",
+				Resources: resources,
+			},
+		},
+	}
+}
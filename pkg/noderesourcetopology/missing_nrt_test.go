@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterDefaultPolicyWhenMissing(t *testing.T) {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+
+	// no NRT object for this node, 2 of its 8 cpus are left
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-nrt"},
+		Status: v1.NodeStatus{
+			Capacity: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("8"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("8"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+				v1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+	}
+	runningPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+
+	singleNUMANode := &apiconfig.TopologyManagerOverlay{
+		Policy: "single-numa-node",
+		Scope:  "pod",
+	}
+	tests := []struct {
+		name          string
+		defaultPolicy *apiconfig.TopologyManagerOverlay
+		cpus          string
+		wantCode      framework.Code
+	}{
+		{
+			name:     "no default policy, node not filtered",
+			cpus:     "4",
+			wantCode: framework.Success,
+		},
+		{
+			name:          "default policy, pod fits the pseudo NUMA node",
+			defaultPolicy: singleNUMANode,
+			cpus:          "2",
+			wantCode:      framework.Success,
+		},
+		{
+			name:          "default policy, pod exceeds the pseudo NUMA node",
+			defaultPolicy: singleNUMANode,
+			cpus:          "4",
+			wantCode:      framework.Unschedulable,
+		},
+		{
+			name: "default policy not aligning",
+			defaultPolicy: &apiconfig.TopologyManagerOverlay{
+				Policy: "none",
+				Scope:  "container",
+			},
+			cpus:     "4",
+			wantCode: framework.Success,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:                 nrtcache.NewPassthrough(fakeClient),
+				defaultPolicyWhenMissing: tt.defaultPolicy,
+			}
+			nodeInfo := framework.NewNodeInfo(runningPod)
+			nodeInfo.SetNode(node)
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(tt.cpus),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			})
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if gotStatus.Code() != tt.wantCode {
+				t.Errorf("status does not match: %v, want code: %v", gotStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestPseudoNodeResourceTopology(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-nrt"},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:              resource.MustParse("8"),
+				v1.ResourceMemory:           resource.MustParse("16Gi"),
+				v1.ResourcePods:             resource.MustParse("110"),
+				v1.ResourceEphemeralStorage: resource.MustParse("100Gi"),
+			},
+		},
+	}
+	runningPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+	nodeInfo := framework.NewNodeInfo(runningPod)
+	nodeInfo.SetNode(node)
+
+	nrt := pseudoNodeResourceTopology(nodeInfo, &apiconfig.TopologyManagerOverlay{Policy: "single-numa-node", Scope: "container"})
+	conf := topologyManagerConfigFromNodeResourceTopology(nrt)
+	if conf.Policy != "single-numa-node" || conf.Scope != "container" {
+		t.Errorf("unexpected topology manager configuration: %+v", conf)
+	}
+	if len(nrt.Zones) != 1 {
+		t.Fatalf("expected a single zone, got %d", len(nrt.Zones))
+	}
+	expected := map[string]string{
+		string(v1.ResourceCPU):    "2",
+		string(v1.ResourceMemory): "12Gi",
+	}
+	if len(nrt.Zones[0].Resources) != len(expected) {
+		t.Errorf("unexpected resources: %v", nrt.Zones[0].Resources)
+	}
+	for _, resInfo := range nrt.Zones[0].Resources {
+		want := resource.MustParse(expected[resInfo.Name])
+		if resInfo.Available.Cmp(want) != 0 {
+			t.Errorf("resource %s: available=%s expected=%s", resInfo.Name, resInfo.Available.String(), want.String())
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// nodeHeadroomComponent scores the overall headroom of the node, regardless of its NUMA nodes.
+func (tm *TopologyMatch) nodeHeadroomComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	nodeInfo, err := tm.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, false, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	return nodeHeadroomScore(util.GetPodEffectiveRequest(pod), nodeInfo, tm.resourceToWeightMap), true, nil
+}
+
+// nodeHeadroomScore scores the allocatable resources of the node still available to the pod,
+// the more resources would be left once the pod is placed, the higher the score is.
+func nodeHeadroomScore(requested v1.ResourceList, nodeInfo *framework.NodeInfo, resourceToWeightMap resourceToWeightMap) int64 {
+	available := util.ResourceList(nodeInfo.Allocatable)
+	used := util.ResourceList(nodeInfo.Requested)
+	for resName, quantity := range available {
+		if usedQuantity, ok := used[resName]; ok {
+			quantity.Sub(usedQuantity)
+			available[resName] = quantity
+		}
+	}
+	return leastAllocatedScoreStrategy(requested, available, resourceToWeightMap)
+}
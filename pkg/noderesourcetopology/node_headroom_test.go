@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestNodeHeadroomScore(t *testing.T) {
+	var nodes []*v1.Node
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nodeName := range []string{"idle", "busy"} {
+		// both nodes align the pod on identical NUMA nodes
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: nodeName},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+			},
+		}
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+		nodes = append(nodes, &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("16"),
+					v1.ResourceMemory: resource.MustParse("32Gi"),
+				},
+			},
+		})
+	}
+	runningPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("10"),
+		v1.ResourceMemory: resource.MustParse("16Gi"),
+	})
+	runningPod.Spec.NodeName = "busy"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fh, err := st.NewFramework(
+		ctx,
+		[]st.RegisterPluginFunc{
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		},
+		"default-scheduler",
+		frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister([]*v1.Pod{runningPod}, nodes)),
+	)
+	if err != nil {
+		t.Fatalf("fail to create framework: %s", err)
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+
+	burstablePod := pod.DeepCopy()
+	burstablePod.Spec.Containers[0].Resources.Limits = nil
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		weight   int64
+		expected nodeToScoreMap
+	}{
+		{
+			name: "disabled",
+			// NUMA node: cpu (8 - 2) / 8 = 75, memory (16 - 2) / 16 = 87
+			expected: nodeToScoreMap{"idle": 81, "busy": 81},
+		},
+		{
+			name:   "blended",
+			weight: 50,
+			// idle headroom: cpu (16 - 2) / 16 = 87, memory (32 - 2) / 32 = 93 => 90
+			// busy headroom: cpu (6 - 2) / 6 = 66, memory (16 - 2) / 16 = 87 => 76
+			expected: nodeToScoreMap{"idle": 85, "busy": 78},
+		},
+		{
+			name:     "headroom only",
+			weight:   100,
+			expected: nodeToScoreMap{"idle": 90, "busy": 76},
+		},
+		{
+			name:     "burstable pod, disabled",
+			pod:      burstablePod,
+			expected: nodeToScoreMap{"idle": 100, "busy": 100},
+		},
+		{
+			name:   "burstable pod, blended",
+			pod:    burstablePod,
+			weight: 50,
+			// the maximum score, as the pod is not aligned, blended with the headroom
+			expected: nodeToScoreMap{"idle": 95, "busy": 88},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				handle:             fh,
+				nrtCache:           nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc:  leastAllocatedScoreStrategy,
+				scoreStrategyType:  apiconfig.LeastAllocated,
+				nodeHeadroomWeight: tt.weight,
+			}
+			pod := pod
+			if tt.pod != nil {
+				pod = tt.pod
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
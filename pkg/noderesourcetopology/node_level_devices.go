@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// nodeLevelDevices returns, for the device resources the node has more instances of than its NUMA zones report, how many
+// of the instances reported only at node level, with unknown NUMA affinity, are free. The instances in use are attributed
+// to the NUMA zones first, as far as their availability tells, the rest to the instances reported only at node level.
+func nodeLevelDevices(zones topologyv1alpha2.ZoneList, nodeInfo *framework.NodeInfo) v1.ResourceList {
+	numaAllocatable := numaZonesAllocatable(zones)
+	numaAvailable := make(v1.ResourceList)
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		for _, resInfo := range zone.Resources {
+			resName := v1.ResourceName(resInfo.Name)
+			total := numaAvailable[resName]
+			total.Add(resInfo.Available)
+			numaAvailable[resName] = total
+		}
+	}
+	// Node() != nil already verified in Filter(), which is the only public entry point
+	allocatable := nodeInfo.Node().Status.Allocatable
+	requested := util.ResourceList(nodeInfo.Requested)
+
+	var devices v1.ResourceList
+	for _, resName := range sortedResourceNames(numaAllocatable) {
+		if v1helper.IsNativeResource(resName) {
+			continue
+		}
+		nodeAllocatable, ok := allocatable[resName]
+		if !ok {
+			continue
+		}
+		free := nodeAllocatable.DeepCopy()
+		free.Sub(numaAllocatable[resName])
+		if free.Sign() <= 0 {
+			continue
+		}
+		// the requests the NUMA zones don't account for use the instances reported only at node level
+		used := requested[resName].DeepCopy()
+		numaUsed := numaAllocatable[resName].DeepCopy()
+		numaUsed.Sub(numaAvailable[resName])
+		used.Sub(numaUsed)
+		if used.Sign() > 0 {
+			free.Sub(used)
+		}
+		if free.Sign() <= 0 {
+			continue
+		}
+		if devices == nil {
+			devices = make(v1.ResourceList)
+		}
+		devices[resName] = free
+	}
+	return devices
+}
+
+// fitsWithNodeLevelDevices returns true if the quantity of the resource fits on a NUMA node with numaQuantity available
+// once the free instances reported only at node level are counted as well.
+func (info *filterInfo) fitsWithNodeLevelDevices(resName v1.ResourceName, quantity, numaQuantity resource.Quantity) bool {
+	nodeLevel, ok := info.nodeLevelDevices[resName]
+	if !ok {
+		return false
+	}
+	available := numaQuantity.DeepCopy()
+	available.Add(nodeLevel)
+	return available.Cmp(info.rounding.roundUp(resName, quantity)) >= 0
+}
+
+// takeNodeLevelDevices consumes the instances reported only at node level needed, on top of the ones available on the
+// NUMA node, by the resources, returning the resources left to subtract from the NUMA node.
+func (info *filterInfo) takeNodeLevelDevices(numaID int, resources v1.ResourceList) v1.ResourceList {
+	if len(info.nodeLevelDevices) == 0 {
+		return resources
+	}
+	var numaResources v1.ResourceList
+	for _, numaNode := range info.numaNodes {
+		if numaNode.NUMAID == numaID {
+			numaResources = numaNode.Resources
+		}
+	}
+	var remaining v1.ResourceList
+	for resName, quantity := range resources {
+		nodeLevel, ok := info.nodeLevelDevices[resName]
+		if !ok {
+			continue
+		}
+		needed := info.rounding.roundUp(resName, quantity)
+		excess := needed.DeepCopy()
+		excess.Sub(numaResources[resName])
+		if excess.Sign() <= 0 {
+			continue
+		}
+		if excess.Cmp(nodeLevel) > 0 {
+			excess = nodeLevel.DeepCopy()
+		}
+		nodeLevel.Sub(excess)
+		info.nodeLevelDevices[resName] = nodeLevel
+		klog.V(6).InfoS("instances reported only at node level taken", "node", info.nodeName, "NUMA", numaID, "resource", resName, "quantity", excess.String())
+
+		if remaining == nil {
+			remaining = resources.DeepCopy()
+		}
+		needed.Sub(excess)
+		remaining[resName] = needed
+	}
+	if remaining == nil {
+		return resources
+	}
+	return remaining
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterBestEffortNodeLevelDevices(t *testing.T) {
+	// each NUMA node reports one NIC, the node reports two more with unknown NUMA affinity
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				MakeTopologyResInfo(nicResourceName, "1", "1"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				MakeTopologyResInfo(nicResourceName, "1", "1"),
+			},
+		},
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "mixed-pod-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            zones,
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "mixed-container-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones:            zones,
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nics := func(count string) map[string]string {
+		return map[string]string{nicResourceName: count}
+	}
+	guaranteedNICs := map[string]string{cpu: "1", memory: "1Gi", nicResourceName: "2"}
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		disabled   bool
+		cntReq     []map[string]string
+		runningReq map[string]string
+		expected   bool
+	}{
+		{
+			name:     "node level instances not counted",
+			nrt:      nrts[0],
+			disabled: true,
+			cntReq:   []map[string]string{nics("2")},
+		},
+		{
+			name:     "node level instances counted",
+			nrt:      nrts[0],
+			cntReq:   []map[string]string{nics("2")},
+			expected: true,
+		},
+		{
+			name:   "node level instances insufficient",
+			nrt:    nrts[0],
+			cntReq: []map[string]string{nics("4")},
+		},
+		{
+			name:       "node level instances in use",
+			nrt:        nrts[0],
+			cntReq:     []map[string]string{nics("2")},
+			runningReq: nics("2"),
+		},
+		{
+			name:   "guaranteed pod",
+			nrt:    nrts[0],
+			cntReq: []map[string]string{guaranteedNICs},
+		},
+		{
+			name:     "node level instances shared by the containers",
+			nrt:      nrts[1],
+			cntReq:   []map[string]string{nics("2"), nics("2")},
+			expected: true,
+		},
+		{
+			name:   "node level instances consumed by the containers",
+			nrt:    nrts[1],
+			cntReq: []map[string]string{nics("2"), nics("2"), nics("2")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache:              nrtcache.NewPassthrough(fakeClient),
+				bestEffortNodeDevices: !tt.disabled,
+			}
+			node := makeNodeFromNodeResourceTopology(tt.nrt)
+			node.Status.Allocatable[nicResourceName] = resource.MustParse("4")
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			if tt.runningReq != nil {
+				running := makePod("running", withMultiContainers(parseContainerRes([]map[string]string{tt.runningReq})))
+				nodeInfo.AddPod(running)
+			}
+
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(tt.cntReq)))
+			status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if status.IsSuccess() != tt.expected {
+				t.Errorf("unexpected filter status: %v, expected success=%v", status, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNodeLevelDevices(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(nicResourceName, "2", "1"),
+			},
+		},
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: v1.NodeStatus{
+			Allocatable: v1.ResourceList{
+				v1.ResourceCPU:  resource.MustParse("8"),
+				nicResourceName: resource.MustParse("5"),
+			},
+		},
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	// one NIC in use on the NUMA node, the other two requested are reported only at node level
+	nodeInfo.AddPod(makePod("running", withMultiContainers(parseContainerRes([]map[string]string{{nicResourceName: "3"}}))))
+
+	devices := nodeLevelDevices(zones, nodeInfo)
+	if len(devices) != 1 {
+		t.Fatalf("unexpected node level devices: %v", devices)
+	}
+	if got := devices[nicResourceName]; got.Cmp(resource.MustParse("1")) != 0 {
+		t.Errorf("free node level NICs: got=%s expected=1", got.String())
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// overReservedState marks in the CycleState a node already declared maybe over-reserved for the pod.
+type overReservedState struct{}
+
+func (s *overReservedState) Clone() framework.StateData {
+	return s
+}
+
+func overReservedStateKey(nodeName string) framework.StateKey {
+	return framework.StateKey(Name + "/overReserved/" + nodeName)
+}
+
+// markNodeMaybeOverReserved declares to the cache the node maybe over-reserved at most once per pod per scheduling
+// cycle, because the filter can run against the same node many times in a cycle, e.g. during the preemption dry runs,
+// and each call contends for the cache lock.
+func (tm *TopologyMatch) markNodeMaybeOverReserved(cycleState *framework.CycleState, pod *v1.Pod, nodeName string) {
+	key := overReservedStateKey(nodeName)
+	if _, err := cycleState.Read(key); err == nil {
+		klog.V(6).InfoS("node already marked maybe over-reserved", "pod", klog.KObj(pod), "node", nodeName)
+		return
+	}
+	cycleState.Write(key, &overReservedState{})
+	tm.nrtCache.NodeMaybeOverReserved(nodeName, pod)
+	if tm.quarantine != nil {
+		tm.quarantine.overReserved(nodeName)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+type countingCache struct {
+	nrtcache.Passthrough
+	overReserved map[string]int
+}
+
+func (cc *countingCache) NodeMaybeOverReserved(nodeName string, pod *v1.Pod) {
+	cc.overReserved[nodeName]++
+}
+
+func TestFilterMarksNodeMaybeOverReservedOnce(t *testing.T) {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	var nodes []*v1.Node
+	for _, name := range []string{"node1", "node2"} {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+		nodes = append(nodes, makeNodeFromNodeResourceTopology(nrt))
+	}
+
+	cache := &countingCache{
+		Passthrough:  nrtcache.NewPassthrough(fakeClient).(nrtcache.Passthrough),
+		overReserved: make(map[string]int),
+	}
+	tm := TopologyMatch{
+		nrtCache: cache,
+	}
+	// fits on the nodes, but on no single NUMA node
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("6"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+
+	filterAll := func(state *framework.CycleState) {
+		for _, node := range nodes {
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status.IsSuccess() {
+				t.Fatalf("node %q unexpectedly fits the pod", node.Name)
+			}
+		}
+	}
+
+	state := framework.NewCycleState()
+	filterAll(state)
+	// e.g. the preemption dry runs
+	filterAll(state)
+	filterAll(state.Clone())
+	if expected := map[string]int{"node1": 1, "node2": 1}; !reflect.DeepEqual(cache.overReserved, expected) {
+		t.Errorf("marks within a cycle=%v expected=%v", cache.overReserved, expected)
+	}
+
+	filterAll(framework.NewCycleState())
+	if expected := map[string]int{"node1": 2, "node2": 2}; !reflect.DeepEqual(cache.overReserved, expected) {
+		t.Errorf("marks in a new cycle=%v expected=%v", cache.overReserved, expected)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sort"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// MergeNUMANodeLists combines the NUMA nodes reported by multiple producers for the same node, e.g. one agent
+// reporting the CPU and memory topology and another one the device topology, into a single list sorted by NUMA ID.
+// The resources of the same NUMA node reported by distinct producers are added together. A resource reported by
+// more than one producer for the same NUMA node is not summed, because it would be double counted: the value of
+// the first producer is kept, and a warning is logged. Likewise, the costs are taken from the first producer
+// reporting any for the NUMA node. Meant for the integrations running more than one topology producer per node,
+// which are expected to pass the NodeResourceTopology objects of a single node only, in order of precedence.
+func MergeNUMANodeLists(nrts ...*topologyv1alpha2.NodeResourceTopology) NUMANodeList {
+	merged := make(map[int]*NUMANode)
+	// the producer each resource was taken from, for the warnings
+	owners := make(map[int]map[v1.ResourceName]string)
+	for _, nrt := range nrts {
+		if nrt == nil {
+			continue
+		}
+		for _, numaNode := range createNUMANodeList(nrt.Zones) {
+			target, ok := merged[numaNode.NUMAID]
+			if !ok {
+				target = &NUMANode{
+					NUMAID:    numaNode.NUMAID,
+					Resources: v1.ResourceList{},
+					Costs:     map[int]int{},
+				}
+				merged[numaNode.NUMAID] = target
+				owners[numaNode.NUMAID] = make(map[v1.ResourceName]string)
+			}
+			for resName, quantity := range numaNode.Resources {
+				if owner, ok := owners[numaNode.NUMAID][resName]; ok {
+					klog.Warningf("resource %q of NUMA node %d reported by both %q and %q, keeping the value of %q", resName, numaNode.NUMAID, owner, nrt.Name, owner)
+					continue
+				}
+				owners[numaNode.NUMAID][resName] = nrt.Name
+				target.Resources[resName] = quantity.DeepCopy()
+			}
+			if len(target.Costs) == 0 && len(numaNode.Costs) > 0 {
+				target.Costs = numaNode.Costs
+			}
+		}
+	}
+
+	nodes := make(NUMANodeList, 0, len(merged))
+	for _, numaNode := range merged {
+		nodes = append(nodes, *numaNode)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NUMAID < nodes[j].NUMAID
+	})
+	return nodes
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMergeNUMANodeLists(t *testing.T) {
+	cpuSource := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "cpu-source"},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Costs: topologyv1alpha2.CostList{
+					{Name: "node-0", Value: 10},
+					{Name: "node-1", Value: 20},
+				},
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "6"),
+					MakeTopologyResInfo(memory, "16Gi", "12Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+				},
+			},
+		},
+	}
+	deviceSource := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "device-source"},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Costs: topologyv1alpha2.CostList{
+					{Name: "node-0", Value: 11},
+					{Name: "node-1", Value: 21},
+				},
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(nicResourceName, "2", "1"),
+					// overlaps with the cpu source
+					MakeTopologyResInfo(cpu, "4", "4"),
+				},
+			},
+			{
+				Name: "node-2",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(nicResourceName, "2", "2"),
+				},
+			},
+		},
+	}
+
+	expected := NUMANodeList{
+		{
+			NUMAID: 0,
+			Resources: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("12Gi"),
+				nicResourceName:   resource.MustParse("1"),
+			},
+			Costs: map[int]int{0: 10, 1: 20},
+		},
+		{
+			NUMAID: 1,
+			Resources: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("8"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			Costs: map[int]int{},
+		},
+		{
+			NUMAID: 2,
+			Resources: v1.ResourceList{
+				nicResourceName: resource.MustParse("2"),
+			},
+			Costs: map[int]int{},
+		},
+	}
+
+	got := MergeNUMANodeLists(cpuSource, nil, deviceSource)
+	if !equality.Semantic.DeepEqual(got, expected) {
+		t.Errorf("merged=%+v expected=%+v", got, expected)
+	}
+
+	// the NUMA nodes reported by a single producer are left untouched
+	got = MergeNUMANodeLists(cpuSource)
+	if !equality.Semantic.DeepEqual(got, createNUMANodeList(cpuSource.Zones)) {
+		t.Errorf("merged=%+v expected=%+v", got, createNUMANodeList(cpuSource.Zones))
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+const (
+	// AnnotationAssignedNUMANodes is the comma-separated list of the NUMA node IDs a running pod
+	// is allocated on, e.g. "0" or "0,1". Expected to be recorded by the node agent, or by the plugin
+	// when numaOccupancyFromPods is set.
+	AnnotationAssignedNUMANodes = AnnotationKeyPrefix + "assigned-numa-nodes"
+	// AnnotationNUMAAffinity is a label selector, e.g. "app=cache", matching the running pods, in the
+	// same namespace, the pod wants to share a NUMA node with.
+	AnnotationNUMAAffinity = AnnotationKeyPrefix + "numa-affinity"
+
+	neutralNodeScore = framework.MaxNodeScore / 2
+)
+
+// interPodNUMAAffinityScore rewards the nodes on which the pod can fit on a NUMA node already hosting
+// one of the pods it references, and penalizes the nodes on which it can't. Nodes not hosting any of the
+// referenced pods get a neutral score.
+func (tm *TopologyMatch) interPodNUMAAffinityScore(pod *v1.Pod, nodeName string, nodeTopology *topologyv1alpha2.NodeResourceTopology) (int64, *framework.Status) {
+	conf := tm.topologyManagerConfig(nodeTopology)
+	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
+		return 0, nil
+	}
+
+	val, ok := pod.Annotations[AnnotationNUMAAffinity]
+	if !ok {
+		return neutralNodeScore, nil
+	}
+	selector, err := labels.Parse(val)
+	if err != nil {
+		klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationNUMAAffinity, "value", val)
+		return neutralNodeScore, nil
+	}
+
+	nodeInfo, err := tm.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	return scoreByNUMAAffinity(pod, selector, nodeInfo, createNUMANodeList(nodeTopology.Zones)), nil
+}
+
+func scoreByNUMAAffinity(pod *v1.Pod, selector labels.Selector, nodeInfo *framework.NodeInfo, numaNodes NUMANodeList) int64 {
+	targetNUMAIDs := referencedPodsNUMANodes(pod, selector, nodeInfo)
+	if len(targetNUMAIDs) == 0 {
+		klog.V(5).InfoS("no referenced pods found", "pod", klog.KObj(pod), "node", nodeInfo.Node().Name)
+		return neutralNodeScore
+	}
+
+	logID := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	qos := v1qos.GetPodQOS(pod)
+	resources := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	for _, numaNode := range numaNodes {
+		if !targetNUMAIDs[numaNode.NUMAID] {
+			continue
+		}
+		if checkResourcesFit(logID, qos, resources, numaNode.Resources) {
+			klog.V(5).InfoS("pod fits on referenced pod NUMA node", "pod", klog.KObj(pod), "node", nodeInfo.Node().Name, "NUMA", numaNode.NUMAID)
+			return framework.MaxNodeScore
+		}
+	}
+	return framework.MinNodeScore
+}
+
+// referencedPodsNUMANodes returns the NUMA nodes on which the running pods matching the selector are allocated.
+func referencedPodsNUMANodes(pod *v1.Pod, selector labels.Selector, nodeInfo *framework.NodeInfo) map[int]bool {
+	return runningPodsNUMANodes(pod, nodeInfo, func(runningPod *v1.Pod) bool {
+		return selector.Matches(labels.Set(runningPod.Labels))
+	})
+}
+
+// runningPodsNUMANodes returns the NUMA nodes on which the running pods, in the namespace of the pod, accepted by
+// the given function are allocated.
+func runningPodsNUMANodes(pod *v1.Pod, nodeInfo *framework.NodeInfo, accept func(runningPod *v1.Pod) bool) map[int]bool {
+	numaIDs := make(map[int]bool)
+	for id := range runningPodsNUMANodeCounts(pod, nodeInfo, accept) {
+		numaIDs[id] = true
+	}
+	return numaIDs
+}
+
+// runningPodsNUMANodeCounts returns, for each NUMA node, how many of the running pods, in the namespace of the pod,
+// accepted by the given function are allocated on it.
+func runningPodsNUMANodeCounts(pod *v1.Pod, nodeInfo *framework.NodeInfo, accept func(runningPod *v1.Pod) bool) map[int]int {
+	numaCounts := make(map[int]int)
+	for _, podInfo := range nodeInfo.Pods {
+		runningPod := podInfo.Pod
+		if runningPod.UID == pod.UID || runningPod.Namespace != pod.Namespace || !accept(runningPod) {
+			continue
+		}
+		ids, err := parseNUMANodeIDs(runningPod.Annotations[AnnotationAssignedNUMANodes])
+		if err != nil {
+			klog.V(4).InfoS("ignoring malformed annotation", "pod", klog.KObj(runningPod), "annotation", AnnotationAssignedNUMANodes, "err", err)
+			continue
+		}
+		for _, id := range ids {
+			numaCounts[id]++
+		}
+	}
+	return numaCounts
+}
+
+func parseNUMANodeIDs(val string) ([]int, error) {
+	var ids []int
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, err := strconv.Atoi(item)
+		if err != nil || id < 0 || id >= highestNUMAID {
+			return nil, fmt.Errorf("invalid NUMA node ID %q", item)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// numaAffineResources returns the subset of resources reported by at least one NUMA node.
+func numaAffineResources(resources v1.ResourceList, numaNodes NUMANodeList) v1.ResourceList {
+	ret := v1.ResourceList{}
+	for resName, quantity := range resources {
+		for _, numaNode := range numaNodes {
+			if _, ok := numaNode.Resources[resName]; ok {
+				ret[resName] = quantity
+				break
+			}
+		}
+	}
+	return ret
+}
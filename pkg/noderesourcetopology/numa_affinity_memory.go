@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+// numaAffinityMemory remembers when the nodes last reported each resource in their NUMA zones, so a resource
+// flapping between NUMA-affine and node-level across the NRT updates is consistently considered NUMA-affine.
+type numaAffinityMemory struct {
+	window time.Duration
+	now    func() time.Time
+	lock   sync.Mutex
+	nodes  map[string]map[v1.ResourceName]time.Time
+}
+
+func newNUMAAffinityMemory(seconds int64) *numaAffinityMemory {
+	if seconds <= 0 {
+		return nil
+	}
+	return &numaAffinityMemory{
+		window: time.Duration(seconds) * time.Second,
+		now:    time.Now,
+		nodes:  make(map[string]map[v1.ResourceName]time.Time),
+	}
+}
+
+// stabilize records the resources the NUMA nodes report, and adds to the NUMA nodes, with zero availability,
+// the resources reported within the window but missing now, so they can't be considered node-level.
+func (nm *numaAffinityMemory) stabilize(nodeName string, numaNodes NUMANodeList) NUMANodeList {
+	now := nm.now()
+	reported := make(map[v1.ResourceName]bool)
+	for _, numaNode := range numaNodes {
+		for resName := range numaNode.Resources {
+			reported[resName] = true
+		}
+	}
+
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	lastSeen, ok := nm.nodes[nodeName]
+	if !ok {
+		lastSeen = make(map[v1.ResourceName]time.Time)
+		nm.nodes[nodeName] = lastSeen
+	}
+	for resName := range reported {
+		lastSeen[resName] = now
+	}
+	for resName, seen := range lastSeen {
+		if reported[resName] {
+			continue
+		}
+		if now.Sub(seen) > nm.window {
+			klog.V(4).InfoS("forgetting the NUMA affinity of resource", "node", nodeName, "resource", resName, "lastSeen", seen)
+			delete(lastSeen, resName)
+			continue
+		}
+		klog.V(4).InfoS("resource not reported by the NUMA nodes, assuming it is still NUMA-affine", "node", nodeName, "resource", resName, "lastSeen", seen)
+		for _, numaNode := range numaNodes {
+			numaNode.Resources[resName] = *resource.NewQuantity(0, resource.DecimalSI)
+		}
+	}
+	return numaNodes
+}
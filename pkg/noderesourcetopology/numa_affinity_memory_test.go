@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterNUMAAffinityMemory(t *testing.T) {
+	makeNRT := func(numaAffine bool) *topologyv1alpha2.NodeResourceTopology {
+		resources := topologyv1alpha2.ResourceInfoList{
+			MakeTopologyResInfo(cpu, "4", "4"),
+			MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+		}
+		if numaAffine {
+			// all the NICs are taken
+			resources = append(resources, MakeTopologyResInfo(nicResourceName, "2", "0"))
+		}
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name:      "node-0",
+					Type:      "Node",
+					Resources: resources.DeepCopy(),
+				},
+				{
+					Name:      "node-1",
+					Type:      "Node",
+					Resources: resources.DeepCopy(),
+				},
+			},
+		}
+	}
+
+	cannotAlign := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+	// the NRT updates report the NIC NUMA affinity inconsistently
+	steps := []struct {
+		elapsed    time.Duration
+		numaAffine bool
+		// verdicts without and with the memory
+		wantStatus       *framework.Status
+		wantStableStatus *framework.Status
+	}{
+		{
+			numaAffine:       true,
+			wantStatus:       cannotAlign,
+			wantStableStatus: cannotAlign,
+		},
+		{
+			elapsed:          10 * time.Second,
+			numaAffine:       false,
+			wantStatus:       nil,
+			wantStableStatus: cannotAlign,
+		},
+		{
+			elapsed:          10 * time.Second,
+			numaAffine:       true,
+			wantStatus:       cannotAlign,
+			wantStableStatus: cannotAlign,
+		},
+		{
+			elapsed:          50 * time.Second,
+			numaAffine:       false,
+			wantStatus:       nil,
+			wantStableStatus: cannotAlign,
+		},
+		{
+			// the NIC was last reported by the NUMA nodes longer than the window ago
+			elapsed:          20 * time.Second,
+			numaAffine:       false,
+			wantStatus:       nil,
+			wantStableStatus: nil,
+		},
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+		nicResourceName:   resource.MustParse("1"),
+	})
+	node := makeNodeFromNodeResourceTopology(makeNRT(true))
+	// the node-level allocatable doesn't change
+	node.Status.Allocatable[nicResourceName] = resource.MustParse("4")
+
+	for _, stable := range []bool{false, true} {
+		fakeClient, err := tu.NewFakeClient()
+		if err != nil {
+			t.Fatalf("failed to create fake client: %v", err)
+		}
+		tm := TopologyMatch{
+			nrtCache: nrtcache.NewPassthrough(fakeClient),
+		}
+		now := time.Now()
+		if stable {
+			tm.numaAffinityMemory = newNUMAAffinityMemory(60)
+			tm.numaAffinityMemory.now = func() time.Time { return now }
+		}
+
+		for idx, step := range steps {
+			now = now.Add(step.elapsed)
+			nrt := makeNRT(step.numaAffine)
+			if idx > 0 {
+				if err := fakeClient.Delete(context.Background(), nrt.DeepCopy()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := fakeClient.Create(context.Background(), nrt); err != nil {
+				t.Fatal(err)
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			wantStatus := step.wantStatus
+			if stable {
+				wantStatus = step.wantStableStatus
+			}
+			if !reflect.DeepEqual(gotStatus, wantStatus) {
+				t.Errorf("memory=%v step %d: status does not match: %v, want: %v", stable, idx, gotStatus, wantStatus)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func TestScoreByNUMAAffinity(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "1"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+	}
+
+	makeRunningPod := func(name string, podLabels map[string]string, numaNodes string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns1",
+				Name:        name,
+				UID:         types.UID("uid-" + name),
+				Labels:      podLabels,
+				Annotations: map[string]string{AnnotationAssignedNUMANodes: numaNodes},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		runningPods []*v1.Pod
+		expected    int64
+	}{
+		{
+			name:     "no running pods",
+			expected: neutralNodeScore,
+		},
+		{
+			name: "referenced pod not found",
+			runningPods: []*v1.Pod{
+				makeRunningPod("other", map[string]string{"app": "other"}, "0"),
+			},
+			expected: neutralNodeScore,
+		},
+		{
+			name: "referenced pod on a NUMA node with enough resources",
+			runningPods: []*v1.Pod{
+				makeRunningPod("cache", map[string]string{"app": "cache"}, "0"),
+			},
+			expected: framework.MaxNodeScore,
+		},
+		{
+			name: "referenced pod on a NUMA node without enough resources",
+			runningPods: []*v1.Pod{
+				makeRunningPod("cache", map[string]string{"app": "cache"}, "1"),
+			},
+			expected: framework.MinNodeScore,
+		},
+		{
+			name: "referenced pod with malformed assignment",
+			runningPods: []*v1.Pod{
+				makeRunningPod("cache", map[string]string{"app": "cache"}, "zero"),
+			},
+			expected: neutralNodeScore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			})
+			pod.Namespace = "ns1"
+			pod.Annotations = map[string]string{AnnotationNUMAAffinity: "app=cache"}
+
+			nrt := &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Zones:      zones,
+			}
+			nodeInfo := framework.NewNodeInfo(tt.runningPods...)
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+
+			selector, err := labels.Parse(pod.Annotations[AnnotationNUMAAffinity])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := scoreByNUMAAffinity(pod, selector, nodeInfo, createNUMANodeList(zones))
+			if got != tt.expected {
+				t.Errorf("score=%d expected=%d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNUMANodeIDs(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []int
+		wantErr  bool
+	}{
+		{value: "", expected: nil},
+		{value: "1", expected: []int{1}},
+		{value: "0, 1", expected: []int{0, 1}},
+		{value: "-1", wantErr: true},
+		{value: "8", wantErr: true},
+		{value: "a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNUMANodeIDs(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("value=%q unexpected error: %v", tt.value, err)
+			continue
+		}
+		if len(got) != len(tt.expected) {
+			t.Errorf("value=%q got=%v expected=%v", tt.value, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("value=%q got=%v expected=%v", tt.value, got, tt.expected)
+			}
+		}
+	}
+}
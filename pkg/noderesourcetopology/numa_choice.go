@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// The reasons a NUMA node is chosen among the feasible ones.
+const (
+	numaChoiceOnlyFeasible      = "only feasible NUMA node"
+	numaChoiceLowestID          = "lowest feasible ID"
+	numaChoicePowerHint         = "lowest power hint"
+	numaChoicePowerHintTieBreak = "lowest ID among the lowest power hints"
+)
+
+// choose records the NUMA node as chosen for the next container, or for the pod, with the reason of the last choice.
+func (info *filterInfo) choose(numaID int) {
+	info.chosenNUMANodes = append(info.chosenNUMANodes, numaID)
+	info.chosenNUMAReasons = append(info.chosenNUMAReasons, info.numaChoiceReason)
+}
+
+type numaChoiceState struct {
+	containerReasons map[string]string
+}
+
+func (s *numaChoiceState) Clone() framework.StateData {
+	containerReasons := make(map[string]string, len(s.containerReasons))
+	for name, reason := range s.containerReasons {
+		containerReasons[name] = reason
+	}
+	return &numaChoiceState{
+		containerReasons: containerReasons,
+	}
+}
+
+func numaChoiceStateKey(nodeName string) framework.StateKey {
+	return framework.StateKey(Name + "/numa-choice/" + nodeName)
+}
+
+// storeNUMAChoiceReasons stores in the CycleState why the filter chose the NUMA node of each container of the pod,
+// to explain surprising placements.
+func storeNUMAChoiceReasons(cycleState *framework.CycleState, pod *v1.Pod, info *filterInfo) {
+	if len(info.chosenNUMAReasons) == 0 {
+		return
+	}
+	choices, ok := containerChoices(pod, info)
+	if !ok {
+		return
+	}
+	containerReasons := make(map[string]string, len(choices))
+	for name, idx := range choices {
+		containerReasons[name] = info.chosenNUMAReasons[idx]
+	}
+	cycleState.Write(numaChoiceStateKey(info.nodeName), &numaChoiceState{containerReasons: containerReasons})
+}
+
+// storedNUMAChoiceReasons returns why the NUMA node of each container of the pod on the node was chosen, as stored
+// by the filter. Returns false if the filter made no NUMA alignment decision for the node.
+func storedNUMAChoiceReasons(cycleState *framework.CycleState, nodeName string) (map[string]string, bool) {
+	data, err := cycleState.Read(numaChoiceStateKey(nodeName))
+	if err != nil {
+		return nil, false
+	}
+	state, ok := data.(*numaChoiceState)
+	if !ok {
+		return nil, false
+	}
+	return state.containerReasons, true
+}
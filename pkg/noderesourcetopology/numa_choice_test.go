@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterNUMAChoiceReasons(t *testing.T) {
+	const powerHint = "power-hint"
+
+	// hints and available cpus of the NUMA nodes, an empty hint is not reported
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy, hints, cpus []string) *topologyv1alpha2.NodeResourceTopology {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+		}
+		for idx := range hints {
+			zone := topologyv1alpha2.Zone{
+				Name: fmt.Sprintf("node-%d", idx),
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", cpus[idx]),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			}
+			if hints[idx] != "" {
+				zone.Attributes = topologyv1alpha2.AttributeList{{Name: powerHint, Value: hints[idx]}}
+			}
+			nrt.Zones = append(nrt.Zones, zone)
+		}
+		return nrt
+	}
+
+	podScope := topologyv1alpha2.SingleNUMANodePodLevel
+	containerScope := topologyv1alpha2.SingleNUMANodeContainerLevel
+	tests := []struct {
+		name     string
+		nrt      *topologyv1alpha2.NodeResourceTopology
+		cntReq   []map[string]string
+		expected map[string]string
+	}{
+		{
+			name:     "only feasible NUMA node",
+			nrt:      makeNRT("only-feasible", podScope, []string{"", "", ""}, []string{"1", "4", "1"}),
+			cntReq:   []map[string]string{{cpu: "2", memory: "1Gi"}},
+			expected: map[string]string{"cnt-1": numaChoiceOnlyFeasible},
+		},
+		{
+			name:     "lowest feasible ID",
+			nrt:      makeNRT("lowest-id", podScope, []string{"", "", ""}, []string{"1", "4", "4"}),
+			cntReq:   []map[string]string{{cpu: "2", memory: "1Gi"}},
+			expected: map[string]string{"cnt-1": numaChoiceLowestID},
+		},
+		{
+			name:     "lowest power hint",
+			nrt:      makeNRT("power-hint", podScope, []string{"30", "10", "20"}, []string{"4", "4", "4"}),
+			cntReq:   []map[string]string{{cpu: "2", memory: "1Gi"}},
+			expected: map[string]string{"cnt-1": numaChoicePowerHint},
+		},
+		{
+			name:     "tie-break on the power hints",
+			nrt:      makeNRT("tie-break", podScope, []string{"20", "10", "10"}, []string{"4", "4", "4"}),
+			cntReq:   []map[string]string{{cpu: "2", memory: "1Gi"}},
+			expected: map[string]string{"cnt-1": numaChoicePowerHintTieBreak},
+		},
+		{
+			name:   "container scope",
+			nrt:    makeNRT("container-scope", containerScope, []string{"", ""}, []string{"2", "4"}),
+			cntReq: []map[string]string{{cpu: "2", memory: "1Gi"}, {cpu: "2", memory: "1Gi"}},
+			expected: map[string]string{
+				"cnt-1": numaChoiceLowestID,
+				"cnt-2": numaChoiceOnlyFeasible,
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, tt := range tests {
+		if err := fakeClient.Create(context.Background(), tt.nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				numaPowerHintAttribute: powerHint,
+			}
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(tt.cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			reasons, ok := storedNUMAChoiceReasons(state, tt.nrt.Name)
+			if !ok {
+				t.Fatalf("missing NUMA choice reasons")
+			}
+			if !reflect.DeepEqual(reasons, tt.expected) {
+				t.Errorf("reasons=%v expected=%v", reasons, tt.expected)
+			}
+		})
+	}
+}
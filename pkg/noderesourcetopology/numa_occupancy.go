@@ -0,0 +1,251 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	k8scache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// numaOccupancy reconstructs the availability of the NUMA nodes from the NUMA nodes the running pods are assigned
+// to, and records in the pods it places the NUMA nodes the filter chose for them.
+type numaOccupancy struct {
+	client kubernetes.Interface
+	lock   sync.Mutex
+	// NUMA nodes of the pods reserved by the plugin whose annotation was not seen yet, until the pods are deleted
+	placed map[types.UID][]int
+}
+
+func newNUMAOccupancy(client kubernetes.Interface) *numaOccupancy {
+	return &numaOccupancy{
+		client: client,
+		placed: make(map[types.UID][]int),
+	}
+}
+
+// reserve remembers the NUMA nodes the filter chose for the pod on the reserved node, so the pod is accounted for
+// until its annotation is recorded.
+func (no *numaOccupancy) reserve(cycleState *framework.CycleState, pod *v1.Pod, nodeName string) {
+	if no == nil {
+		return
+	}
+	containerNUMANodes, ok := storedPlacement(cycleState, nodeName)
+	if !ok {
+		return
+	}
+	seen := make(map[int]bool, len(containerNUMANodes))
+	var ids []int
+	for _, numaID := range containerNUMANodes {
+		if seen[numaID] {
+			continue
+		}
+		seen[numaID] = true
+		ids = append(ids, numaID)
+	}
+	sort.Ints(ids)
+
+	no.lock.Lock()
+	defer no.lock.Unlock()
+	no.placed[pod.UID] = ids
+}
+
+// forgetDeletedPods makes the occupancy drop the NUMA nodes remembered for the pods deleted before their annotation
+// was seen, like the pods whose annotation could not be recorded.
+func (no *numaOccupancy) forgetDeletedPods(podInformer k8scache.SharedInformer) {
+	podInformer.AddEventHandler(k8scache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(k8scache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			pod, ok := obj.(*v1.Pod)
+			if !ok {
+				return
+			}
+			no.forget(pod)
+		},
+	})
+}
+
+func (no *numaOccupancy) forget(pod *v1.Pod) {
+	if no == nil {
+		return
+	}
+	no.lock.Lock()
+	defer no.lock.Unlock()
+	delete(no.placed, pod.UID)
+}
+
+// record patches the pod with the NUMA nodes the filter chose for it. Failures are logged only: the pod is
+// still accounted for on the NUMA nodes remembered at reserve time, which are kept until the pod is deleted.
+func (no *numaOccupancy) record(ctx context.Context, pod *v1.Pod) {
+	if no == nil {
+		return
+	}
+	no.lock.Lock()
+	ids, ok := no.placed[pod.UID]
+	no.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, strconv.Itoa(id))
+	}
+	val := strings.Join(items, ",")
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				AnnotationAssignedNUMANodes: val,
+			},
+		},
+	})
+	if err != nil {
+		klog.ErrorS(err, "cannot encode the NUMA nodes of the pod", "pod", klog.KObj(pod))
+		return
+	}
+	_, err = no.client.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		klog.ErrorS(err, "cannot record the NUMA nodes of the pod", "pod", klog.KObj(pod), "annotation", AnnotationAssignedNUMANodes, "value", val)
+		return
+	}
+	klog.V(5).InfoS("recorded the NUMA nodes of the pod", "pod", klog.KObj(pod), "annotation", AnnotationAssignedNUMANodes, "value", val)
+}
+
+// assignedNUMANodes returns the NUMA nodes the running pod is assigned to, from its annotation if recorded,
+// otherwise from the NUMA nodes remembered at reserve time.
+func (no *numaOccupancy) assignedNUMANodes(pod *v1.Pod) ([]int, bool) {
+	if val, ok := pod.Annotations[AnnotationAssignedNUMANodes]; ok {
+		no.forget(pod)
+		ids, err := parseNUMANodeIDs(val)
+		if err != nil {
+			klog.V(4).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationAssignedNUMANodes, "err", err)
+			return nil, false
+		}
+		return ids, len(ids) > 0
+	}
+	no.lock.Lock()
+	defer no.lock.Unlock()
+	ids, ok := no.placed[pod.UID]
+	return ids, ok
+}
+
+// reconstruct replaces the availability the NUMA nodes report with their allocatable resources minus the requests
+// of the running pods assigned to them. The requests of a pod assigned to many NUMA nodes are subtracted from each
+// of them, since how they are split is unknown. Pods whose NUMA nodes are unknown, like the pods placed by other
+// schedulers, can't be accounted for, so the reconstructed availability never exceeds the reported one.
+func (no *numaOccupancy) reconstruct(nodeName string, numaNodes NUMANodeList, zones topologyv1alpha2.ZoneList, pods []*framework.PodInfo, policy qosResourcePolicy) NUMANodeList {
+	if no == nil {
+		return numaNodes
+	}
+	reported := make(map[int]v1.ResourceList, len(numaNodes))
+	for _, numaNode := range numaNodes {
+		reported[numaNode.NUMAID] = numaNode.Resources.DeepCopy()
+	}
+	allocatable := make(map[int]v1.ResourceList)
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		numaID, err := getID(zone.Name)
+		if err != nil {
+			continue
+		}
+		resources := make(v1.ResourceList)
+		for _, resInfo := range zone.Resources {
+			// not all the producers report the allocatable of the NUMA zones
+			if resInfo.Allocatable.IsZero() {
+				resources[v1.ResourceName(resInfo.Name)] = resInfo.Capacity.DeepCopy()
+			} else {
+				resources[v1.ResourceName(resInfo.Name)] = resInfo.Allocatable.DeepCopy()
+			}
+		}
+		allocatable[numaID] = resources
+	}
+	for _, numaNode := range numaNodes {
+		for resName := range numaNode.Resources {
+			if quantity, ok := allocatable[numaNode.NUMAID][resName]; ok {
+				numaNode.Resources[resName] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	for _, podInfo := range pods {
+		pod := podInfo.Pod
+		ids, ok := no.assignedNUMANodes(pod)
+		if !ok {
+			klog.V(6).InfoS("NUMA nodes of the running pod unknown, not accounted for", "node", nodeName, "pod", klog.KObj(pod))
+			continue
+		}
+		qos := v1qos.GetPodQOS(pod)
+		requests := util.GetPodEffectiveRequest(pod)
+		for _, numaNode := range numaNodes {
+			if !containsNUMAID(ids, numaNode.NUMAID) {
+				continue
+			}
+			for resName, quantity := range requests {
+				available, ok := numaNode.Resources[resName]
+				if !ok || policy.capacityIgnored(qos, resName) {
+					continue
+				}
+				available.Sub(quantity)
+				if available.Sign() < 0 {
+					available = *resource.NewQuantity(0, available.Format)
+				}
+				numaNode.Resources[resName] = available
+			}
+			klog.V(6).InfoS("running pod accounted for", "node", nodeName, "pod", klog.KObj(pod), "NUMA", numaNode.NUMAID)
+		}
+	}
+	for _, numaNode := range numaNodes {
+		for resName, available := range numaNode.Resources {
+			if quantity, ok := reported[numaNode.NUMAID][resName]; ok && quantity.Cmp(available) < 0 {
+				klog.V(6).InfoS("reported availability lower than the reconstructed one, unknown pods running", "node", nodeName, "NUMA", numaNode.NUMAID, "resource", resName)
+				numaNode.Resources[resName] = quantity
+			}
+		}
+	}
+	klog.V(5).InfoS("NUMA occupancy reconstructed from the running pods", "node", nodeName, "pods", len(pods))
+	return numaNodes
+}
+
+func containsNUMAID(ids []int, numaID int) bool {
+	for _, id := range ids {
+		if id == numaID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makeRunningPod(name string, cpus string, numaNodes string) *v1.Pod {
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse(cpus),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	pod.Name = name
+	pod.Namespace = "default"
+	pod.UID = types.UID(name)
+	if numaNodes != "" {
+		pod.Annotations = map[string]string{AnnotationAssignedNUMANodes: numaNodes}
+	}
+	return pod
+}
+
+// the NUMA zones report all their resources as available, as a stale or coarse producer would
+func makeStaleOccupancyNRT(name string) *topologyv1alpha2.NodeResourceTopology {
+	return &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: name},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+}
+
+func TestNUMAOccupancyFromPods(t *testing.T) {
+	nrt := makeStaleOccupancyNRT("occupancy-node")
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		disabled bool
+		running  []*v1.Pod
+		// running pods reserved by the plugin, by NUMA node, not annotated yet
+		reserved map[string][]int
+		expected framework.Code
+	}{
+		{
+			name:     "disabled",
+			disabled: true,
+			running:  []*v1.Pod{makeRunningPod("pod-0", "3", "0"), makeRunningPod("pod-1", "3", "1")},
+			expected: framework.Success,
+		},
+		{
+			name:     "annotated pods fill both NUMA nodes",
+			running:  []*v1.Pod{makeRunningPod("pod-0", "3", "0"), makeRunningPod("pod-1", "3", "1")},
+			expected: framework.Unschedulable,
+		},
+		{
+			name:     "annotated pod fills one NUMA node",
+			running:  []*v1.Pod{makeRunningPod("pod-0", "3", "0")},
+			expected: framework.Success,
+		},
+		{
+			name:     "pod spanning both NUMA nodes",
+			running:  []*v1.Pod{makeRunningPod("pod-0", "3", "0,1")},
+			expected: framework.Unschedulable,
+		},
+		{
+			name:     "reserved pod not annotated yet",
+			running:  []*v1.Pod{makeRunningPod("pod-0", "3", "0"), makeRunningPod("pod-1", "3", "")},
+			reserved: map[string][]int{"pod-1": {1}},
+			expected: framework.Unschedulable,
+		},
+		{
+			name:     "pods with unknown NUMA nodes",
+			running:  []*v1.Pod{makeRunningPod("pod-0", "3", ""), makeRunningPod("pod-1", "3", "")},
+			expected: framework.Success,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			if !tt.disabled {
+				tm.numaOccupancy = newNUMAOccupancy(fake.NewSimpleClientset())
+				for name, ids := range tt.reserved {
+					tm.numaOccupancy.placed[types.UID(name)] = ids
+				}
+			}
+
+			nodeInfo := framework.NewNodeInfo(tt.running...)
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			})
+			status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if status.Code() != tt.expected {
+				t.Errorf("unexpected filter status: %v, expected code %v", status, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNUMAOccupancyForgetsAnnotatedPods(t *testing.T) {
+	no := newNUMAOccupancy(fake.NewSimpleClientset())
+	no.placed["pod-0"] = []int{1}
+
+	ids, ok := no.assignedNUMANodes(makeRunningPod("pod-0", "1", "0"))
+	if !ok || len(ids) != 1 || ids[0] != 0 {
+		t.Errorf("unexpected NUMA nodes: %v, expected the annotation to take precedence", ids)
+	}
+	if _, ok := no.placed["pod-0"]; ok {
+		t.Errorf("reserved NUMA nodes of the annotated pod not forgotten")
+	}
+}
+
+func TestNUMAOccupancyClampedToReported(t *testing.T) {
+	// pods placed by another scheduler, unknown to the plugin, leave a single CPU on each NUMA node
+	nrt := makeStaleOccupancyNRT("foreign-pods-node")
+	for i := range nrt.Zones {
+		nrt.Zones[i].Resources[0] = MakeTopologyResInfo(cpu, "4", "1")
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	tm := &TopologyMatch{
+		nrtCache:      nrtcache.NewPassthrough(fakeClient),
+		numaOccupancy: newNUMAOccupancy(fake.NewSimpleClientset()),
+	}
+
+	nodeInfo := framework.NewNodeInfo(makeRunningPod("foreign-pod", "3", ""))
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	if status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo); status.Code() != framework.Unschedulable {
+		t.Errorf("unexpected filter status: %v, expected the reported availability to prevail", status)
+	}
+}
+
+func TestNUMAOccupancyForgetsDeletedPods(t *testing.T) {
+	pod := makeRunningPod("pod-0", "1", "")
+	clientset := fake.NewSimpleClientset(pod)
+	no := newNUMAOccupancy(clientset)
+	no.placed[pod.UID] = []int{1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	no.forgetDeletedPods(informerFactory.Core().V1().Pods().Informer())
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	if err := clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, wait.ForeverTestTimeout, true, func(context.Context) (bool, error) {
+		no.lock.Lock()
+		defer no.lock.Unlock()
+		_, ok := no.placed[pod.UID]
+		return !ok, nil
+	})
+	if err != nil {
+		t.Errorf("reserved NUMA nodes of the deleted pod not forgotten")
+	}
+}
+
+func TestNUMAOccupancyKeepsUnrecordedPods(t *testing.T) {
+	// the pod is unknown to the API server, so the patch fails
+	pod := makeRunningPod("pod-0", "1", "")
+	no := newNUMAOccupancy(fake.NewSimpleClientset())
+	no.placed[pod.UID] = []int{1}
+
+	no.record(context.Background(), pod)
+	ids, ok := no.assignedNUMANodes(pod)
+	if !ok || len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("unexpected NUMA nodes: %v, expected the reserved ones to be kept", ids)
+	}
+}
+
+func TestNUMAOccupancyPreBind(t *testing.T) {
+	nrt := makeStaleOccupancyNRT("prebind-node")
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		unreserve  bool
+		expected   string
+		annotation bool
+	}{
+		{
+			name:       "bound pod",
+			expected:   "1",
+			annotation: true,
+		},
+		{
+			name:      "unreserved pod",
+			unreserve: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makeRunningPod("testpod", "2", "")
+			clientset := fake.NewSimpleClientset(pod)
+			tm := &TopologyMatch{
+				nrtCache:      nrtcache.NewPassthrough(fakeClient),
+				numaOccupancy: newNUMAOccupancy(clientset),
+			}
+
+			nodeInfo := framework.NewNodeInfo(makeRunningPod("pod-0", "3", "0"))
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			state := framework.NewCycleState()
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			if status := tm.Reserve(context.Background(), state, pod, nrt.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected reserve status: %v", status)
+			}
+			if tt.unreserve {
+				tm.Unreserve(context.Background(), state, pod, nrt.Name)
+			}
+			if status := tm.PreBind(context.Background(), state, pod, nrt.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected prebind status: %v", status)
+			}
+
+			updated, err := clientset.CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			val, ok := updated.Annotations[AnnotationAssignedNUMANodes]
+			if ok != tt.annotation || val != tt.expected {
+				t.Errorf("unexpected annotation %q (present: %v), expected %q (present: %v)", val, ok, tt.expected, tt.annotation)
+			}
+		})
+	}
+}
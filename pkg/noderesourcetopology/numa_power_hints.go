@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	"k8s.io/klog/v2"
+)
+
+// numaNodePowerHints maps the NUMA nodes to the power hint reported in the given attribute of their zone.
+// The NUMA nodes not reporting a valid hint are omitted. Returns nil if the attribute is not set, or no NUMA node reports it.
+func numaNodePowerHints(zones topologyv1alpha2.ZoneList, attrName string) map[int]int64 {
+	if attrName == "" {
+		return nil
+	}
+	var hints map[int]int64
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		numaID, err := getID(zone.Name)
+		if err != nil {
+			continue
+		}
+		for _, attr := range zone.Attributes {
+			if attr.Name != attrName {
+				continue
+			}
+			hint, err := strconv.ParseInt(attr.Value, 10, 64)
+			if err != nil {
+				klog.V(2).InfoS("ignoring malformed power hint", "zone", zone.Name, "attribute", attrName, "value", attr.Value)
+				break
+			}
+			if hints == nil {
+				hints = make(map[int]int64)
+			}
+			hints[numaID] = hint
+			break
+		}
+	}
+	return hints
+}
+
+// preferredNUMAID returns, among the given feasible NUMA IDs in ascending order, the one with the lowest power hint,
+// the lowest ID on ties, along with the reason it was chosen. Like the kubelet, it returns the lowest ID if none of the
+// NUMA nodes reports a hint.
+func (info *filterInfo) preferredNUMAID(numaIDs []int) (int, string) {
+	preferred := numaIDs[0]
+	preferredHint, preferredHinted := info.numaPowerHints[preferred]
+	tied := false
+	for _, numaID := range numaIDs[1:] {
+		hint, ok := info.numaPowerHints[numaID]
+		if !ok {
+			continue
+		}
+		if !preferredHinted || hint < preferredHint {
+			preferred, preferredHint, preferredHinted = numaID, hint, true
+			tied = false
+		} else if hint == preferredHint {
+			tied = true
+		}
+	}
+	switch {
+	case len(numaIDs) == 1:
+		return preferred, numaChoiceOnlyFeasible
+	case !preferredHinted:
+		return preferred, numaChoiceLowestID
+	case tied:
+		return preferred, numaChoicePowerHintTieBreak
+	default:
+		return preferred, numaChoicePowerHint
+	}
+}
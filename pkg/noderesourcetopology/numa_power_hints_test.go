@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterNUMAPowerHints(t *testing.T) {
+	const powerHint = "power-hint"
+
+	// hints and available cpus of the NUMA nodes, an empty hint is not reported
+	makeNRT := func(name string, hints, cpus []string) *topologyv1alpha2.NodeResourceTopology {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		}
+		for idx := range hints {
+			zone := topologyv1alpha2.Zone{
+				Name: fmt.Sprintf("node-%d", idx),
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", cpus[idx]),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			}
+			if hints[idx] != "" {
+				zone.Attributes = topologyv1alpha2.AttributeList{{Name: powerHint, Value: hints[idx]}}
+			}
+			nrt.Zones = append(nrt.Zones, zone)
+		}
+		return nrt
+	}
+
+	tests := []struct {
+		name          string
+		nrt           *topologyv1alpha2.NodeResourceTopology
+		hintAttribute string
+		expected      int
+	}{
+		{
+			name:     "hints not used",
+			nrt:      makeNRT("not-used", []string{"30", "10", "20"}, []string{"4", "4", "4"}),
+			expected: 0,
+		},
+		{
+			name:          "lowest hint preferred",
+			nrt:           makeNRT("hinted", []string{"30", "10", "20"}, []string{"4", "4", "4"}),
+			hintAttribute: powerHint,
+			expected:      1,
+		},
+		{
+			name:          "lowest hint among the fitting NUMA nodes",
+			nrt:           makeNRT("busy", []string{"30", "10", "20"}, []string{"4", "1", "4"}),
+			hintAttribute: powerHint,
+			expected:      2,
+		},
+		{
+			name:          "NUMA nodes without hint come last",
+			nrt:           makeNRT("partial", []string{"", "", "20"}, []string{"4", "4", "4"}),
+			hintAttribute: powerHint,
+			expected:      2,
+		},
+		{
+			name:          "lowest ID on ties",
+			nrt:           makeNRT("ties", []string{"20", "10", "10"}, []string{"4", "4", "4"}),
+			hintAttribute: powerHint,
+			expected:      1,
+		},
+		{
+			name:          "hints absent",
+			nrt:           makeNRT("absent", []string{"", "", ""}, []string{"4", "4", "4"}),
+			hintAttribute: powerHint,
+			expected:      0,
+		},
+		{
+			name:          "malformed hint ignored",
+			nrt:           makeNRT("malformed", []string{"low", "30", "20"}, []string{"4", "4", "4"}),
+			hintAttribute: powerHint,
+			expected:      2,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, tt := range tests {
+		if err := fakeClient.Create(context.Background(), tt.nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cntReq := []map[string]string{
+		{cpu: "2", memory: "1Gi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakePlacementRecorder{}
+			tm := &TopologyMatch{
+				nrtCache:               nrtcache.NewPassthrough(fakeClient),
+				numaPowerHintAttribute: tt.hintAttribute,
+			}
+			WithPlacementRecorder(recorder)(tm)
+
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			if status := tm.Reserve(context.Background(), state, pod, tt.nrt.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected reserve status: %v", status)
+			}
+			expected := []recordedPlacement{
+				{podName: pod.Name, nodeName: tt.nrt.Name, containerNUMANodes: map[string]int{"cnt-1": tt.expected}},
+			}
+			if !reflect.DeepEqual(recorder.placements, expected) {
+				t.Errorf("placements=%v expected=%v", recorder.placements, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// victimSetCost measures the disruption caused by evicting a set of victims, like the default preemption does
+// when picking the node: the highest victim priority first, then the sum of the priorities, then the victims count.
+type victimSetCost struct {
+	highestPriority int32
+	prioritiesSum   int64
+	victims         int
+}
+
+func newVictimSetCost(victims []*v1.Pod) victimSetCost {
+	cost := victimSetCost{
+		highestPriority: math.MinInt32,
+		victims:         len(victims),
+	}
+	for _, victim := range victims {
+		priority := corev1helpers.PodPriority(victim)
+		if priority > cost.highestPriority {
+			cost.highestPriority = priority
+		}
+		cost.prioritiesSum += int64(priority)
+	}
+	return cost
+}
+
+func (c victimSetCost) lessThan(other victimSetCost) bool {
+	if c.highestPriority != other.highestPriority {
+		return c.highestPriority < other.highestPriority
+	}
+	if c.prioritiesSum != other.prioritiesSum {
+		return c.prioritiesSum < other.prioritiesSum
+	}
+	return c.victims < other.victims
+}
+
+// SelectNUMAVictimSet returns the index of the candidate victim set to evict from a node with the given topology to make
+// room for the pod, the one disrupting the least among the sets whose eviction frees room on a single NUMA node: like the
+// default preemption, the highest victim priority is compared first, then the sum of the priorities, then the victims count.
+// Meant for the preemption integrations, which compute the candidate sets and are expected to evict the selected one.
+// The requests of each victim are given back to the NUMA node recorded in its assigned-numa-nodes annotation; the victims
+// without it, or spanning more NUMA nodes, are assumed to free nothing.
+// Returns an error if the NRT object is invalid, or no candidate set frees room on a NUMA node for the pod, in which case
+// the preemption would be useless.
+func SelectNUMAVictimSet(nodeTopology *topologyv1alpha2.NodeResourceTopology, pod *v1.Pod, candidates [][]*v1.Pod) (int, error) {
+	if errs := ValidateNRT(nodeTopology); len(errs) > 0 {
+		return -1, errors.Join(errs...)
+	}
+	selected, ok := selectNUMAVictimSet(pod, createNUMANodeList(nodeTopology.Zones), candidates)
+	if !ok {
+		return -1, fmt.Errorf("no candidate victim set frees room on a NUMA node of %q for the pod", nodeTopology.Name)
+	}
+	return selected, nil
+}
+
+// selectNUMAVictimSet returns the index of the candidate victim set disrupting the least among the ones whose eviction
+// frees room on a NUMA node for the pod. Returns false if no candidate does: evicting the victims could make room on
+// the node as a whole, yet not on any single NUMA node, and the preemption would be useless.
+func selectNUMAVictimSet(pod *v1.Pod, numaNodes NUMANodeList, candidates [][]*v1.Pod) (int, bool) {
+	selected := -1
+	var selectedCost victimSetCost
+	for idx, victims := range candidates {
+		if !victimsFreeNUMANode(pod, numaNodes, victims) {
+			klog.V(5).InfoS("victims don't free a NUMA node for the pod", "pod", klog.KObj(pod), "candidate", idx)
+			continue
+		}
+		cost := newVictimSetCost(victims)
+		if selected < 0 || cost.lessThan(selectedCost) {
+			selected, selectedCost = idx, cost
+		}
+	}
+	return selected, selected >= 0
+}
+
+// victimsFreeNUMANode returns true if the pod fits on one of the NUMA nodes once the victims are evicted. The requests
+// of each victim are given back to the NUMA node recorded in its assigned-numa-nodes annotation; the victims without it,
+// or spanning more NUMA nodes, can't be accounted for and are conservatively assumed to free nothing.
+func victimsFreeNUMANode(pod *v1.Pod, numaNodes NUMANodeList, victims []*v1.Pod) bool {
+	available := make(map[int]v1.ResourceList, len(numaNodes))
+	for _, numaNode := range numaNodes {
+		available[numaNode.NUMAID] = numaNode.Resources.DeepCopy()
+	}
+	for _, victim := range victims {
+		ids, err := parseNUMANodeIDs(victim.Annotations[AnnotationAssignedNUMANodes])
+		if err != nil || len(ids) != 1 {
+			klog.V(5).InfoS("cannot account the NUMA resources of the victim", "pod", klog.KObj(victim), "annotation", AnnotationAssignedNUMANodes)
+			continue
+		}
+		resources, ok := available[ids[0]]
+		if !ok {
+			continue
+		}
+		for resName, quantity := range util.GetPodEffectiveRequest(victim) {
+			freed, ok := resources[resName]
+			if !ok {
+				// not a NUMA-affine resource
+				continue
+			}
+			freed.Add(quantity)
+			resources[resName] = freed
+		}
+	}
+
+	requests := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	for _, numaNode := range numaNodes {
+		if numaFitsRequests(requests, available[numaNode.NUMAID]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSelectNUMAVictimSet(t *testing.T) {
+	makeVictim := func(name string, priority int32, numaNodes, cpus string) *v1.Pod {
+		pod := makePodByResourceList(&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpus),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		})
+		pod.Name = name
+		pod.Spec.Priority = &priority
+		pod.Annotations = map[string]string{AnnotationAssignedNUMANodes: numaNodes}
+		return pod
+	}
+	lowOnNUMA0 := makeVictim("low-numa0", 10, "0", "3")
+	highOnNUMA1 := makeVictim("high-numa1", 100, "1", "3")
+	lowOnNUMA1 := []*v1.Pod{
+		makeVictim("low-numa1-a", 10, "1", "2"),
+		makeVictim("low-numa1-b", 10, "1", "1"),
+	}
+	// frees enough cpus on the node, but not on a single NUMA node
+	split := []*v1.Pod{
+		makeVictim("split-numa0", 5, "0", "2"),
+		makeVictim("split-numa1", 5, "1", "2"),
+	}
+	spanning := makeVictim("spanning", 1, "0,1", "4")
+
+	numaNodes := NUMANodeList{
+		{
+			NUMAID: 0,
+			Resources: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			NUMAID: 1,
+			Resources: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+
+	tests := []struct {
+		name       string
+		candidates [][]*v1.Pod
+		expected   int
+		found      bool
+	}{
+		{
+			name:       "lowest priority victims",
+			candidates: [][]*v1.Pod{{highOnNUMA1}, {lowOnNUMA0}},
+			expected:   1,
+			found:      true,
+		},
+		{
+			name:       "fewest victims of the same priority",
+			candidates: [][]*v1.Pod{lowOnNUMA1, {lowOnNUMA0}},
+			expected:   1,
+			found:      true,
+		},
+		{
+			name:       "only the victims freeing a NUMA node",
+			candidates: [][]*v1.Pod{split, {highOnNUMA1}},
+			expected:   1,
+			found:      true,
+		},
+		{
+			name:       "victims not freeing a NUMA node",
+			candidates: [][]*v1.Pod{split},
+			expected:   -1,
+		},
+		{
+			name:       "victim spanning NUMA nodes",
+			candidates: [][]*v1.Pod{{spanning}},
+			expected:   -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := selectNUMAVictimSet(pod, numaNodes, tt.candidates)
+			if got != tt.expected || found != tt.found {
+				t.Errorf("selected=%d,%v expected=%d,%v", got, found, tt.expected, tt.found)
+			}
+		})
+	}
+}
+
+func TestSelectNUMAVictimSetNodeTopology(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "1"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "1"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("3"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	victim := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+	victim.Annotations = map[string]string{AnnotationAssignedNUMANodes: "1"}
+	halfVictims := []*v1.Pod{victim.DeepCopy(), victim.DeepCopy()}
+	halfVictims[0].Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("1")
+	halfVictims[0].Annotations = map[string]string{AnnotationAssignedNUMANodes: "0"}
+	halfVictims[1].Spec.Containers[0].Resources.Requests[v1.ResourceCPU] = resource.MustParse("1")
+
+	got, err := SelectNUMAVictimSet(nrt, pod, [][]*v1.Pod{halfVictims, {victim}})
+	if err != nil || got != 1 {
+		t.Errorf("selected=%d err=%v expected=1", got, err)
+	}
+	if got, err := SelectNUMAVictimSet(nrt, pod, [][]*v1.Pod{halfVictims}); err == nil {
+		t.Errorf("selected=%d, expected an error", got)
+	}
+
+	invalid := nrt.DeepCopy()
+	invalid.Zones[0].Resources[0].Available = resource.MustParse("8")
+	if got, err := SelectNUMAVictimSet(invalid, pod, [][]*v1.Pod{{victim}}); err == nil {
+		t.Errorf("selected=%d, expected an error for the invalid NRT object", got)
+	}
+}
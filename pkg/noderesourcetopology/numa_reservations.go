@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strings"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+// ZoneAttributeReserved is the name of the zone attribute holding the resources reserved on a NUMA node, which the filter
+// doesn't align any pod on, as comma-separated list of `resource=quantity` items, e.g. "nvidia.com/gpu=2,cpu=4".
+const ZoneAttributeReserved = "numaReserved"
+
+// parseNUMAReservation returns the resources reserved by the attribute value. Malformed items are ignored.
+func parseNUMAReservation(zoneName, val string) v1.ResourceList {
+	reserved := make(v1.ResourceList)
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		resName, value, _ := strings.Cut(item, "=")
+		quantity, err := resource.ParseQuantity(value)
+		if resName == "" || err != nil || quantity.Sign() < 0 {
+			klog.V(4).InfoS("ignoring malformed zone attribute item", "zone", zoneName, "attribute", ZoneAttributeReserved, "item", item)
+			continue
+		}
+		reserved[v1.ResourceName(resName)] = quantity
+	}
+	return reserved
+}
+
+// reserveNUMAResources removes from the availability of the NUMA nodes the resources their zones reserve, down to zero.
+func reserveNUMAResources(nodeName string, numaNodes NUMANodeList, zones topologyv1alpha2.ZoneList) NUMANodeList {
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		for _, attr := range zone.Attributes {
+			if attr.Name != ZoneAttributeReserved {
+				continue
+			}
+			numaID, err := getID(zone.Name)
+			if err != nil {
+				continue
+			}
+			reserved := parseNUMAReservation(zone.Name, attr.Value)
+			for _, numaNode := range numaNodes {
+				if numaNode.NUMAID != numaID {
+					continue
+				}
+				for resName, quantity := range reserved {
+					available, ok := numaNode.Resources[resName]
+					if !ok {
+						continue
+					}
+					available.Sub(quantity)
+					if available.Sign() < 0 {
+						available = *resource.NewQuantity(0, available.Format)
+					}
+					numaNode.Resources[resName] = available
+				}
+				klog.V(5).InfoS("reserved NUMA resources", "node", nodeName, "NUMA", numaID, "reserved", attr.Value)
+			}
+		}
+	}
+	return numaNodes
+}
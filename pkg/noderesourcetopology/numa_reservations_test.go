@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterNUMAReservations(t *testing.T) {
+	makeNRT := func(name, numa0Reserved, numa1Reserved string) *topologyv1alpha2.NodeResourceTopology {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+						MakeTopologyResInfo(nicResourceName, "4", "4"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+						MakeTopologyResInfo(nicResourceName, "4", "4"),
+					},
+				},
+			},
+		}
+		for idx, reserved := range []string{numa0Reserved, numa1Reserved} {
+			if reserved == "" {
+				continue
+			}
+			nrt.Zones[idx].Attributes = topologyv1alpha2.AttributeList{
+				{Name: ZoneAttributeReserved, Value: reserved},
+			}
+		}
+		return nrt
+	}
+
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("unreserved", "", ""),
+		makeNRT("reserved-numa0", nicResourceName+"=2", ""),
+		makeNRT("reserved-all", nicResourceName+"=2", nicResourceName+"=2, cpu=4"),
+		makeNRT("reserved-beyond-available", "cpu=12", "cpu=6"),
+		makeNRT("reserved-malformed", nicResourceName+"=two", nicResourceName+"=-2,=2"),
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cannotAlign := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		wantStatus *framework.Status
+	}{
+		{
+			name: "no reservations",
+			nrt:  nrts[0],
+		},
+		{
+			name: "one NUMA node reserved",
+			nrt:  nrts[1],
+		},
+		{
+			name:       "all the NUMA nodes reserved",
+			nrt:        nrts[2],
+			wantStatus: cannotAlign,
+		},
+		{
+			name:       "reservations beyond the availability",
+			nrt:        nrts[3],
+			wantStatus: cannotAlign,
+		},
+		{
+			name: "malformed reservations ignored",
+			nrt:  nrts[4],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				nicResourceName:   resource.MustParse("3"),
+			})
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// spanningResources is the set of the resources which may be allocated from many NUMA nodes, like the memory
+// can with a performance penalty. All the other resources must be allocated from a single NUMA node.
+type spanningResources map[v1.ResourceName]struct{}
+
+func newSpanningResources(specs []apiconfig.NUMASpanSpec) spanningResources {
+	var sr spanningResources
+	for _, spec := range specs {
+		if spec.Mode != apiconfig.NUMASpanMaySpan {
+			continue
+		}
+		if sr == nil {
+			sr = make(spanningResources)
+		}
+		sr[v1.ResourceName(spec.Name)] = struct{}{}
+	}
+	return sr
+}
+
+func (sr spanningResources) has(resName v1.ResourceName) bool {
+	_, ok := sr[resName]
+	return ok
+}
+
+// spanningResourceFits checks the quantity of the spanning resource against the sum of its availability on the NUMA
+// nodes the pod can use. reported is false if no NUMA node exposes the resource.
+func (info *filterInfo) spanningResourceFits(qos v1.PodQOSClass, resName v1.ResourceName, quantity resource.Quantity) (fits bool, reported bool) {
+	var available resource.Quantity
+	for _, numaNode := range info.numaNodes {
+		numaQuantity, ok := numaNode.Resources[resName]
+		if !ok {
+			continue
+		}
+		reported = true
+		if _, excluded := info.excludedNUMANodes[numaNode.NUMAID]; excluded {
+			continue
+		}
+		available.Add(numaQuantity)
+	}
+	if info.qosPolicy.capacityIgnored(qos, resName) {
+		return true, reported
+	}
+	return available.Cmp(info.rounding.roundUp(resName, quantity)) >= 0, reported
+}
+
+// consume subtracts the resources from the NUMA node, so they are not allocated again to the upcoming containers.
+// The spanning resources the NUMA node can't provide are taken from the other NUMA nodes the pod can use, lowest ID first.
+// The device instances the NUMA node can't provide are taken from the usable ones reported only at node level.
+func (info *filterInfo) consume(numaID int, resources v1.ResourceList) {
+	resources = info.takeNodeLevelDevices(numaID, info.sharedDevices.consumedResources(resources))
+	if len(info.spanningResources) == 0 {
+		subtractFromNUMA(info.numaNodes, numaID, resources, info.rounding)
+		return
+	}
+	pinned := make(v1.ResourceList, len(resources))
+	for resName, quantity := range resources {
+		if !info.spanningResources.has(resName) {
+			pinned[resName] = quantity
+			continue
+		}
+		info.spillOver(numaID, resName, info.rounding.roundUp(resName, quantity))
+	}
+	subtractFromNUMA(info.numaNodes, numaID, pinned, info.rounding)
+}
+
+func (info *filterInfo) spillOver(numaID int, resName v1.ResourceName, quantity resource.Quantity) {
+	idxs := make([]int, 0, len(info.numaNodes))
+	for idx, numaNode := range info.numaNodes {
+		if _, excluded := info.excludedNUMANodes[numaNode.NUMAID]; excluded && numaNode.NUMAID != numaID {
+			continue
+		}
+		idxs = append(idxs, idx)
+	}
+	// the NUMA node the pod is aligned on first, then the others by ID
+	sort.SliceStable(idxs, func(i, j int) bool {
+		ni, nj := info.numaNodes[idxs[i]].NUMAID, info.numaNodes[idxs[j]].NUMAID
+		if (ni == numaID) != (nj == numaID) {
+			return ni == numaID
+		}
+		return ni < nj
+	})
+
+	for _, idx := range idxs {
+		if quantity.Sign() <= 0 {
+			return
+		}
+		numaNode := info.numaNodes[idx]
+		available, ok := numaNode.Resources[resName]
+		if !ok || available.Sign() <= 0 {
+			continue
+		}
+		taken := quantity.DeepCopy()
+		if taken.Cmp(available) > 0 {
+			taken = available.DeepCopy()
+		}
+		available.Sub(taken)
+		numaNode.Resources[resName] = available
+		quantity.Sub(taken)
+		klog.V(6).InfoS("spanning resource taken", "node", info.nodeName, "NUMA", numaNode.NUMAID, "resource", resName, "quantity", taken.String())
+	}
+	if quantity.Sign() > 0 {
+		// not expected, since the availability was checked, but let's log it
+		klog.V(4).InfoS("spanning resource exceeds the NUMA nodes availability", "node", info.nodeName, "resource", resName, "quantity", quantity.String())
+	}
+}
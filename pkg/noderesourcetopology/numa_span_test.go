@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterNUMASpan(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "4Gi", "4Gi"),
+				MakeTopologyResInfo(nicResourceName, "1", "1"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "4Gi", "4Gi"),
+				MakeTopologyResInfo(nicResourceName, "1", "1"),
+			},
+		},
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "span-pod-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            zones,
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "span-container-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones:            zones,
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	memorySpans := []apiconfig.NUMASpanSpec{
+		{Name: memory, Mode: apiconfig.NUMASpanMaySpan},
+		{Name: nicResourceName, Mode: apiconfig.NUMASpanSingleNUMA},
+	}
+	tests := []struct {
+		name     string
+		nrt      *topologyv1alpha2.NodeResourceTopology
+		numaSpan []apiconfig.NUMASpanSpec
+		cntReq   []map[string]string
+		// NUMA node by container, nil if the pod is rejected
+		expected map[string]int
+	}{
+		{
+			name:   "pod scope, memory pinned",
+			nrt:    nrts[0],
+			cntReq: []map[string]string{{cpu: "2", memory: "6Gi", nicResourceName: "1"}},
+		},
+		{
+			name:     "pod scope, memory spans",
+			nrt:      nrts[0],
+			numaSpan: memorySpans,
+			cntReq:   []map[string]string{{cpu: "2", memory: "6Gi", nicResourceName: "1"}},
+			expected: map[string]int{"cnt-1": 0},
+		},
+		{
+			name:     "pod scope, memory spans, NICs pinned",
+			nrt:      nrts[0],
+			numaSpan: memorySpans,
+			cntReq:   []map[string]string{{cpu: "2", memory: "6Gi", nicResourceName: "2"}},
+		},
+		{
+			name:     "pod scope, memory spans beyond the node",
+			nrt:      nrts[0],
+			numaSpan: memorySpans,
+			cntReq:   []map[string]string{{cpu: "2", memory: "9Gi", nicResourceName: "1"}},
+		},
+		{
+			name: "container scope, memory pinned",
+			nrt:  nrts[1],
+			cntReq: []map[string]string{
+				{cpu: "2", memory: "5Gi", nicResourceName: "1"},
+				{cpu: "2", memory: "3Gi", nicResourceName: "1"},
+			},
+		},
+		{
+			name:     "container scope, memory spans, NICs pinned",
+			nrt:      nrts[1],
+			numaSpan: memorySpans,
+			cntReq: []map[string]string{
+				{cpu: "2", memory: "5Gi", nicResourceName: "1"},
+				{cpu: "2", memory: "3Gi", nicResourceName: "1"},
+			},
+			expected: map[string]int{"cnt-1": 0, "cnt-2": 1},
+		},
+		{
+			name:     "container scope, spanning memory consumed",
+			nrt:      nrts[1],
+			numaSpan: memorySpans,
+			cntReq: []map[string]string{
+				{cpu: "2", memory: "5Gi", nicResourceName: "1"},
+				{cpu: "2", memory: "4Gi", nicResourceName: "1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakePlacementRecorder{}
+			tm := &TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				spanningResources: newSpanningResources(tt.numaSpan),
+			}
+			WithPlacementRecorder(recorder)(tm)
+
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(tt.cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			status := tm.Filter(context.Background(), state, pod, nodeInfo)
+			if tt.expected == nil {
+				if status.Code() != framework.Unschedulable {
+					t.Fatalf("unexpected filter status: %v, expected the pod to be rejected", status)
+				}
+				return
+			}
+			if status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			if status := tm.Reserve(context.Background(), state, pod, tt.nrt.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected reserve status: %v", status)
+			}
+			expected := []recordedPlacement{
+				{podName: pod.Name, nodeName: tt.nrt.Name, containerNUMANodes: tt.expected},
+			}
+			if !reflect.DeepEqual(recorder.placements, expected) {
+				t.Errorf("placements=%v expected=%v", recorder.placements, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// numaSpreadComponent scores the chance to place the pod on a NUMA node not hosting any of its siblings, the running pods
+// with the same controller as the pod, whose NUMA nodes are read from their assigned-numa-nodes annotation. It doesn't
+// apply to the pods without a controller.
+func (tm *TopologyMatch) numaSpreadComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	controller := metav1.GetControllerOf(pod)
+	if controller == nil {
+		return 0, false, nil
+	}
+	nodeInfo, err := tm.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, false, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	siblingNUMAIDs := runningPodsNUMANodes(pod, nodeInfo, func(runningPod *v1.Pod) bool {
+		owner := metav1.GetControllerOf(runningPod)
+		return owner != nil && owner.UID == controller.UID
+	})
+	return numaSpreadScore(pod, createNUMANodeList(zones), siblingNUMAIDs), true, nil
+}
+
+// numaSpreadScore gives the maximum score if the pod fits on a NUMA node not hosting any of its siblings, the minimum
+// score if it fits only alongside them, and a neutral score to the nodes not running any sibling.
+func numaSpreadScore(pod *v1.Pod, numaNodes NUMANodeList, siblingNUMAIDs map[int]bool) int64 {
+	if len(siblingNUMAIDs) == 0 {
+		return neutralNodeScore
+	}
+	logID := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	qos := v1qos.GetPodQOS(pod)
+	resources := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	for _, numaNode := range numaNodes {
+		if siblingNUMAIDs[numaNode.NUMAID] {
+			continue
+		}
+		if checkResourcesFit(logID, qos, resources, numaNode.Resources) {
+			return framework.MaxNodeScore
+		}
+	}
+	return framework.MinNodeScore
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestScoreNUMASpread(t *testing.T) {
+	makeNRT := func(name, numa0CPUs, numa1CPUs string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", numa0CPUs),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", numa1CPUs),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+			},
+		}
+	}
+	// a sibling runs on the NUMA node 0 of "spread" and "crowded", the NUMA node 1 of "crowded" is full
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("spread", "6", "8"),
+		makeNRT("crowded", "6", "1"),
+		makeNRT("empty", "6", "8"),
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	var nodes []*v1.Node
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+		nodes = append(nodes, makeNodeFromNodeResourceTopology(nrt))
+	}
+
+	controller := func(uid string) []metav1.OwnerReference {
+		isController := true
+		return []metav1.OwnerReference{
+			{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-" + uid, UID: types.UID(uid), Controller: &isController},
+		}
+	}
+	makeRunningPod := func(name, nodeName, ownerUID, numaNodes string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "ns1",
+				Name:            name,
+				UID:             types.UID("uid-" + name),
+				OwnerReferences: controller(ownerUID),
+				Annotations:     map[string]string{AnnotationAssignedNUMANodes: numaNodes},
+			},
+			Spec: v1.PodSpec{NodeName: nodeName},
+		}
+	}
+	runningPods := []*v1.Pod{
+		makeRunningPod("sibling-0", "spread", "db", "0"),
+		makeRunningPod("sibling-1", "crowded", "db", "0"),
+		// not a sibling
+		makeRunningPod("other", "empty", "cache", "1"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fh, err := st.NewFramework(
+		ctx,
+		[]st.RegisterPluginFunc{
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		},
+		"default-scheduler",
+		frameworkruntime.WithSnapshotSharedLister(tu.NewFakeSharedLister(runningPods, nodes)),
+	)
+	if err != nil {
+		t.Fatalf("fail to create framework: %s", err)
+	}
+
+	tests := []struct {
+		name       string
+		weight     int64
+		controller string
+		expected   nodeToScoreMap
+	}{
+		{
+			name:       "disabled",
+			controller: "db",
+			expected:   nodeToScoreMap{"spread": 76, "crowded": 43, "empty": 76},
+		},
+		{
+			name:       "replica steered to a NUMA node without siblings",
+			weight:     50,
+			controller: "db",
+			// spread: (76 + 100) / 2, crowded: (43 + 0) / 2, empty: (76 + 50) / 2
+			expected: nodeToScoreMap{"spread": 88, "crowded": 21, "empty": 63},
+		},
+		{
+			name:     "pod without controller",
+			weight:   50,
+			expected: nodeToScoreMap{"spread": 76, "crowded": 43, "empty": 76},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				handle:            fh,
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc: leastAllocatedScoreStrategy,
+				scoreStrategyType: apiconfig.LeastAllocated,
+				numaSpreadWeight:  tt.weight,
+			}
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			})
+			pod.Namespace = "ns1"
+			if tt.controller != "" {
+				pod.OwnerReferences = controller(tt.controller)
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+)
+
+const (
+	// ZoneAttributeTaints is the name of the zone attribute holding the taints of a NUMA node,
+	// as comma-separated list of `key` or `key=value` items, e.g. "maintenance,reserved=sre".
+	ZoneAttributeTaints = "numaTaints"
+	// ZoneAttributeUnschedulable marks, when "true", a NUMA node as cordoned: no pod can be placed on it,
+	// while the pods already running on it are left untouched. Unlike taints, it can't be tolerated.
+	ZoneAttributeUnschedulable = "unschedulable"
+
+	// AnnotationNUMATolerations is the comma-separated list of the NUMA taints the pod tolerates.
+	// A `key` item tolerates any taint with that key, a `key=value` item only the exact match.
+	AnnotationNUMATolerations = AnnotationKeyPrefix + "numa-tolerations"
+)
+
+type numaTaint struct {
+	key   string
+	value string
+}
+
+func parseNUMATaints(val string) []numaTaint {
+	var taints []numaTaint
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(item, "=")
+		taints = append(taints, numaTaint{key: key, value: value})
+	}
+	return taints
+}
+
+// tolerates returns true if the toleration, parsed with the same format of the taints, matches the given taint.
+func (toleration numaTaint) tolerates(taint numaTaint) bool {
+	if toleration.key != taint.key {
+		return false
+	}
+	return toleration.value == "" || toleration.value == taint.value
+}
+
+func isNUMATaintTolerated(taint numaTaint, tolerations []numaTaint) bool {
+	for _, toleration := range tolerations {
+		if toleration.tolerates(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNUMANodeCordoned returns true if the zone is marked unschedulable. Malformed values are ignored.
+func isNUMANodeCordoned(zone topologyv1alpha2.Zone) bool {
+	for _, attr := range zone.Attributes {
+		if attr.Name != ZoneAttributeUnschedulable {
+			continue
+		}
+		cordoned, err := strconv.ParseBool(attr.Value)
+		if err != nil {
+			klog.V(4).InfoS("ignoring malformed zone attribute", "zone", zone.Name, "attribute", ZoneAttributeUnschedulable, "value", attr.Value)
+			return false
+		}
+		return cordoned
+	}
+	return false
+}
+
+// untoleratedNUMANodes returns the IDs of the NUMA nodes which are cordoned or have taints the pod doesn't tolerate,
+// mapped to the reason of the exclusion.
+func untoleratedNUMANodes(pod *v1.Pod, zones topologyv1alpha2.ZoneList) map[int]string {
+	var excluded map[int]string
+	tolerations := parseNUMATaints(pod.Annotations[AnnotationNUMATolerations])
+
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		if isNUMANodeCordoned(zone) {
+			numaID, err := getID(zone.Name)
+			if err != nil {
+				continue
+			}
+			if excluded == nil {
+				excluded = make(map[int]string)
+			}
+			excluded[numaID] = "cordoned"
+			klog.V(5).InfoS("excluding cordoned NUMA node", "pod", klog.KObj(pod), "NUMA", numaID)
+			continue
+		}
+		for _, attr := range zone.Attributes {
+			if attr.Name != ZoneAttributeTaints {
+				continue
+			}
+			for _, taint := range parseNUMATaints(attr.Value) {
+				if isNUMATaintTolerated(taint, tolerations) {
+					continue
+				}
+				numaID, err := getID(zone.Name)
+				if err != nil {
+					continue
+				}
+				if excluded == nil {
+					excluded = make(map[int]string)
+				}
+				excluded[numaID] = "untolerated taint " + taint.key
+				klog.V(5).InfoS("excluding tainted NUMA node", "pod", klog.KObj(pod), "NUMA", numaID, "taint", taint.key)
+				break
+			}
+		}
+	}
+	return excluded
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestNUMATaintTolerates(t *testing.T) {
+	tests := []struct {
+		toleration string
+		taint      string
+		expected   bool
+	}{
+		{toleration: "maintenance", taint: "maintenance", expected: true},
+		{toleration: "reserved", taint: "reserved=sre", expected: true},
+		{toleration: "reserved=sre", taint: "reserved=sre", expected: true},
+		{toleration: "reserved=dev", taint: "reserved=sre", expected: false},
+		{toleration: "reserved=sre", taint: "reserved", expected: false},
+		{toleration: "maintenance", taint: "reserved", expected: false},
+	}
+
+	for _, tt := range tests {
+		toleration := parseNUMATaints(tt.toleration)[0]
+		taint := parseNUMATaints(tt.taint)[0]
+		if got := toleration.tolerates(taint); got != tt.expected {
+			t.Errorf("toleration=%q taint=%q got=%v expected=%v", tt.toleration, tt.taint, got, tt.expected)
+		}
+	}
+}
+
+func TestFilterNUMATaints(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+				Attributes: topologyv1alpha2.AttributeList{
+					{Name: ZoneAttributeTaints, Value: "reserved=sre"},
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+				Attributes: topologyv1alpha2.AttributeList{
+					{Name: ZoneAttributeTaints, Value: "maintenance"},
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantStatus  *framework.Status
+	}{
+		{
+			name:       "no tolerations",
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:        "tolerations not matching",
+			annotations: map[string]string{AnnotationNUMATolerations: "reserved=dev"},
+			wantStatus:  framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:        "tolerates the taint key",
+			annotations: map[string]string{AnnotationNUMATolerations: "maintenance"},
+			wantStatus:  nil,
+		},
+		{
+			name:        "tolerates the taint key and value",
+			annotations: map[string]string{AnnotationNUMATolerations: "foo, reserved=sre"},
+			wantStatus:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			})
+			pod.Annotations = tt.annotations
+
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestFilterNUMACordon(t *testing.T) {
+	makeNRT := func(name, unschedulable, numa1CPUs string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+					Attributes: topologyv1alpha2.AttributeList{
+						{Name: ZoneAttributeUnschedulable, Value: unschedulable},
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", numa1CPUs),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		nrt         *topologyv1alpha2.NodeResourceTopology
+		annotations map[string]string
+		wantStatus  *framework.Status
+	}{
+		{
+			name:       "cordoned NUMA node excluded",
+			nrt:        makeNRT("cordoned", "true", "2"),
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:       "cordoned NUMA node excluded, other NUMA node fits",
+			nrt:        makeNRT("cordoned-fit", "true", "4"),
+			wantStatus: nil,
+		},
+		{
+			name:        "cordon can't be tolerated",
+			nrt:         makeNRT("cordoned-tolerated", "true", "2"),
+			annotations: map[string]string{AnnotationNUMATolerations: ZoneAttributeUnschedulable},
+			wantStatus:  framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:       "uncordoned NUMA node",
+			nrt:        makeNRT("uncordoned", "false", "2"),
+			wantStatus: nil,
+		},
+		{
+			name:       "malformed marker ignored",
+			nrt:        makeNRT("malformed", "maybe", "2"),
+			wantStatus: nil,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := fakeClient.Create(context.Background(), tt.nrt.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("3"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			})
+			pod.Annotations = tt.annotations
+
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
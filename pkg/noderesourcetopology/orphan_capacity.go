@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sort"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// orphanCapacity attributes to the NUMA nodes the capacity a node reports as allocatable but its NUMA zones don't,
+// which would otherwise be unusable for the alignment.
+type orphanCapacity struct {
+	mode     apiconfig.OrphanCapacityMode
+	numaNode int
+}
+
+func newOrphanCapacity(conf *apiconfig.OrphanCapacityAttribution) *orphanCapacity {
+	if conf == nil {
+		return nil
+	}
+	return &orphanCapacity{
+		mode:     conf.Mode,
+		numaNode: conf.NUMANode,
+	}
+}
+
+// attribute adds, for each resource the NUMA zones report, the part of the node allocatable exceeding the sum of
+// the allocatable of the NUMA zones to the availability of the NUMA nodes. The orphan capacity is assumed unused.
+func (oc *orphanCapacity) attribute(nodeName string, numaNodes NUMANodeList, zones topologyv1alpha2.ZoneList, nodeAllocatable v1.ResourceList) NUMANodeList {
+	if oc == nil || len(numaNodes) == 0 {
+		return numaNodes
+	}
+	numaAllocatable := numaZonesAllocatable(zones)
+
+	for _, resName := range sortedResourceNames(numaAllocatable) {
+		orphan, ok := nodeAllocatable[resName]
+		if !ok {
+			continue
+		}
+		orphan = orphan.DeepCopy()
+		orphan.Sub(numaAllocatable[resName])
+		if orphan.Sign() <= 0 {
+			continue
+		}
+		klog.V(5).InfoS("attributing orphan capacity", "node", nodeName, "resource", resName, "quantity", orphan.String(), "mode", oc.mode)
+		if oc.mode == apiconfig.OrphanCapacityDistribute {
+			distributeOrphanCapacity(numaNodes, resName, orphan)
+			continue
+		}
+		found := false
+		for _, numaNode := range numaNodes {
+			if numaNode.NUMAID != oc.numaNode {
+				continue
+			}
+			addToNUMANode(numaNode, resName, orphan)
+			found = true
+		}
+		if !found {
+			klog.V(2).InfoS("cannot attribute orphan capacity, NUMA node not found", "node", nodeName, "resource", resName, "NUMA", oc.numaNode)
+		}
+	}
+	return numaNodes
+}
+
+// numaZonesAllocatable returns the sum of the allocatable resources of the NUMA zones.
+func numaZonesAllocatable(zones topologyv1alpha2.ZoneList) v1.ResourceList {
+	numaAllocatable := make(v1.ResourceList)
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		for _, resInfo := range zone.Resources {
+			resName := v1.ResourceName(resInfo.Name)
+			total := numaAllocatable[resName]
+			// not all the producers report the allocatable of the NUMA zones
+			if resInfo.Allocatable.IsZero() {
+				total.Add(resInfo.Capacity)
+			} else {
+				total.Add(resInfo.Allocatable)
+			}
+			numaAllocatable[resName] = total
+		}
+	}
+	return numaAllocatable
+}
+
+// distributeOrphanCapacity spreads the orphan capacity of the resource evenly across the NUMA nodes, in whole units,
+// whole CPUs for the CPU, the lowest NUMA IDs getting the remainder.
+func distributeOrphanCapacity(numaNodes NUMANodeList, resName v1.ResourceName, orphan resource.Quantity) {
+	units := orphan.Value()
+	if resName == v1.ResourceCPU {
+		units = orphan.MilliValue() / 1000
+	}
+	numaIDs := make([]int, 0, len(numaNodes))
+	numaNodeByID := make(map[int]NUMANode, len(numaNodes))
+	for _, numaNode := range numaNodes {
+		numaIDs = append(numaIDs, numaNode.NUMAID)
+		numaNodeByID[numaNode.NUMAID] = numaNode
+	}
+	sort.Ints(numaIDs)
+
+	count := int64(len(numaIDs))
+	for idx, numaID := range numaIDs {
+		share := units / count
+		if int64(idx) < units%count {
+			share++
+		}
+		if share == 0 {
+			continue
+		}
+		quantity := *resource.NewQuantity(share, orphan.Format)
+		if resName == v1.ResourceCPU {
+			quantity = *resource.NewMilliQuantity(share*1000, orphan.Format)
+		}
+		addToNUMANode(numaNodeByID[numaID], resName, quantity)
+	}
+}
+
+func addToNUMANode(numaNode NUMANode, resName v1.ResourceName, quantity resource.Quantity) {
+	available := numaNode.Resources[resName]
+	available.Add(quantity)
+	numaNode.Resources[resName] = available
+}
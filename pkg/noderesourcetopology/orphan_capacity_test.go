@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterOrphanCapacity(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the producer reports 4 of the 12 cpus of the node in no NUMA zone
+	node := makeNodeFromNodeResourceTopology(nrt)
+	node.Status.Allocatable[v1.ResourceCPU] = resource.MustParse("12")
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("3"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+	})
+
+	cannotAlign := framework.NewStatus(framework.Unschedulable, "cannot align pod")
+	tests := []struct {
+		name        string
+		attribution *apiconfig.OrphanCapacityAttribution
+		wantStatus  *framework.Status
+	}{
+		{
+			name:       "orphan capacity not attributed",
+			wantStatus: cannotAlign,
+		},
+		{
+			name: "orphan capacity attributed to a NUMA node",
+			attribution: &apiconfig.OrphanCapacityAttribution{
+				Mode:     apiconfig.OrphanCapacityNUMANode,
+				NUMANode: 1,
+			},
+		},
+		{
+			name: "orphan capacity attributed to a missing NUMA node",
+			attribution: &apiconfig.OrphanCapacityAttribution{
+				Mode:     apiconfig.OrphanCapacityNUMANode,
+				NUMANode: 3,
+			},
+			wantStatus: cannotAlign,
+		},
+		{
+			name: "orphan capacity distributed",
+			attribution: &apiconfig.OrphanCapacityAttribution{
+				Mode: apiconfig.OrphanCapacityDistribute,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:       nrtcache.NewPassthrough(fakeClient),
+				orphanCapacity: newOrphanCapacity(tt.attribution),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDistributeOrphanCapacity(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "1"),
+				MakeTopologyResInfo(nicResourceName, "1", "1"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "1"),
+				MakeTopologyResInfo(nicResourceName, "1", "0"),
+			},
+		},
+	}
+	nodeAllocatable := v1.ResourceList{
+		v1.ResourceCPU:  resource.MustParse("9500m"),
+		nicResourceName: resource.MustParse("5"),
+	}
+
+	oc := newOrphanCapacity(&apiconfig.OrphanCapacityAttribution{Mode: apiconfig.OrphanCapacityDistribute})
+	numaNodes := oc.attribute("node1", createNUMANodeList(zones), zones, nodeAllocatable)
+
+	// the 1.5 orphan cpus make a whole cpu, the 3 orphan NICs are split 2+1
+	expected := map[int]v1.ResourceList{
+		0: {v1.ResourceCPU: resource.MustParse("2"), nicResourceName: resource.MustParse("3")},
+		1: {v1.ResourceCPU: resource.MustParse("1"), nicResourceName: resource.MustParse("1")},
+	}
+	for _, numaNode := range numaNodes {
+		for resName, want := range expected[numaNode.NUMAID] {
+			got := numaNode.Resources[resName]
+			if got.Cmp(want) != 0 {
+				t.Errorf("NUMA %d resource %s: got=%s expected=%s", numaNode.NUMAID, resName, got.String(), want.String())
+			}
+		}
+	}
+}
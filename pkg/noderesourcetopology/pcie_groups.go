@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+)
+
+// ZoneAttributePCIeGroupsPrefix prefixes the name of the zone attributes holding the available devices of a resource
+// per PCIe group of a NUMA node, e.g. "pcieGroups.vendor.com/gpu". The value is a comma-separated list of `group=count`
+// items, e.g. "switch0=2,switch1=1".
+const ZoneAttributePCIeGroupsPrefix = "pcieGroups."
+
+// pcieGroups maps the NUMA node IDs to the largest amount of devices available within one PCIe group, per resource.
+type pcieGroups map[int]map[v1.ResourceName]int64
+
+func newPCIeGroups(zones topologyv1alpha2.ZoneList) pcieGroups {
+	var groups pcieGroups
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		for _, attr := range zone.Attributes {
+			resName, ok := strings.CutPrefix(attr.Name, ZoneAttributePCIeGroupsPrefix)
+			if !ok || resName == "" {
+				continue
+			}
+			numaID, err := getID(zone.Name)
+			if err != nil {
+				klog.V(5).InfoS("cannot get the NUMA ID, ignoring PCIe groups", "zone", zone.Name, "err", err)
+				continue
+			}
+			largest, ok := largestPCIeGroup(attr.Value)
+			if !ok {
+				klog.V(5).InfoS("malformed PCIe groups, ignoring", "zone", zone.Name, "resource", resName, "value", attr.Value)
+				continue
+			}
+			if groups == nil {
+				groups = make(pcieGroups)
+			}
+			if groups[numaID] == nil {
+				groups[numaID] = make(map[v1.ResourceName]int64)
+			}
+			groups[numaID][v1.ResourceName(resName)] = largest
+		}
+	}
+	return groups
+}
+
+func largestPCIeGroup(val string) (int64, bool) {
+	largest := int64(0)
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		_, count, found := strings.Cut(item, "=")
+		if !found {
+			return 0, false
+		}
+		value, err := strconv.ParseInt(strings.TrimSpace(count), 10, 64)
+		if err != nil || value < 0 {
+			return 0, false
+		}
+		if value > largest {
+			largest = value
+		}
+	}
+	return largest, true
+}
+
+// fits returns true if the quantity of the resource fits in a single PCIe group of the NUMA node.
+// Requests of a single device, and resources without PCIe groups data, always fit.
+func (pg pcieGroups) fits(numaID int, resName v1.ResourceName, quantity resource.Quantity) bool {
+	if quantity.Value() <= 1 {
+		return true
+	}
+	largest, ok := pg[numaID][resName]
+	if !ok {
+		return true
+	}
+	return quantity.Value() <= largest
+}
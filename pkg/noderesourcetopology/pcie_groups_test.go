@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+const vendorGPU = "vendor.com/gpu"
+
+func TestNewPCIeGroups(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: ZoneAttributePCIeGroupsPrefix + vendorGPU, Value: "switch0=2, switch1=3"},
+				{Name: ZoneAttributePCIeGroupsPrefix + nicResourceName, Value: "switch0"},
+				{Name: ZoneAttributeTaints, Value: "maintenance"},
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: ZoneAttributePCIeGroupsPrefix + vendorGPU, Value: "switch0=-1"},
+			},
+		},
+		{
+			Name: "socket-0",
+			Type: "Socket",
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: ZoneAttributePCIeGroupsPrefix + vendorGPU, Value: "switch0=8"},
+			},
+		},
+	}
+	expected := pcieGroups{
+		0: {vendorGPU: 3},
+	}
+	if got := newPCIeGroups(zones); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got=%v expected=%v", got, expected)
+	}
+}
+
+func TestFilterPCIeGroups(t *testing.T) {
+	gpuZone := func(name, available, groups string) topologyv1alpha2.Zone {
+		zone := topologyv1alpha2.Zone{
+			Name: name,
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "8", "8"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				MakeTopologyResInfo(vendorGPU, "4", available),
+			},
+		}
+		if groups != "" {
+			zone.Attributes = topologyv1alpha2.AttributeList{
+				{Name: ZoneAttributePCIeGroupsPrefix + vendorGPU, Value: groups},
+			}
+		}
+		return zone
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "grouped"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				gpuZone("node-0", "4", "switch0=2,switch1=2"),
+				gpuZone("node-1", "2", ""),
+			},
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "ungrouped"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				gpuZone("node-0", "4", ""),
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		node       *topologyv1alpha2.NodeResourceTopology
+		gpus       string
+		alignment  bool
+		wantStatus *framework.Status
+	}{
+		{
+			name:      "fits in a PCIe group",
+			node:      nrts[0],
+			gpus:      "2",
+			alignment: true,
+		},
+		{
+			name: "disabled, spans PCIe groups",
+			node: nrts[0],
+			gpus: "3",
+		},
+		{
+			name:       "cannot fit in a PCIe group",
+			node:       nrts[0],
+			gpus:       "3",
+			alignment:  true,
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:      "no PCIe groups data, NUMA alignment only",
+			node:      nrts[1],
+			gpus:      "3",
+			alignment: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				vendorGPU:         resource.MustParse(tt.gpus),
+			})
+
+			tm := TopologyMatch{
+				nrtCache:           nrtcache.NewPassthrough(fakeClient),
+				pcieGroupAlignment: tt.alignment,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.node))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// PlacementRecorder records the NUMA placements of the pods, for example to push them to a billing or chargeback pipeline.
+type PlacementRecorder interface {
+	// RecordPlacement is called in Reserve with the NUMA node each container of the pod, identified by name,
+	// is expected to run on. Errors are logged and don't affect the scheduling; since it is called in the
+	// scheduling cycle, implementations should hand off any slow work instead of blocking.
+	RecordPlacement(ctx context.Context, pod *v1.Pod, nodeName string, containerNUMANodes map[string]int) error
+}
+
+// WithPlacementRecorder makes the plugin report the NUMA placements of the pods to the given recorder.
+// By default the placements are not recorded.
+func WithPlacementRecorder(recorder PlacementRecorder) Option {
+	return func(tm *TopologyMatch) {
+		tm.placementRecorder = recorder
+	}
+}
+
+type placementState struct {
+	containerNUMANodes map[string]int
+	topologyManager    TopologyManagerConfig
+}
+
+func (s *placementState) Clone() framework.StateData {
+	containerNUMANodes := make(map[string]int, len(s.containerNUMANodes))
+	for name, numaID := range s.containerNUMANodes {
+		containerNUMANodes[name] = numaID
+	}
+	return &placementState{
+		containerNUMANodes: containerNUMANodes,
+		topologyManager:    s.topologyManager,
+	}
+}
+
+func placementStateKey(nodeName string) framework.StateKey {
+	return framework.StateKey(Name + "/placement/" + nodeName)
+}
+
+// storePlacement stores in the CycleState the NUMA node of each container of the pod, as chosen by the filter,
+// so it can be recorded once the node is reserved.
+func (tm *TopologyMatch) storePlacement(cycleState *framework.CycleState, pod *v1.Pod, info *filterInfo) {
+	if (tm.placementRecorder == nil && !tm.chosenNUMAMetric && tm.numaOccupancy == nil && tm.decisionWebhook == nil) || len(info.chosenNUMANodes) == 0 {
+		return
+	}
+	choices, ok := containerChoices(pod, info)
+	if !ok {
+		return
+	}
+	containerNUMANodes := make(map[string]int, len(choices))
+	for name, idx := range choices {
+		containerNUMANodes[name] = info.chosenNUMANodes[idx]
+	}
+	cycleState.Write(placementStateKey(info.nodeName), &placementState{
+		containerNUMANodes: containerNUMANodes,
+		topologyManager:    info.topologyManager,
+	})
+}
+
+// containerChoices maps each container of the pod, identified by name, to the index of its choice in the chosenNUMANodes.
+// Returns false if the handler made fewer choices than expected.
+func containerChoices(pod *v1.Pod, info *filterInfo) (map[string]int, bool) {
+	choices := make(map[string]int, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	idx := 0
+	// the container handler chooses a NUMA node for each init container first, then for each app container
+	for _, containers := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			if info.topologyManager.Scope == kubeletconfig.PodTopologyManagerScope {
+				choices[container.Name] = 0
+				continue
+			}
+			if idx >= len(info.chosenNUMANodes) {
+				// should never happen
+				klog.V(3).InfoS("missing NUMA placement", "pod", klog.KObj(pod), "node", info.nodeName, "container", container.Name)
+				return nil, false
+			}
+			choices[container.Name] = idx
+			idx++
+		}
+	}
+	return choices, true
+}
+
+// recordPlacement reports to the recorder, if any, the NUMA placement of the pod on the reserved node.
+// The recorder failures are only logged, because they must not affect the scheduling.
+func (tm *TopologyMatch) recordPlacement(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) {
+	if tm.placementRecorder == nil {
+		return
+	}
+	containerNUMANodes, ok := storedPlacement(cycleState, nodeName)
+	if !ok {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			klog.ErrorS(nil, "placement recorder panicked", "pod", klog.KObj(pod), "node", nodeName, "panic", r)
+		}
+	}()
+	if err := tm.placementRecorder.RecordPlacement(ctx, pod, nodeName, containerNUMANodes); err != nil {
+		klog.ErrorS(err, "cannot record the NUMA placement", "pod", klog.KObj(pod), "node", nodeName)
+	}
+}
+
+// storedPlacement returns the NUMA node of each container of the pod on the node, as stored by the filter.
+// Returns false if the filter made no NUMA alignment decision for the node.
+func storedPlacement(cycleState *framework.CycleState, nodeName string) (map[string]int, bool) {
+	state, ok := storedPlacementState(cycleState, nodeName)
+	if !ok {
+		return nil, false
+	}
+	return state.containerNUMANodes, true
+}
+
+// storedPlacementState returns the placement of the pod on the node, along with the topology manager configuration
+// the filter aligned it for. Returns false if the filter made no NUMA alignment decision for the node.
+func storedPlacementState(cycleState *framework.CycleState, nodeName string) (*placementState, bool) {
+	data, err := cycleState.Read(placementStateKey(nodeName))
+	if err != nil {
+		return nil, false
+	}
+	state, ok := data.(*placementState)
+	return state, ok
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+type recordedPlacement struct {
+	podName            string
+	nodeName           string
+	containerNUMANodes map[string]int
+}
+
+type fakePlacementRecorder struct {
+	placements []recordedPlacement
+	err        error
+}
+
+func (r *fakePlacementRecorder) RecordPlacement(ctx context.Context, pod *v1.Pod, nodeName string, containerNUMANodes map[string]int) error {
+	r.placements = append(r.placements, recordedPlacement{
+		podName:            pod.Name,
+		nodeName:           nodeName,
+		containerNUMANodes: containerNUMANodes,
+	})
+	return r.err
+}
+
+func TestPlacementRecorder(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "2"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "pod-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            zones,
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "container-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones:            zones,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cntReq := []map[string]string{
+		{cpu: "2", memory: "1Gi"},
+		{cpu: "2", memory: "1Gi"},
+	}
+
+	tests := []struct {
+		name        string
+		nrt         *topologyv1alpha2.NodeResourceTopology
+		recorderErr error
+		expected    map[string]int
+	}{
+		{
+			name:     "pod scope",
+			nrt:      nrts[0],
+			expected: map[string]int{"cnt-1": 1, "cnt-2": 1},
+		},
+		{
+			name:     "container scope",
+			nrt:      nrts[1],
+			expected: map[string]int{"cnt-1": 0, "cnt-2": 1},
+		},
+		{
+			name:        "recorder failure",
+			nrt:         nrts[0],
+			recorderErr: errors.New("billing pipeline unavailable"),
+			expected:    map[string]int{"cnt-1": 1, "cnt-2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &fakePlacementRecorder{err: tt.recorderErr}
+			tm := &TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			WithPlacementRecorder(recorder)(tm)
+
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected filter status: %v", status)
+			}
+			if len(recorder.placements) != 0 {
+				t.Fatalf("placement recorded before reserve: %v", recorder.placements)
+			}
+
+			if status := tm.Reserve(context.Background(), state, pod, tt.nrt.Name); !status.IsSuccess() {
+				t.Fatalf("unexpected reserve status: %v", status)
+			}
+			expected := []recordedPlacement{
+				{podName: pod.Name, nodeName: tt.nrt.Name, containerNUMANodes: tt.expected},
+			}
+			if !reflect.DeepEqual(recorder.placements, expected) {
+				t.Errorf("placements=%v expected=%v", recorder.placements, expected)
+			}
+		})
+	}
+}
+
+func TestPlacementRecorderNoAlignment(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "none"},
+		TopologyPolicies: []string{string(topologyv1alpha2.None)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &fakePlacementRecorder{}
+	tm := &TopologyMatch{
+		nrtCache:          nrtcache.NewPassthrough(fakeClient),
+		placementRecorder: recorder,
+	}
+	pod := makePod("testpod", withMultiContainers(parseContainerRes([]map[string]string{{cpu: "2", memory: "1Gi"}})))
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+	state := framework.NewCycleState()
+	if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+		t.Fatalf("unexpected filter status: %v", status)
+	}
+	if status := tm.Reserve(context.Background(), state, pod, nrt.Name); !status.IsSuccess() {
+		t.Fatalf("unexpected reserve status: %v", status)
+	}
+	if len(recorder.placements) != 0 {
+		t.Errorf("unexpected placements without alignment decision: %v", recorder.placements)
+	}
+}
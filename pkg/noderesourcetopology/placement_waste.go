@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+var (
+	strandedResourcesTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "scheduler_plugins",
+			Name:           "noderesourcetopology_stranded_resources_total",
+			Help:           "Amount of NUMA-local resources stranded by the pods placements, in cores for cpu and in bytes for memory and hugepages.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"resource"},
+	)
+
+	registerMetricsOnce sync.Once
+)
+
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(strandedResourcesTotal)
+		legacyregistry.MustRegister(alignableReferencePodsTotal)
+		legacyregistry.MustRegister(feasibleReferencePods)
+		legacyregistry.MustRegister(allocatableMismatchTotal)
+		legacyregistry.MustRegister(chosenNUMATotal)
+	})
+}
+
+type placementWasteState struct {
+	stranded v1.ResourceList
+}
+
+func (s *placementWasteState) Clone() framework.StateData {
+	return &placementWasteState{
+		stranded: s.stranded.DeepCopy(),
+	}
+}
+
+func placementWasteStateKey(nodeName string) framework.StateKey {
+	return framework.StateKey(Name + "/placementWaste/" + nodeName)
+}
+
+// PlacementWaste returns the NUMA-local resources the placement of the pod on the node would strand,
+// as computed by the filter of the current scheduling cycle. Returns false if not computed.
+func PlacementWaste(cycleState *framework.CycleState, nodeName string) (v1.ResourceList, bool) {
+	if cycleState == nil {
+		return nil, false
+	}
+	data, err := cycleState.Read(placementWasteStateKey(nodeName))
+	if err != nil {
+		return nil, false
+	}
+	state, ok := data.(*placementWasteState)
+	if !ok {
+		return nil, false
+	}
+	return state.stranded, true
+}
+
+// recordPlacementWaste computes the resources stranded by the placement of the pod on the NUMA nodes chosen by the filter,
+// and stores them in the CycleState. Only guaranteed pods consume the NUMA-local resources exclusively.
+func (tm *TopologyMatch) recordPlacementWaste(cycleState *framework.CycleState, pod *v1.Pod, info *filterInfo) {
+	if !tm.placementWaste || info.qos != v1.PodQOSGuaranteed || len(info.chosenNUMANodes) == 0 {
+		return
+	}
+	// at container scope, the handler already subtracted the requests of the containers
+	if info.topologyManager.Scope == kubeletconfig.PodTopologyManagerScope {
+		info.consume(info.chosenNUMANodes[0], info.podAlignmentResources(pod))
+	}
+	stranded := strandedResources(info.numaNodes, info.chosenNUMANodes)
+	klog.V(6).InfoS("placement waste", "pod", klog.KObj(pod), "node", info.nodeName, "stranded", stranded)
+	cycleState.Write(placementWasteStateKey(info.nodeName), &placementWasteState{stranded: stranded})
+}
+
+// strandedResources returns the resources left on the given NUMA nodes which no guaranteed pod can use anymore,
+// because either less than a whole cpu or no memory is left on the same NUMA node.
+func strandedResources(numaNodes NUMANodeList, numaIDs []int) v1.ResourceList {
+	stranded := v1.ResourceList{}
+	seen := make(map[int]bool)
+	for _, numaID := range numaIDs {
+		if seen[numaID] {
+			continue
+		}
+		seen[numaID] = true
+		for _, numaNode := range numaNodes {
+			if numaNode.NUMAID != numaID {
+				continue
+			}
+			cpuLeft := numaNode.Resources[v1.ResourceCPU]
+			memoryLeft := numaNode.Resources[v1.ResourceMemory]
+			if cpuLeft.MilliValue() >= 1000 && memoryLeft.Sign() > 0 {
+				continue
+			}
+			for resName, quantity := range numaNode.Resources {
+				if !isCapacityIgnoredForQoS(resName) || quantity.Sign() <= 0 {
+					continue
+				}
+				total := stranded[resName]
+				total.Add(quantity)
+				stranded[resName] = total
+			}
+		}
+	}
+	return stranded
+}
+
+func observePlacementWaste(stranded v1.ResourceList) {
+	for resName, quantity := range stranded {
+		strandedResourcesTotal.WithLabelValues(string(resName)).Add(quantity.AsApproximateFloat64())
+	}
+}
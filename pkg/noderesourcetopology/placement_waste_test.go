@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestPlacementWaste(t *testing.T) {
+	registerMetrics()
+
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+	}
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "pod-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            zones,
+		},
+		{
+			ObjectMeta:       metav1.ObjectMeta{Name: "container-scope"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones:            zones,
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		nrt      *topologyv1alpha2.NodeResourceTopology
+		cntReq   []map[string]string
+		expected v1.ResourceList
+	}{
+		{
+			name: "pod scope, nothing stranded",
+			nrt:  nrts[0],
+			cntReq: []map[string]string{
+				{cpu: "2", memory: "2Gi"},
+			},
+			expected: v1.ResourceList{},
+		},
+		{
+			name: "pod scope, all the cpus taken strand the memory",
+			nrt:  nrts[0],
+			cntReq: []map[string]string{
+				{cpu: "2", memory: "1Gi"},
+				{cpu: "2", memory: "1Gi"},
+			},
+			expected: v1.ResourceList{
+				v1.ResourceMemory: resource.MustParse("6Gi"),
+			},
+		},
+		{
+			name: "container scope, all the memory taken strands the cpus",
+			nrt:  nrts[1],
+			cntReq: []map[string]string{
+				{cpu: "1", memory: "8Gi"},
+				{cpu: "1", memory: "1Gi"},
+			},
+			expected: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("3"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:       nrtcache.NewPassthrough(fakeClient),
+				placementWaste: true,
+			}
+			pod := makePod("testpod", withMultiContainers(parseContainerRes(tt.cntReq)))
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			state := framework.NewCycleState()
+			if status := tm.Filter(context.Background(), state, pod, nodeInfo); status != nil {
+				t.Fatalf("unexpected status: %v", status)
+			}
+
+			stranded, ok := PlacementWaste(state, tt.nrt.Name)
+			if !ok {
+				t.Fatalf("placement waste not computed")
+			}
+			if len(stranded) != len(tt.expected) {
+				t.Fatalf("stranded=%v expected=%v", stranded, tt.expected)
+			}
+			for resName, quantity := range tt.expected {
+				if got, ok := stranded[resName]; !ok || got.Cmp(quantity) != 0 {
+					t.Errorf("resource %q: stranded=%v expected=%v", resName, got.String(), quantity.String())
+				}
+			}
+
+			before := make(map[v1.ResourceName]float64)
+			for resName := range tt.expected {
+				before[resName], _ = testutil.GetCounterMetricValue(strandedResourcesTotal.WithLabelValues(string(resName)))
+			}
+			tm.Reserve(context.Background(), state, pod, tt.nrt.Name)
+			for resName, quantity := range tt.expected {
+				after, err := testutil.GetCounterMetricValue(strandedResourcesTotal.WithLabelValues(string(resName)))
+				if err != nil {
+					t.Fatalf("cannot read the metric: %v", err)
+				}
+				if delta := after - before[resName]; delta != quantity.AsApproximateFloat64() {
+					t.Errorf("resource %q: metric increased by %v expected %v", resName, delta, quantity.AsApproximateFloat64())
+				}
+			}
+		})
+	}
+}
@@ -17,15 +17,20 @@ limitations under the License.
 package noderesourcetopology
 
 import (
+	"context"
 	"fmt"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	resourcelisters "k8s.io/client-go/listers/resource/v1alpha2"
 	"k8s.io/klog/v2"
+	kubeletconfig "k8s.io/kubernetes/pkg/kubelet/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
 	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
@@ -33,6 +38,7 @@ import (
 
 	topologyapi "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology"
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -93,22 +99,105 @@ func subtractFromNUMAs(resources v1.ResourceList, numaNodes NUMANodeList, nodes
 	}
 }
 
-type filterFn func(pod *v1.Pod, zones topologyv1alpha2.ZoneList, nodeInfo *framework.NodeInfo) *framework.Status
+type filterFn func(pod *v1.Pod, info *filterInfo) *framework.Status
 type scoringFn func(*v1.Pod, topologyv1alpha2.ZoneList) (int64, *framework.Status)
 
 // TopologyMatch plugin which run simplified version of TopologyManager's admit handler
 type TopologyMatch struct {
-	resourceToWeightMap resourceToWeightMap
-	nrtCache            nrtcache.Interface
-	scoreStrategyFunc   scoreStrategyFn
-	scoreStrategyType   apiconfig.ScoringStrategyType
+	handle                   framework.Handle
+	resourceToWeightMap      resourceToWeightMap
+	nrtCache                 nrtcache.Interface
+	scoreStrategyFunc        scoreStrategyFn
+	normalizedStrategyFunc   normalizedScoreStrategyFn
+	scoreStrategyType        apiconfig.ScoringStrategyType
+	profileLister            WorkloadProfileLister
+	resourceRounding         resourceRounding
+	resourceAliases          resourceAliases
+	orphanCapacity           *orphanCapacity
+	memoryAlignAgainstLimits bool
+	containerCPUExclusivity  bool
+	deviceAvoidanceResources []v1.ResourceName
+	pcieGroupAlignment       bool
+	quarantine               *nodeQuarantine
+	nodeHeadroomWeight       int64
+	stabilityWeight          int64
+	sharedDevices            sharedDevices
+	spanningResources        spanningResources
+	placementWaste           bool
+	policyResolver           PolicyResolver
+	strictTopologyPolicies   bool
+	deviceCPUBalance         *apiconfig.DeviceCPUBalance
+	placementRecorder        PlacementRecorder
+	chosenNUMAMetric         bool
+	resourceNameHints        bool
+	containerScopeSameNUMA   bool
+	initContainersSameNUMA   bool
+	stickyNUMAWeight         int64
+	numaSpreadWeight         int64
+	ownerNUMALocalityWeight  int64
+	deviceHintConflictWeight int64
+	socketFreenessWeight     int64
+	numaPowerHintAttribute   string
+	restrictedAsSingleNUMA   bool
+	excludedResources        []v1.ResourceName
+	requiredAlignment        []v1.ResourceName
+	sidecarOverhead          v1.ResourceList
+	fractionalCPUShared      bool
+	staticCPUManager         bool
+	burstFactorPercent       int64
+	maxCandidateNUMASets     int64
+	exemptNamespaces         sets.Set[string]
+	numaAffinityMemory       *numaAffinityMemory
+	allocatableConsistency   *allocatableConsistency
+	downgrades               *bestEffortDowngrades
+	invalidTopologies        *invalidTopologies
+	defaultPolicyWhenMissing *apiconfig.TopologyManagerOverlay
+	logTopologyAge           bool
+	bestEffortNodeDevices    bool
+	failFastNoEnforcing      bool
+	gatedNUMAPrefWeight      int64
+	numaEphemeralStorage     bool
+	qosResourcePolicy        qosResourcePolicy
+	sameSocketWeight         int64
+	enabledPolicies          sets.Set[string]
+	guaranteedOffNonePolicy  bool
+	numaOccupancy            *numaOccupancy
+	decisionWebhook          *decisionWebhook
+	degradedNUMAWeight       int64
+	degradedNUMAThreshold    int64
+	decisionLog              *decisionLog
+	configChanges            *configChanges
+	claimLister              resourcelisters.ResourceClaimLister
+	tracer                   trace.Tracer
 }
 
+// Option customizes a TopologyMatch plugin instance at construction time.
+type Option func(tm *TopologyMatch)
+
+// WithWorkloadProfileLister makes the plugin read the NUMA preferences of the pods from the
+// workload profiles they reference, instead of only from their annotations.
+func WithWorkloadProfileLister(lister WorkloadProfileLister) Option {
+	return func(tm *TopologyMatch) {
+		tm.profileLister = lister
+	}
+}
+
+// WithPolicyResolver makes the plugin obtain the topology manager configuration of the nodes
+// from the given resolver, instead of from the NRT objects.
+func WithPolicyResolver(resolver PolicyResolver) Option {
+	return func(tm *TopologyMatch) {
+		tm.policyResolver = resolver
+	}
+}
+
+var _ framework.PreFilterPlugin = &TopologyMatch{}
 var _ framework.FilterPlugin = &TopologyMatch{}
 var _ framework.ReservePlugin = &TopologyMatch{}
 var _ framework.ScorePlugin = &TopologyMatch{}
 var _ framework.EnqueueExtensions = &TopologyMatch{}
+var _ framework.PreBindPlugin = &TopologyMatch{}
 var _ framework.PostBindPlugin = &TopologyMatch{}
+var _ framework.PostFilterPlugin = &TopologyMatch{}
 
 // Name returns name of the plugin. It is used in logs, etc.
 func (tm *TopologyMatch) Name() string {
@@ -117,6 +206,26 @@ func (tm *TopologyMatch) Name() string {
 
 // New initializes a new plugin and returns it.
 func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	tm, err := newTopologyMatch(args, handle)
+	if err != nil {
+		return nil, err
+	}
+	return tm, nil
+}
+
+// NewWithOptions returns a factory which creates the plugin like New does, applying
+// the given options. Meant to be used when registering the plugin in out-of-tree builds.
+func NewWithOptions(opts ...Option) frameworkruntime.PluginFactory {
+	return func(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+		tm, err := newTopologyMatch(args, handle, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return tm, nil
+	}
+}
+
+func newTopologyMatch(args runtime.Object, handle framework.Handle, opts ...Option) (*TopologyMatch, error) {
 	klog.V(5).InfoS("Creating new TopologyMatch plugin")
 	tcfg, ok := args.(*apiconfig.NodeResourceTopologyMatchArgs)
 	if !ok {
@@ -149,15 +258,149 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	}
 
 	topologyMatch := &TopologyMatch{
-		resourceToWeightMap: resToWeightMap,
-		nrtCache:            nrtCache,
-		scoreStrategyFunc:   strategy,
-		scoreStrategyType:   tcfg.ScoringStrategy.Type,
+		handle:                   handle,
+		resourceToWeightMap:      resToWeightMap,
+		nrtCache:                 nrtCache,
+		scoreStrategyFunc:        strategy,
+		normalizedStrategyFunc:   normalizedScoringStrategyFunction(tcfg.ScoringStrategy.Type, tcfg.CapacityNormalizedScoring),
+		scoreStrategyType:        tcfg.ScoringStrategy.Type,
+		resourceRounding:         newResourceRounding(tcfg.ResourceRounding),
+		resourceAliases:          newResourceAliases(tcfg.ResourceAliases),
+		orphanCapacity:           newOrphanCapacity(tcfg.OrphanCapacityAttribution),
+		memoryAlignAgainstLimits: tcfg.MemoryAlignAgainstLimits,
+		containerCPUExclusivity:  tcfg.ContainerCPUExclusivity,
+		deviceAvoidanceResources: resourceNames(tcfg.DeviceAvoidanceResources),
+		pcieGroupAlignment:       tcfg.PCIeGroupAlignment,
+		quarantine:               newNodeQuarantine(tcfg.Quarantine),
+		nodeHeadroomWeight:       tcfg.NodeHeadroomWeight,
+		stabilityWeight:          tcfg.StabilityWeight,
+		sharedDevices:            newSharedDevices(tcfg.DeviceAllocation),
+		spanningResources:        newSpanningResources(tcfg.NUMASpan),
+		placementWaste:           tcfg.PlacementWaste,
+		chosenNUMAMetric:         tcfg.ChosenNUMAMetric,
+		policyResolver:           NRTPolicyResolver{},
+		strictTopologyPolicies:   tcfg.StrictTopologyPolicies,
+		deviceCPUBalance:         tcfg.DeviceCPUBalance,
+		resourceNameHints:        tcfg.ResourceNameHints,
+		containerScopeSameNUMA:   tcfg.ContainerScopeForceSameNUMA,
+		initContainersSameNUMA:   tcfg.ContainerScopeInitSameNUMA,
+		stickyNUMAWeight:         tcfg.StickyNUMAWeight,
+		numaSpreadWeight:         tcfg.NUMASpreadWeight,
+		ownerNUMALocalityWeight:  tcfg.OwnerNUMALocalityWeight,
+		deviceHintConflictWeight: tcfg.DeviceHintConflictWeight,
+		socketFreenessWeight:     tcfg.SocketFreenessWeight,
+		numaPowerHintAttribute:   tcfg.NUMAPowerHintAttribute,
+		restrictedAsSingleNUMA:   tcfg.RestrictedAsSingleNUMA,
+		excludedResources:        resourceNames(tcfg.ExcludedResources),
+		requiredAlignment:        resourceNames(tcfg.RequiredAlignmentResources),
+		sidecarOverhead:          tcfg.SidecarOverheadEstimate,
+		fractionalCPUShared:      tcfg.FractionalCPUShared,
+		staticCPUManager:         tcfg.StaticCPUManagerPolicy,
+		burstFactorPercent:       tcfg.BurstFactorPercent,
+		maxCandidateNUMASets:     tcfg.MaxCandidateNUMASets,
+		exemptNamespaces:         sets.New(tcfg.ExemptNamespaces...),
+		numaAffinityMemory:       newNUMAAffinityMemory(tcfg.NUMAAffinityMemorySeconds),
+		allocatableConsistency:   newAllocatableConsistency(tcfg.AllocatableMismatchPercent),
+		downgrades:               newBestEffortDowngrades(),
+		invalidTopologies:        newInvalidTopologies(tcfg.PersistentInvalidTopologyThreshold),
+		defaultPolicyWhenMissing: tcfg.DefaultPolicyWhenMissing,
+		logTopologyAge:           tcfg.LogTopologyAge,
+		bestEffortNodeDevices:    tcfg.BestEffortNodeLevelDevices,
+		failFastNoEnforcing:      tcfg.FailFastWithoutEnforcingNodes,
+		gatedNUMAPrefWeight:      tcfg.GatedNUMAPreferenceWeight,
+		numaEphemeralStorage:     tcfg.NUMALocalEphemeralStorage,
+		qosResourcePolicy:        newQoSResourcePolicy(tcfg.QoSResourcePolicy),
+		sameSocketWeight:         tcfg.SameSocketResourcesWeight,
+		degradedNUMAWeight:       tcfg.DegradedNUMAWeight,
+		degradedNUMAThreshold:    tcfg.DegradedNUMAThreshold,
+		enabledPolicies:          sets.New(tcfg.EnabledPolicies...),
+		guaranteedOffNonePolicy:  tcfg.GuaranteedPodsRejectedOnNonePolicy,
+		configChanges:            newConfigChanges(),
+	}
+	registerFilterMetrics()
+	if tcfg.PlacementWaste || tcfg.AllocatableMismatchPercent > 0 || tcfg.ChosenNUMAMetric {
+		registerMetrics()
+	}
+	if overlay := tcfg.TopologyManagerOverlay; overlay != nil {
+		klog.InfoS("Assuming the same topology manager configuration on all the nodes", "policy", overlay.Policy, "scope", overlay.Scope)
+		topologyMatch.policyResolver = OverlayPolicyResolver{
+			Config: TopologyManagerConfig{
+				Policy: overlay.Policy,
+				Scope:  overlay.Scope,
+			},
+		}
+	}
+	for _, opt := range opts {
+		opt(topologyMatch)
 	}
+	// the options must be applied first, the report runs concurrently with the plugin
+	if tcfg.SchedulabilityReport != nil {
+		startSchedulabilityReport(topologyMatch, tcfg.SchedulabilityReport, handle)
+	}
+	if tcfg.DecisionLog != nil {
+		if err := startDecisionLog(topologyMatch, tcfg.DecisionLog, profileName(handle)); err != nil {
+			return nil, err
+		}
+	}
+	if tcfg.NUMAOccupancyFromPods {
+		topologyMatch.numaOccupancy = newNUMAOccupancy(handle.ClientSet())
+		topologyMatch.numaOccupancy.forgetDeletedPods(handle.SharedInformerFactory().Core().V1().Pods().Informer())
+	}
+	if tcfg.DecisionWebhook != nil {
+		topologyMatch.decisionWebhook = newDecisionWebhook(tcfg.DecisionWebhook)
+	}
+	if topologyMatch.quarantine != nil {
+		topologyMatch.quarantine.forgetDeletedNodes(handle.SharedInformerFactory().Core().V1().Nodes().Informer())
+	}
+	topologyMatch.configChanges.forgetDeletedNodes(handle.SharedInformerFactory().Core().V1().Nodes().Informer())
+	topologyMatch.invalidTopologies.forgetDeletedNodes(handle.SharedInformerFactory().Core().V1().Nodes().Informer())
 
 	return topologyMatch, nil
 }
 
+func (tm *TopologyMatch) topologyManagerConfig(nodeTopology *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
+	conf := tm.reportedTopologyManagerConfig(nodeTopology)
+	if len(tm.enabledPolicies) > 0 && !tm.enabledPolicies.Has(conf.Policy) {
+		klog.V(6).InfoS("topology manager policy not enabled, handling it as none", "node", nodeTopology.Name, "policy", conf.Policy)
+		conf.Policy = kubeletconfig.NoneTopologyManagerPolicy
+	}
+	if tm.restrictedAsSingleNUMA && conf.Policy == kubeletconfig.RestrictedTopologyManagerPolicy {
+		klog.V(6).InfoS("handling the restricted policy as single-numa-node", "node", nodeTopology.Name)
+		conf.Policy = kubeletconfig.SingleNumaNodeTopologyManagerPolicy
+	}
+	return conf
+}
+
+// reportedTopologyManagerConfig returns the topology manager configuration of the node, as resolved from the NRT data
+// by the configured PolicyResolver, before the policy overrides.
+func (tm *TopologyMatch) reportedTopologyManagerConfig(nodeTopology *topologyv1alpha2.NodeResourceTopology) TopologyManagerConfig {
+	if tm.policyResolver == nil {
+		return topologyManagerConfigFromNodeResourceTopology(nodeTopology)
+	}
+	return tm.policyResolver.TopologyManagerConfig(nodeTopology)
+}
+
+// NodeTopologyManagerConfig returns the topology manager configuration the plugin uses for the node, as resolved
+// from the cached NRT data, honoring the configured PolicyResolver and the policy overrides. Returns false if the
+// cache has no usable NRT data for the node.
+func (tm *TopologyMatch) NodeTopologyManagerConfig(ctx context.Context, nodeName string) (TopologyManagerConfig, bool) {
+	nodeTopology, ok := tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, nil)
+	if !ok || nodeTopology == nil {
+		return TopologyManagerConfig{}, false
+	}
+	return tm.topologyManagerConfig(nodeTopology), true
+}
+
+// isMisconfigured returns true if the node must be handled like the nodes without NRT object, because the
+// topology manager configuration it reports is ambiguous. The deprecated topologyPolicies are only checked
+// if the configuration is taken from them, that is if the attributes don't provide both the scope and the policy.
+func (tm *TopologyMatch) isMisconfigured(nodeTopology *topologyv1alpha2.NodeResourceTopology) bool {
+	if !tm.strictTopologyPolicies || attributesProvideConfig(nodeTopology.Attributes) {
+		return false
+	}
+	return hasConflictingTopologyPolicies(nodeTopology.TopologyPolicies)
+}
+
 // EventsToRegister returns the possible events that may make a Pod
 // failed by this plugin schedulable.
 // NOTE: if in-place-update (KEP 1287) gets implemented, then PodUpdate event
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/wait"
 	k8scache "k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -71,6 +72,13 @@ func initNodeTopologyInformer(tcfg *apiconfig.NodeResourceTopologyMatchArgs, han
 
 	klog.V(3).InfoS("enable NodeTopology cache (needs the Reserve plugin)", "resyncPeriod", resyncPeriod)
 
+	if tcfg.Cache != nil && tcfg.Cache.FullResyncPeriodSeconds != nil && *tcfg.Cache.FullResyncPeriodSeconds > 0 {
+		fullResyncPeriod := time.Duration(*tcfg.Cache.FullResyncPeriodSeconds) * time.Second
+		go wait.Forever(nrtCache.FullResync, fullResyncPeriod)
+
+		klog.V(3).InfoS("enable NodeTopology cache full resync", "fullResyncPeriod", fullResyncPeriod)
+	}
+
 	return nrtCache, nil
 }
 
@@ -99,6 +107,8 @@ func initNodeTopologyForeignPodsDetection(cfg *apiconfig.NodeResourceTopologyCac
 	nrtcache.SetupForeignPodsDetector(profileName, podSharedInformer, nrtCache)
 }
 
+// createNUMANodeList returns the NUMA nodes described by the zones. The list is owned by the caller: its resources and
+// costs are copies, so the handlers can consume them without affecting the zones, which may belong to a cached object.
 func createNUMANodeList(zones topologyv1alpha2.ZoneList) NUMANodeList {
 	numaIDToZoneIDx := make([]int, maxNUMAId)
 	nodes := NUMANodeList{}
@@ -173,6 +183,13 @@ func extractCosts(costs topologyv1alpha2.CostList) map[int]int {
 func extractResources(zone topologyv1alpha2.Zone) corev1.ResourceList {
 	res := make(corev1.ResourceList)
 	for _, resInfo := range zone.Resources {
+		// a negative amount can only come from a buggy producer; don't let it
+		// propagate in the capacity math, consider the resource exhausted instead.
+		if resInfo.Available.Sign() < 0 {
+			klog.Warningf("zone %q reports negative available %q: %s, treating as zero", zone.Name, resInfo.Name, resInfo.Available.String())
+			res[corev1.ResourceName(resInfo.Name)] = *resource.NewQuantity(0, resInfo.Available.Format)
+			continue
+		}
 		res[corev1.ResourceName(resInfo.Name)] = resInfo.Available.DeepCopy()
 	}
 	return res
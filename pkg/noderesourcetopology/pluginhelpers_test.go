@@ -22,7 +22,11 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
 	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
 )
 
@@ -171,6 +175,90 @@ func TestOnlyNonNUMAResources(t *testing.T) {
 	}
 }
 
+func TestExtractResourcesNonPositive(t *testing.T) {
+	testCases := []struct {
+		description string
+		available   string
+		expected    int64
+	}{
+		{
+			description: "positive quantity",
+			available:   "4",
+			expected:    4,
+		},
+		{
+			description: "zero quantity",
+			available:   "0",
+			expected:    0,
+		},
+		{
+			description: "negative quantity",
+			available:   "-2",
+			expected:    0,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			zone := topologyv1alpha2.Zone{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", testCase.available),
+				},
+			}
+			res := extractResources(zone)
+			qty, ok := res[corev1.ResourceCPU]
+			if !ok {
+				t.Fatalf("missing resource %q", cpu)
+			}
+			if qty.Value() != testCase.expected {
+				t.Fatalf("expected %d to equal %d", qty.Value(), testCase.expected)
+			}
+		})
+	}
+}
+
+func TestCreateNUMANodeListOwnership(t *testing.T) {
+	zones := topologyv1alpha2.ZoneList{
+		{
+			Name: "node-0",
+			Type: "Node",
+			Costs: topologyv1alpha2.CostList{
+				{Name: "node-0", Value: 10},
+				{Name: "node-1", Value: 20},
+			},
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+		{
+			Name: "node-1",
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				MakeTopologyResInfo(cpu, "4", "4"),
+				MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+			},
+		},
+	}
+	pristine := zones.DeepCopy()
+
+	numaNodes := createNUMANodeList(zones)
+	subtractFromNUMA(numaNodes, 0, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("3"),
+		corev1.ResourceMemory: resource.MustParse("6Gi"),
+	}, nil)
+	numaNodes[0].Costs[1] = 99
+	numaNodes[1].Resources[corev1.ResourceCPU] = resource.MustParse("1")
+
+	if !equality.Semantic.DeepEqual(zones, pristine) {
+		t.Errorf("zones mutated through the NUMA node list: %+v", zones)
+	}
+	if fresh := createNUMANodeList(zones); !equality.Semantic.DeepEqual(fresh, createNUMANodeList(pristine)) {
+		t.Errorf("NUMA node list affected by a previous one: %+v", fresh)
+	}
+}
+
 func TestGetForeignPodsDetectMode(t *testing.T) {
 	detectAll := apiconfig.ForeignPodsDetectAll
 	detectNone := apiconfig.ForeignPodsDetectNone
@@ -25,4 +25,5 @@ import (
 
 func (tm *TopologyMatch) PostBind(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
 	tm.nrtCache.PostBind(nodeName, pod)
+	tm.downgrades.forget(pod)
 }
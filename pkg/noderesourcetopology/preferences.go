@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+)
+
+const (
+	AnnotationKeyPrefix = "noderesourcetopology.scheduling.x-k8s.io/"
+	// AnnotationPreferredNUMANodes is the number of NUMA nodes the pod is happy to span, e.g. "2".
+	AnnotationPreferredNUMANodes = AnnotationKeyPrefix + "preferred-numa-nodes"
+	// AnnotationRequiredResources is a comma-separated list of resources which must be NUMA-aligned
+	// regardless of the pod QoS class, e.g. "cpu,memory".
+	AnnotationRequiredResources = AnnotationKeyPrefix + "required-resources"
+	// AnnotationExcludedResources is a comma-separated list of resources whose NUMA alignment must not be checked,
+	// e.g. "vendor.com/nic". Overrides the excludedResources and requiredAlignmentResources plugin args.
+	AnnotationExcludedResources = AnnotationKeyPrefix + "excluded-resources"
+	// AnnotationMinMemoryNUMANodes is the minimum number of NUMA nodes the memory of the pod must be spread across,
+	// for memory bandwidth bound workloads, e.g. "2".
+	AnnotationMinMemoryNUMANodes = AnnotationKeyPrefix + "min-memory-numa-nodes"
+
+	// LabelWorkloadProfile is the name of the workload profile, in the pod namespace, holding
+	// the NUMA preferences of the pod. Takes precedence over the annotations above.
+	LabelWorkloadProfile = AnnotationKeyPrefix + "workload-profile"
+)
+
+// NUMAPreferences describes how a workload wants to be placed over the NUMA nodes.
+// The zero value means no preferences, and it is always valid.
+type NUMAPreferences struct {
+	// PreferredNUMANodes is the number of NUMA nodes the workload is happy to span.
+	// Placements needing up to this many NUMA nodes score the same. Zero means no preference.
+	PreferredNUMANodes int
+	// RequiredResources must be NUMA-aligned regardless of the pod QoS class.
+	RequiredResources []v1.ResourceName
+	// ExcludedResources are never NUMA-aligned. RequiredResources take precedence.
+	ExcludedResources []v1.ResourceName
+	// MinMemoryNUMANodes is the minimum number of NUMA nodes the memory of the workload must be evenly spread across.
+	// Values lower than 2 mean no spread is required.
+	MinMemoryNUMANodes int
+}
+
+func (np NUMAPreferences) requiresAlignment(resource v1.ResourceName) bool {
+	return containsResourceName(np.RequiredResources, resource)
+}
+
+func (np NUMAPreferences) excludesAlignment(resource v1.ResourceName) bool {
+	return containsResourceName(np.ExcludedResources, resource) && !np.requiresAlignment(resource)
+}
+
+func containsResourceName(resNames []v1.ResourceName, resource v1.ResourceName) bool {
+	for _, res := range resNames {
+		if res == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveNUMANodesCount returns the NUMA nodes count to be used for scoring: spanning up to
+// the preferred NUMA nodes count is as good as spanning just one NUMA node.
+func (np NUMAPreferences) effectiveNUMANodesCount(count int) int {
+	if np.PreferredNUMANodes <= 1 {
+		return count
+	}
+	if count <= np.PreferredNUMANodes {
+		return 1
+	}
+	return count - np.PreferredNUMANodes + 1
+}
+
+// WorkloadProfileLister provides the NUMA preferences stored in workload profiles,
+// e.g. backed by the lister of a custom resource.
+type WorkloadProfileLister interface {
+	// NUMAPreferences returns the preferences of the profile `name` in `namespace`.
+	// Returns false if the profile does not exist.
+	NUMAPreferences(namespace, name string) (NUMAPreferences, bool)
+}
+
+// numaPreferencesForPod resolves the NUMA preferences for the given pod. The workload profile
+// referenced by the pod, if any, takes precedence over the pod annotations, which take precedence
+// over the resources the plugin args exclude from or require the NUMA alignment.
+func (tm *TopologyMatch) numaPreferencesForPod(pod *v1.Pod) NUMAPreferences {
+	if profileName, ok := pod.Labels[LabelWorkloadProfile]; ok && tm.profileLister != nil {
+		prefs, found := tm.profileLister.NUMAPreferences(pod.Namespace, profileName)
+		if found {
+			return tm.withAlignmentDefaults(pod, prefs)
+		}
+		klog.V(4).InfoS("workload profile not found, falling back to annotations", "pod", klog.KObj(pod), "profile", profileName)
+	}
+	return tm.withAlignmentDefaults(pod, numaPreferencesFromAnnotations(pod))
+}
+
+// withAlignmentDefaults adds to the preferences the resources the plugin args exclude from or require the NUMA alignment,
+// unless the preferences of the pod already state otherwise.
+func (tm *TopologyMatch) withAlignmentDefaults(pod *v1.Pod, prefs NUMAPreferences) NUMAPreferences {
+	if len(tm.requiredAlignment) == 0 && len(tm.excludedResources) == 0 {
+		return prefs
+	}
+	// don't modify the slices of the workload profile
+	required := append([]v1.ResourceName(nil), prefs.RequiredResources...)
+	excluded := append([]v1.ResourceName(nil), prefs.ExcludedResources...)
+	for _, resName := range tm.requiredAlignment {
+		if prefs.requiresAlignment(resName) {
+			continue
+		}
+		if prefs.excludesAlignment(resName) {
+			klog.V(4).InfoS("pod overrides the required alignment of resource", "pod", klog.KObj(pod), "resource", resName)
+			continue
+		}
+		required = append(required, resName)
+	}
+	for _, resName := range tm.excludedResources {
+		if prefs.excludesAlignment(resName) {
+			continue
+		}
+		if prefs.requiresAlignment(resName) {
+			klog.V(4).InfoS("pod overrides the alignment exclusion of resource", "pod", klog.KObj(pod), "resource", resName)
+			continue
+		}
+		excluded = append(excluded, resName)
+	}
+	prefs.RequiredResources = required
+	prefs.ExcludedResources = excluded
+	return prefs
+}
+
+func numaPreferencesFromAnnotations(pod *v1.Pod) NUMAPreferences {
+	prefs := NUMAPreferences{}
+	if val, ok := pod.Annotations[AnnotationPreferredNUMANodes]; ok {
+		count, err := strconv.Atoi(val)
+		if err != nil || count < 0 {
+			klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationPreferredNUMANodes, "value", val)
+		} else {
+			prefs.PreferredNUMANodes = count
+		}
+	}
+	if val, ok := pod.Annotations[AnnotationMinMemoryNUMANodes]; ok {
+		count, err := strconv.Atoi(val)
+		if err != nil || count < 0 {
+			klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationMinMemoryNUMANodes, "value", val)
+		} else {
+			prefs.MinMemoryNUMANodes = count
+		}
+	}
+	if val, ok := pod.Annotations[AnnotationRequiredResources]; ok {
+		prefs.RequiredResources = parseResourceNames(pod, AnnotationRequiredResources, val)
+	}
+	if val, ok := pod.Annotations[AnnotationExcludedResources]; ok {
+		for _, resName := range parseResourceNames(pod, AnnotationExcludedResources, val) {
+			if prefs.requiresAlignment(resName) {
+				klog.V(2).InfoS("ignoring resource both required and excluded", "pod", klog.KObj(pod), "annotation", AnnotationExcludedResources, "resource", resName)
+				continue
+			}
+			prefs.ExcludedResources = append(prefs.ExcludedResources, resName)
+		}
+	}
+	return prefs
+}
+
+// parseResourceNames parses a comma-separated list of resource names, skipping the malformed ones.
+func parseResourceNames(pod *v1.Pod, annotation, val string) []v1.ResourceName {
+	var resNames []v1.ResourceName
+	for _, name := range strings.Split(val, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+			klog.V(2).InfoS("ignoring malformed resource name", "pod", klog.KObj(pod), "annotation", annotation, "resource", name, "errors", errs)
+			continue
+		}
+		resNames = append(resNames, v1.ResourceName(name))
+	}
+	return resNames
+}
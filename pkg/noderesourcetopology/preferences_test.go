@@ -0,0 +1,495 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+type fakeProfileLister map[string]NUMAPreferences
+
+func (fpl fakeProfileLister) NUMAPreferences(namespace, name string) (NUMAPreferences, bool) {
+	prefs, ok := fpl[namespace+"/"+name]
+	return prefs, ok
+}
+
+func TestNUMAPreferencesForPod(t *testing.T) {
+	lister := fakeProfileLister{
+		"ns1/latency-critical": {
+			PreferredNUMANodes: 1,
+			RequiredResources:  []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		lister      WorkloadProfileLister
+		labels      map[string]string
+		annotations map[string]string
+		expected    NUMAPreferences
+	}{
+		{
+			name:     "no lister, no annotations",
+			expected: NUMAPreferences{},
+		},
+		{
+			name:   "no lister, profile reference",
+			labels: map[string]string{LabelWorkloadProfile: "latency-critical"},
+			annotations: map[string]string{
+				AnnotationPreferredNUMANodes: "2",
+			},
+			expected: NUMAPreferences{PreferredNUMANodes: 2},
+		},
+		{
+			name:   "profile found",
+			lister: lister,
+			labels: map[string]string{LabelWorkloadProfile: "latency-critical"},
+			annotations: map[string]string{
+				AnnotationPreferredNUMANodes: "2",
+			},
+			expected: NUMAPreferences{
+				PreferredNUMANodes: 1,
+				RequiredResources:  []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory},
+			},
+		},
+		{
+			name:   "profile missing",
+			lister: lister,
+			labels: map[string]string{LabelWorkloadProfile: "batch"},
+			annotations: map[string]string{
+				AnnotationRequiredResources: "cpu, vendor.com/dev",
+			},
+			expected: NUMAPreferences{
+				RequiredResources: []v1.ResourceName{v1.ResourceCPU, v1.ResourceName("vendor.com/dev")},
+			},
+		},
+		{
+			name:        "lister, no profile reference",
+			lister:      lister,
+			annotations: map[string]string{AnnotationPreferredNUMANodes: "3"},
+			expected:    NUMAPreferences{PreferredNUMANodes: 3},
+		},
+		{
+			name:        "malformed annotation",
+			annotations: map[string]string{AnnotationPreferredNUMANodes: "-1"},
+			expected:    NUMAPreferences{},
+		},
+		{
+			name:        "memory spread",
+			annotations: map[string]string{AnnotationMinMemoryNUMANodes: "2"},
+			expected:    NUMAPreferences{MinMemoryNUMANodes: 2},
+		},
+		{
+			name:        "malformed memory spread annotation",
+			annotations: map[string]string{AnnotationMinMemoryNUMANodes: "two"},
+			expected:    NUMAPreferences{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{profileLister: tt.lister}
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns1",
+					Name:        "pod",
+					Labels:      tt.labels,
+					Annotations: tt.annotations,
+				},
+			}
+			got := tm.numaPreferencesForPod(pod)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got=%+v expected=%+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEffectiveNUMANodesCount(t *testing.T) {
+	tests := []struct {
+		preferred int
+		count     int
+		expected  int
+	}{
+		{preferred: 0, count: 1, expected: 1},
+		{preferred: 0, count: 3, expected: 3},
+		{preferred: 1, count: 2, expected: 2},
+		{preferred: 2, count: 1, expected: 1},
+		{preferred: 2, count: 2, expected: 1},
+		{preferred: 2, count: 4, expected: 3},
+	}
+
+	for _, tt := range tests {
+		got := NUMAPreferences{PreferredNUMANodes: tt.preferred}.effectiveNUMANodesCount(tt.count)
+		if got != tt.expected {
+			t.Errorf("preferred=%d count=%d got=%d expected=%d", tt.preferred, tt.count, got, tt.expected)
+		}
+	}
+}
+
+func TestFilterRequiredResourcesFromProfile(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	// burstable: requests are lower than limits
+	pod := makePodWithReqAndLimitByResourceList(
+		&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("6"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+		&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("8"),
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	)
+	pod.Namespace = "ns1"
+	pod.Name = "burstable"
+	pod.Labels = map[string]string{LabelWorkloadProfile: "latency-critical"}
+
+	tests := []struct {
+		name       string
+		lister     WorkloadProfileLister
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "absent profile, no alignment required",
+			lister:     fakeProfileLister{},
+			wantStatus: nil,
+		},
+		{
+			name: "profile requires cpu alignment",
+			lister: fakeProfileLister{
+				"ns1/latency-critical": {RequiredResources: []v1.ResourceName{v1.ResourceCPU}},
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:      nrtcache.NewPassthrough(fakeClient),
+				profileLister: tt.lister,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestNUMAPreferencesAlignmentOverrides(t *testing.T) {
+	const gpu = v1.ResourceName("vendor.com/gpu")
+	const nic = v1.ResourceName("vendor.com/nic")
+
+	tests := []struct {
+		name        string
+		excluded    []v1.ResourceName
+		required    []v1.ResourceName
+		annotations map[string]string
+		expected    NUMAPreferences
+	}{
+		{
+			name:     "plugin args",
+			excluded: []v1.ResourceName{gpu},
+			required: []v1.ResourceName{v1.ResourceCPU},
+			expected: NUMAPreferences{
+				RequiredResources: []v1.ResourceName{v1.ResourceCPU},
+				ExcludedResources: []v1.ResourceName{gpu},
+			},
+		},
+		{
+			name:        "pod re-includes an excluded resource",
+			excluded:    []v1.ResourceName{gpu},
+			annotations: map[string]string{AnnotationRequiredResources: "vendor.com/gpu"},
+			expected: NUMAPreferences{
+				RequiredResources: []v1.ResourceName{gpu},
+			},
+		},
+		{
+			name:        "pod excludes a required resource",
+			required:    []v1.ResourceName{v1.ResourceCPU, nic},
+			annotations: map[string]string{AnnotationExcludedResources: "vendor.com/nic"},
+			expected: NUMAPreferences{
+				RequiredResources: []v1.ResourceName{v1.ResourceCPU},
+				ExcludedResources: []v1.ResourceName{nic},
+			},
+		},
+		{
+			name: "pod both requires and excludes a resource",
+			annotations: map[string]string{
+				AnnotationRequiredResources: "vendor.com/gpu",
+				AnnotationExcludedResources: "vendor.com/gpu, vendor.com/nic",
+			},
+			expected: NUMAPreferences{
+				RequiredResources: []v1.ResourceName{gpu},
+				ExcludedResources: []v1.ResourceName{nic},
+			},
+		},
+		{
+			name:        "malformed resource names",
+			annotations: map[string]string{AnnotationExcludedResources: "vendor.com/nic, -bad/, a b"},
+			expected: NUMAPreferences{
+				ExcludedResources: []v1.ResourceName{nic},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				excludedResources: tt.excluded,
+				requiredAlignment: tt.required,
+			}
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "ns1",
+					Name:        "pod",
+					Annotations: tt.annotations,
+				},
+			}
+			got := tm.numaPreferencesForPod(pod)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got=%+v expected=%+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterExcludedResources(t *testing.T) {
+	const gpu = "vendor.com/gpu"
+
+	// no NUMA node has both the cpus and the gpu the pod needs
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(gpu, "1", "0"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "1"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(gpu, "1", "1"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		excluded    []v1.ResourceName
+		annotations map[string]string
+		wantStatus  *framework.Status
+	}{
+		{
+			name:       "gpu aligned",
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:     "gpu excluded by the plugin args",
+			excluded: []v1.ResourceName{gpu},
+		},
+		{
+			name:        "gpu excluded by the pod",
+			annotations: map[string]string{AnnotationExcludedResources: gpu},
+		},
+		{
+			name:        "pod re-includes the gpu excluded by the plugin args",
+			excluded:    []v1.ResourceName{gpu},
+			annotations: map[string]string{AnnotationRequiredResources: gpu},
+			wantStatus:  framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:       resource.MustParse("2"),
+				v1.ResourceMemory:    resource.MustParse("2Gi"),
+				v1.ResourceName(gpu): resource.MustParse("1"),
+			})
+			pod.Annotations = tt.annotations
+			tm := TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				excludedResources: tt.excluded,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestFilterRequiredResourcesNonePolicy(t *testing.T) {
+	makeNRT := func(name, policy string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Attributes: topologyv1alpha2.AttributeList{
+				{Name: AttributePolicy, Value: policy},
+				{Name: AttributeScope, Value: "pod"},
+			},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	nonePolicy := makeNRT("none", "none")
+	singleNUMA := makeNRT("single-numa", "single-numa-node")
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{nonePolicy, singleNUMA} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		nrt         *topologyv1alpha2.NodeResourceTopology
+		annotations map[string]string
+		wantStatus  *framework.Status
+	}{
+		{
+			name: "alignment not required",
+			nrt:  nonePolicy,
+		},
+		{
+			name:        "alignment required",
+			nrt:         nonePolicy,
+			annotations: map[string]string{AnnotationRequiredResources: "cpu"},
+			wantStatus:  framework.NewStatus(framework.UnschedulableAndUnresolvable, "cannot align required resource cpu: node topology manager policy is none"),
+		},
+		{
+			name:        "alignment required for a resource not requested",
+			nrt:         nonePolicy,
+			annotations: map[string]string{AnnotationRequiredResources: "vendor.com/gpu"},
+		},
+		{
+			name:        "alignment required, node aligning",
+			nrt:         singleNUMA,
+			annotations: map[string]string{AnnotationRequiredResources: "cpu"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// burstable: requests are lower than limits
+			pod := makePodWithReqAndLimitByResourceList(
+				&v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("2"),
+					v1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+				&v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("4"),
+					v1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			)
+			pod.Annotations = tt.annotations
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// qosResourcePolicy tells, for each QoS class, the resources whose NUMA capacity doesn't constrain the pods.
+// The zero value is the kubelet behavior: the cpu, memory and hugepages are pinned only for the guaranteed pods,
+// so their NUMA capacity is ignored for the other ones.
+type qosResourcePolicy struct {
+	// burstableEnforced are the resources whose NUMA capacity is checked for the burstable pods nonetheless
+	burstableEnforced sets.Set[v1.ResourceName]
+}
+
+func newQoSResourcePolicy(spec *apiconfig.QoSResourcePolicy) qosResourcePolicy {
+	var policy qosResourcePolicy
+	if spec == nil || len(spec.BurstableEnforcedResources) == 0 {
+		return policy
+	}
+	policy.burstableEnforced = sets.New[v1.ResourceName]()
+	for _, name := range spec.BurstableEnforcedResources {
+		policy.burstableEnforced.Insert(v1.ResourceName(name))
+	}
+	return policy
+}
+
+// capacityIgnored returns true if the NUMA capacity of the resource doesn't constrain the pods of the QoS class.
+func (policy qosResourcePolicy) capacityIgnored(qos v1.PodQOSClass, resName v1.ResourceName) bool {
+	if qos == v1.PodQOSGuaranteed || !isCapacityIgnoredForQoS(resName) {
+		return false
+	}
+	return qos != v1.PodQOSBurstable || !policy.burstableEnforced.Has(resName)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestQoSResourcePolicyCapacityIgnored(t *testing.T) {
+	hugepages2Mi := v1.ResourceName("hugepages-2Mi")
+	enforcingMemory := newQoSResourcePolicy(&apiconfig.QoSResourcePolicy{
+		BurstableEnforcedResources: []string{string(v1.ResourceMemory)},
+	})
+
+	tests := []struct {
+		name     string
+		policy   qosResourcePolicy
+		qos      v1.PodQOSClass
+		resName  v1.ResourceName
+		expected bool
+	}{
+		{
+			name:    "default, guaranteed memory",
+			qos:     v1.PodQOSGuaranteed,
+			resName: v1.ResourceMemory,
+		},
+		{
+			name:     "default, burstable memory",
+			qos:      v1.PodQOSBurstable,
+			resName:  v1.ResourceMemory,
+			expected: true,
+		},
+		{
+			name:     "default, best effort hugepages",
+			qos:      v1.PodQOSBestEffort,
+			resName:  hugepages2Mi,
+			expected: true,
+		},
+		{
+			name:    "default, burstable device",
+			qos:     v1.PodQOSBurstable,
+			resName: nicResourceName,
+		},
+		{
+			name:    "enforcing memory, burstable memory",
+			policy:  enforcingMemory,
+			qos:     v1.PodQOSBurstable,
+			resName: v1.ResourceMemory,
+		},
+		{
+			name:     "enforcing memory, burstable cpu",
+			policy:   enforcingMemory,
+			qos:      v1.PodQOSBurstable,
+			resName:  v1.ResourceCPU,
+			expected: true,
+		},
+		{
+			name:     "enforcing memory, best effort memory",
+			policy:   enforcingMemory,
+			qos:      v1.PodQOSBestEffort,
+			resName:  v1.ResourceMemory,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.capacityIgnored(tt.qos, tt.resName); got != tt.expected {
+				t.Errorf("capacityIgnored(%s, %s) = %v, expected %v", tt.qos, tt.resName, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterQoSResourcePolicy(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "qos-policy"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "4Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "4Gi"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		policy   *apiconfig.QoSResourcePolicy
+		requests v1.ResourceList
+		expected framework.Code
+	}{
+		{
+			name: "default, burstable pod exceeding the NUMA memory",
+			requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("6Gi"),
+			},
+		},
+		{
+			name: "enforcing memory, burstable pod exceeding the NUMA memory",
+			policy: &apiconfig.QoSResourcePolicy{
+				BurstableEnforcedResources: []string{string(v1.ResourceMemory)},
+			},
+			requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("6Gi"),
+			},
+			expected: framework.Unschedulable,
+		},
+		{
+			name: "enforcing memory, burstable pod fitting the NUMA memory",
+			policy: &apiconfig.QoSResourcePolicy{
+				BurstableEnforcedResources: []string{string(v1.ResourceMemory)},
+			},
+			requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
+		{
+			name: "enforcing memory, burstable pod exceeding the NUMA cpu",
+			policy: &apiconfig.QoSResourcePolicy{
+				BurstableEnforcedResources: []string{string(v1.ResourceMemory)},
+			},
+			requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				qosResourcePolicy: newQoSResourcePolicy(tt.policy),
+			}
+			pod := makePodWithReqByResourceList(&tt.requests)
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if status.Code() != tt.expected {
+				t.Errorf("unexpected status: got %v, expected code %v", status, tt.expected)
+			}
+		})
+	}
+}
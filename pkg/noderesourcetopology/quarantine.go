@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sync"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+type quarantineState struct {
+	resourceVersion string
+	valid           bool
+	consecutive     int64
+	quarantined     bool
+	// the streak before the observation of the current update, to re-evaluate it
+	prevValid       bool
+	prevConsecutive int64
+	overReserved    bool
+}
+
+// nodeQuarantine tracks the validity of the NRT updates of the nodes, and quarantines the nodes reporting
+// invalid data too many times in a row. Each update, identified by its resource version, is counted once.
+// An update is invalid if it fails the validation, or if the node was marked maybe over-reserved while it
+// was the current one, meaning the reported availability didn't match the pods the node runs.
+type nodeQuarantine struct {
+	threshold         int64
+	recoveryThreshold int64
+	lock              sync.Mutex
+	nodes             map[string]*quarantineState
+}
+
+func newNodeQuarantine(conf *apiconfig.NodeQuarantine) *nodeQuarantine {
+	if conf == nil {
+		return nil
+	}
+	return &nodeQuarantine{
+		threshold:         conf.Threshold,
+		recoveryThreshold: conf.RecoveryThreshold,
+		nodes:             make(map[string]*quarantineState),
+	}
+}
+
+// forgetDeletedNodes makes the quarantine drop the state of the nodes deleted from the cluster.
+func (nq *nodeQuarantine) forgetDeletedNodes(nodeInformer k8scache.SharedInformer) {
+	nodeInformer.AddEventHandler(k8scache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(k8scache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			node, ok := obj.(*v1.Node)
+			if !ok {
+				return
+			}
+			nq.forget(node.Name)
+		},
+	})
+}
+
+func (nq *nodeQuarantine) forget(nodeName string) {
+	nq.lock.Lock()
+	defer nq.lock.Unlock()
+	delete(nq.nodes, nodeName)
+}
+
+// isQuarantined records the observation of the NRT object, if not observed already, and returns
+// true if the node is quarantined.
+func (nq *nodeQuarantine) isQuarantined(nrt *topologyv1alpha2.NodeResourceTopology) bool {
+	nq.lock.Lock()
+	defer nq.lock.Unlock()
+
+	state, ok := nq.nodes[nrt.Name]
+	if !ok {
+		state = &quarantineState{}
+		nq.nodes[nrt.Name] = state
+	}
+	if ok && state.resourceVersion == nrt.ResourceVersion {
+		return state.quarantined
+	}
+	state.resourceVersion = nrt.ResourceVersion
+	state.prevValid, state.prevConsecutive = state.valid, state.consecutive
+	state.overReserved = false
+
+	errs := ValidateNRT(nrt)
+	nq.observe(nrt.Name, state, len(errs) == 0, "errors", errs)
+	return state.quarantined
+}
+
+// quarantined returns true if the node is quarantined, as of the last observation of its NRT data, without recording
+// any observation. Nodes whose NRT data was never observed are not quarantined.
+func (nq *nodeQuarantine) quarantined(nodeName string) bool {
+	nq.lock.Lock()
+	defer nq.lock.Unlock()
+	state, ok := nq.nodes[nodeName]
+	return ok && state.quarantined
+}
+
+// overReserved records the node was marked maybe over-reserved, invalidating its current update
+// if it was valid. Nodes whose NRT data was never observed are ignored.
+func (nq *nodeQuarantine) overReserved(nodeName string) {
+	nq.lock.Lock()
+	defer nq.lock.Unlock()
+
+	state, ok := nq.nodes[nodeName]
+	if !ok || state.overReserved {
+		return
+	}
+	state.overReserved = true
+	if !state.valid {
+		return
+	}
+	nq.observe(nodeName, state, false, "reason", "over-reserved")
+}
+
+// observe sets the validity of the current update on top of the streak before it, and updates the quarantine.
+func (nq *nodeQuarantine) observe(nodeName string, state *quarantineState, valid bool, details ...interface{}) {
+	state.valid = valid
+	state.consecutive = 1
+	if valid == state.prevValid && state.prevConsecutive > 0 {
+		state.consecutive = state.prevConsecutive + 1
+	}
+
+	if !state.quarantined && !valid && state.consecutive >= nq.threshold {
+		klog.V(2).InfoS("quarantining node for invalid topology data", append([]interface{}{"node", nodeName, "updates", state.consecutive}, details...)...)
+		state.quarantined = true
+	} else if state.quarantined && valid && state.consecutive >= nq.recoveryThreshold {
+		klog.V(2).InfoS("releasing node from quarantine", "node", nodeName, "updates", state.consecutive)
+		state.quarantined = false
+	}
+}
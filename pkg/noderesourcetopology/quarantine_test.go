@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makeQuarantineTestNRT(resourceVersion string, valid bool) *topologyv1alpha2.NodeResourceTopology {
+	available := "4"
+	if !valid {
+		available = "8" // exceeds the capacity
+	}
+	return &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1", ResourceVersion: resourceVersion},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", available),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+}
+
+func TestNodeQuarantine(t *testing.T) {
+	nq := newNodeQuarantine(&apiconfig.NodeQuarantine{
+		Threshold:         3,
+		RecoveryThreshold: 2,
+	})
+
+	steps := []struct {
+		valid    bool
+		expected bool
+	}{
+		{valid: false, expected: false},
+		{valid: false, expected: false},
+		{valid: true, expected: false}, // streak broken
+		{valid: false, expected: false},
+		{valid: false, expected: false},
+		{valid: false, expected: true},
+		{valid: false, expected: true},
+		{valid: true, expected: true},
+		{valid: false, expected: true}, // streak broken
+		{valid: true, expected: true},
+		{valid: true, expected: false},
+		{valid: true, expected: false},
+	}
+
+	for i, step := range steps {
+		nrt := makeQuarantineTestNRT(fmt.Sprintf("%d", i+1), step.valid)
+		if got := nq.isQuarantined(nrt); got != step.expected {
+			t.Fatalf("step %d (valid=%v): quarantined=%v expected=%v", i, step.valid, got, step.expected)
+		}
+		// observing the same update again must not change the outcome
+		if got := nq.isQuarantined(nrt); got != step.expected {
+			t.Fatalf("step %d (valid=%v) repeated: quarantined=%v expected=%v", i, step.valid, got, step.expected)
+		}
+	}
+}
+
+func TestNodeQuarantineOverReserved(t *testing.T) {
+	nq := newNodeQuarantine(&apiconfig.NodeQuarantine{
+		Threshold:         2,
+		RecoveryThreshold: 1,
+	})
+
+	// never observed, ignored
+	nq.overReserved("node1")
+	if _, ok := nq.nodes["node1"]; ok {
+		t.Fatalf("unexpected state for a node never observed")
+	}
+
+	steps := []struct {
+		valid        bool
+		overReserved int
+		expected     bool
+	}{
+		{valid: true, overReserved: 1, expected: false},
+		// counted once per update
+		{valid: true, overReserved: 3, expected: true},
+		{valid: true, expected: false},
+		{valid: false, overReserved: 1, expected: false},
+		{valid: true, overReserved: 1, expected: true},
+	}
+	for i, step := range steps {
+		nrt := makeQuarantineTestNRT(fmt.Sprintf("%d", i+1), step.valid)
+		nq.isQuarantined(nrt)
+		for n := 0; n < step.overReserved; n++ {
+			nq.overReserved(nrt.Name)
+		}
+		if got := nq.isQuarantined(nrt); got != step.expected {
+			t.Fatalf("step %d (valid=%v overReserved=%d): quarantined=%v expected=%v", i, step.valid, step.overReserved, got, step.expected)
+		}
+	}
+
+	nq.forget("node1")
+	if got := nq.isQuarantined(makeQuarantineTestNRT("100", true)); got {
+		t.Fatalf("forgotten node still quarantined")
+	}
+}
+
+func TestFilterNodeQuarantine(t *testing.T) {
+	nrt := makeQuarantineTestNRT("", false)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+
+	tm := TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+	}
+	if gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo); gotStatus != nil {
+		t.Errorf("quarantine disabled: unexpected status: %v", gotStatus)
+	}
+
+	tm.quarantine = newNodeQuarantine(&apiconfig.NodeQuarantine{
+		Threshold:         1,
+		RecoveryThreshold: 1,
+	})
+	wantStatus := framework.NewStatus(framework.UnschedulableAndUnresolvable, "node topology data quarantined")
+	if gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo); !reflect.DeepEqual(gotStatus, wantStatus) {
+		t.Errorf("status does not match: %v, want: %v", gotStatus, wantStatus)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// RecordedFilterDecision is a decision of the filter together with its inputs, serializable as JSON, to capture the
+// production decisions and lock them in as regression tests using ReplayFilterDecisions. The NRT object is the one
+// the filter got from its cache, and its name is the node name.
+type RecordedFilterDecision struct {
+	Pod                  *v1.Pod                                `json:"pod"`
+	NodeResourceTopology *topologyv1alpha2.NodeResourceTopology `json:"nodeResourceTopology"`
+	Allocatable          v1.ResourceList                        `json:"allocatable"`
+	// Verdict is the code of the status, like "Success" or "Unschedulable".
+	Verdict string `json:"verdict"`
+	// Reason is the message of the status, empty for the pods admitted.
+	Reason string `json:"reason,omitempty"`
+}
+
+// RecordFilterDecision returns the record of the given decision of the filter.
+func RecordFilterDecision(pod *v1.Pod, nodeTopology *topologyv1alpha2.NodeResourceTopology, allocatable v1.ResourceList, status *framework.Status) RecordedFilterDecision {
+	return RecordedFilterDecision{
+		Pod:                  pod.DeepCopy(),
+		NodeResourceTopology: nodeTopology.DeepCopy(),
+		Allocatable:          allocatable.DeepCopy(),
+		Verdict:              status.Code().String(),
+		Reason:               status.Message(),
+	}
+}
+
+// EvaluateFilter runs the filter, with the default args, for the pod on a node with the given allocatable resources
+// and NRT object, the name of the NRT object being the node name. The pods running on the node are accounted for
+// only through the available resources of the NRT object.
+func EvaluateFilter(pod *v1.Pod, nodeTopology *topologyv1alpha2.NodeResourceTopology, allocatable v1.ResourceList) *framework.Status {
+	tm := &TopologyMatch{
+		nrtCache: staticNRTCache{nodeTopology: nodeTopology},
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeTopology.Name},
+		Status: v1.NodeStatus{
+			Capacity:    allocatable,
+			Allocatable: allocatable,
+		},
+	})
+	return tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+}
+
+// ReplayFilterDecisions evaluates the filter on the inputs of the recorded decisions, returning an error listing the
+// decisions whose verdict or reason changed, or nil if they all match.
+func ReplayFilterDecisions(decisions []RecordedFilterDecision) error {
+	var errs []error
+	for idx, decision := range decisions {
+		if decision.Pod == nil || decision.NodeResourceTopology == nil {
+			errs = append(errs, fmt.Errorf("decision %d: missing pod or NRT object", idx))
+			continue
+		}
+		status := EvaluateFilter(decision.Pod, decision.NodeResourceTopology, decision.Allocatable)
+		if verdict, reason := status.Code().String(), status.Message(); verdict != decision.Verdict || reason != decision.Reason {
+			errs = append(errs, fmt.Errorf("decision %d: pod %s on node %s: got %s %q, recorded %s %q", idx,
+				klog.KObj(decision.Pod), decision.NodeResourceTopology.Name, verdict, reason, decision.Verdict, decision.Reason))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// staticNRTCache serves a single NRT object, which is never stale nor reserved.
+type staticNRTCache struct {
+	nodeTopology *topologyv1alpha2.NodeResourceTopology
+}
+
+func (c staticNRTCache) GetCachedNRTCopy(ctx context.Context, nodeName string, pod *v1.Pod) (*topologyv1alpha2.NodeResourceTopology, bool) {
+	if c.nodeTopology == nil || c.nodeTopology.Name != nodeName {
+		return nil, true
+	}
+	return c.nodeTopology.DeepCopy(), true
+}
+
+func (c staticNRTCache) NodeMaybeOverReserved(nodeName string, pod *v1.Pod)  {}
+func (c staticNRTCache) NodeHasForeignPods(nodeName string, pod *v1.Pod)     {}
+func (c staticNRTCache) ReserveNodeResources(nodeName string, pod *v1.Pod)   {}
+func (c staticNRTCache) UnreserveNodeResources(nodeName string, pod *v1.Pod) {}
+func (c staticNRTCache) PostBind(nodeName string, pod *v1.Pod)               {}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// recordedTrace holds two decisions on a node with a single NUMA node having 2 cpus left
+const recordedTrace = `[
+  {
+    "pod": {
+      "metadata": {"namespace": "ns1", "name": "small"},
+      "spec": {"containers": [{"name": "cnt", "resources": {
+        "requests": {"cpu": "2", "memory": "1Gi"},
+        "limits": {"cpu": "2", "memory": "1Gi"}
+      }}]}
+    },
+    "nodeResourceTopology": {
+      "metadata": {"name": "worker-0"},
+      "topologyPolicies": ["SingleNUMANodePodLevel"],
+      "zones": [{"name": "node-0", "type": "Node", "resources": [
+        {"name": "cpu", "capacity": "4", "allocatable": "4", "available": "2"},
+        {"name": "memory", "capacity": "8Gi", "allocatable": "8Gi", "available": "8Gi"}
+      ]}]
+    },
+    "allocatable": {"cpu": "4", "memory": "8Gi"},
+    "verdict": "Success"
+  },
+  {
+    "pod": {
+      "metadata": {"namespace": "ns1", "name": "large"},
+      "spec": {"containers": [{"name": "cnt", "resources": {
+        "requests": {"cpu": "3", "memory": "1Gi"},
+        "limits": {"cpu": "3", "memory": "1Gi"}
+      }}]}
+    },
+    "nodeResourceTopology": {
+      "metadata": {"name": "worker-0"},
+      "topologyPolicies": ["SingleNUMANodePodLevel"],
+      "zones": [{"name": "node-0", "type": "Node", "resources": [
+        {"name": "cpu", "capacity": "4", "allocatable": "4", "available": "2"},
+        {"name": "memory", "capacity": "8Gi", "allocatable": "8Gi", "available": "8Gi"}
+      ]}]
+    },
+    "allocatable": {"cpu": "4", "memory": "8Gi"},
+    "verdict": "Unschedulable",
+    "reason": "cannot align pod"
+  }
+]`
+
+func TestReplayFilterDecisions(t *testing.T) {
+	var decisions []RecordedFilterDecision
+	if err := json.Unmarshal([]byte(recordedTrace), &decisions); err != nil {
+		t.Fatalf("cannot decode the trace: %v", err)
+	}
+	if err := ReplayFilterDecisions(decisions); err != nil {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+
+	// a behavior change is reported
+	decisions[1].Verdict = framework.Success.String()
+	decisions[1].Reason = ""
+	err := ReplayFilterDecisions(decisions)
+	if err == nil || !strings.Contains(err.Error(), "decision 1: pod ns1/large on node worker-0") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRecordFilterDecisionRoundTrip(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "worker-1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(nicResourceName, "1", "0"),
+				},
+			},
+		},
+	}
+	allocatable := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+		nicResourceName:   resource.MustParse("1"),
+	}
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+		nicResourceName:   resource.MustParse("1"),
+	})
+
+	status := EvaluateFilter(pod, nrt, allocatable)
+	if status.Code() != framework.Unschedulable {
+		t.Fatalf("unexpected status: %v", status)
+	}
+	data, err := json.Marshal([]RecordedFilterDecision{RecordFilterDecision(pod, nrt, allocatable, status)})
+	if err != nil {
+		t.Fatalf("cannot encode the decision: %v", err)
+	}
+	var decisions []RecordedFilterDecision
+	if err := json.Unmarshal(data, &decisions); err != nil {
+		t.Fatalf("cannot decode the decision: %v", err)
+	}
+	if err := ReplayFilterDecisions(decisions); err != nil {
+		t.Errorf("unexpected mismatch: %v", err)
+	}
+}
@@ -25,10 +25,19 @@ import (
 
 func (tm *TopologyMatch) Reserve(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
 	tm.nrtCache.ReserveNodeResources(nodeName, pod)
+	if tm.placementWaste {
+		if stranded, ok := PlacementWaste(state, nodeName); ok {
+			observePlacementWaste(stranded)
+		}
+	}
+	tm.recordPlacement(ctx, state, pod, nodeName)
+	tm.observeChosenNUMANodes(state, nodeName)
+	tm.numaOccupancy.reserve(state, pod, nodeName)
 	// can't fail
 	return framework.NewStatus(framework.Success, "")
 }
 
 func (tm *TopologyMatch) Unreserve(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
 	tm.nrtCache.UnreserveNodeResources(nodeName, pod)
+	tm.numaOccupancy.forget(pod)
 }
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/podprovider"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+// TestFilterSeesReservedPods schedules two pods in quick succession on the same node: the NRT data and the node cache
+// of the scheduler don't reflect the first pod yet, so only the resources deducted by Reserve prevent the second pod
+// from being aligned on the NUMA node already booked by the first one.
+func TestFilterSeesReservedPods(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node-reserve"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "2"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	podLister := corelisters.NewPodLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+	nrtCache, err := nrtcache.NewOverReserve(nil, fakeClient, podLister, podprovider.IsPodRelevantAlways)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := &TopologyMatch{
+		nrtCache: nrtCache,
+	}
+
+	// only the NUMA node 0 can align any of the pods
+	makeTestPod := func(name string) *v1.Pod {
+		pod := makePodByResourceList(&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("3"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		})
+		pod.Name = name
+		return pod
+	}
+	first := makeTestPod("first")
+	second := makeTestPod("second")
+
+	// the node cache of the scheduler doesn't reflect the assumed pods either
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+
+	filter := func(pod *v1.Pod) *framework.Status {
+		return tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+	}
+
+	if status := filter(second); status != nil {
+		t.Fatalf("unexpected filter status before any reservation: %v", status)
+	}
+	if status := filter(first); status != nil {
+		t.Fatalf("unexpected filter status for the first pod: %v", status)
+	}
+	if status := tm.Reserve(context.Background(), framework.NewCycleState(), first, nrt.Name); !status.IsSuccess() {
+		t.Fatalf("unexpected reserve status: %v", status)
+	}
+	if status := filter(second); status.Code() != framework.Unschedulable {
+		t.Fatalf("second pod admitted on the NUMA node reserved for the first one: %v", status)
+	}
+
+	// the deduction is released along with the reservation
+	tm.Unreserve(context.Background(), framework.NewCycleState(), first, nrt.Name)
+	if status := filter(second); status != nil {
+		t.Fatalf("unexpected filter status after the first pod was unreserved: %v", status)
+	}
+}
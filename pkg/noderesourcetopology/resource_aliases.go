@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// resourceAliases maps the aliases to the names of the resources they stand for
+type resourceAliases map[v1.ResourceName]v1.ResourceName
+
+func newResourceAliases(specs []apiconfig.ResourceAliasSpec) resourceAliases {
+	if len(specs) == 0 {
+		return nil
+	}
+	ra := make(resourceAliases, len(specs))
+	for _, spec := range specs {
+		ra[v1.ResourceName(spec.Alias)] = v1.ResourceName(spec.Name)
+	}
+	return ra
+}
+
+// resolve renames the resources the NUMA nodes report under an alias, summing the aliases of the same resource.
+// The NUMA nodes reporting a resource under its name are trusted, and its aliases are left untouched there.
+func (ra resourceAliases) resolve(numaNodes NUMANodeList) NUMANodeList {
+	if len(ra) == 0 {
+		return numaNodes
+	}
+	for _, numaNode := range numaNodes {
+		resolved := make(map[v1.ResourceName]bool)
+		for alias, resName := range ra {
+			quantity, ok := numaNode.Resources[alias]
+			if !ok {
+				continue
+			}
+			if _, reported := numaNode.Resources[resName]; reported && !resolved[resName] {
+				continue
+			}
+			total := numaNode.Resources[resName]
+			total.Add(quantity)
+			numaNode.Resources[resName] = total
+			delete(numaNode.Resources, alias)
+			resolved[resName] = true
+		}
+	}
+	return numaNodes
+}
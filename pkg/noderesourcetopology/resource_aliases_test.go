@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+const (
+	devResourceName   = "vendor.com/dev"
+	devV0ResourceName = "vendor.com/dev-v0"
+	devV1ResourceName = "vendor.com/dev-v1"
+)
+
+func TestResourceAliasesResolve(t *testing.T) {
+	ra := newResourceAliases([]apiconfig.ResourceAliasSpec{
+		{Name: devResourceName, Alias: devV1ResourceName},
+		{Name: devResourceName, Alias: devV0ResourceName},
+	})
+
+	tests := []struct {
+		name      string
+		resources v1.ResourceList
+		expected  v1.ResourceList
+	}{
+		{
+			name: "reported under the name",
+			resources: v1.ResourceList{
+				devResourceName: resource.MustParse("2"),
+			},
+			expected: v1.ResourceList{
+				devResourceName: resource.MustParse("2"),
+			},
+		},
+		{
+			name: "reported under an alias",
+			resources: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				devV1ResourceName: resource.MustParse("2"),
+			},
+			expected: v1.ResourceList{
+				v1.ResourceCPU:  resource.MustParse("4"),
+				devResourceName: resource.MustParse("2"),
+			},
+		},
+		{
+			name: "reported under both the aliases",
+			resources: v1.ResourceList{
+				devV0ResourceName: resource.MustParse("1"),
+				devV1ResourceName: resource.MustParse("2"),
+			},
+			expected: v1.ResourceList{
+				devResourceName: resource.MustParse("3"),
+			},
+		},
+		{
+			name: "reported under the name and an alias",
+			resources: v1.ResourceList{
+				devResourceName:   resource.MustParse("2"),
+				devV1ResourceName: resource.MustParse("1"),
+			},
+			expected: v1.ResourceList{
+				devResourceName:   resource.MustParse("2"),
+				devV1ResourceName: resource.MustParse("1"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ra.resolve(NUMANodeList{{NUMAID: 0, Resources: tt.resources}})
+			if len(got[0].Resources) != len(tt.expected) {
+				t.Fatalf("resources=%v expected=%v", got[0].Resources, tt.expected)
+			}
+			for resName, quantity := range tt.expected {
+				if q, ok := got[0].Resources[resName]; !ok || q.Cmp(quantity) != 0 {
+					t.Errorf("resource %q: got=%v expected=%v", resName, q.String(), quantity.String())
+				}
+			}
+		})
+	}
+}
+
+func TestFilterResourceAliases(t *testing.T) {
+	// the NRT data still reports the device under its old name
+	makeNRT := func(name, numa0Devs, numa1Devs string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+						MakeTopologyResInfo(devV1ResourceName, "2", numa0Devs),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "0"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+						MakeTopologyResInfo(devV1ResourceName, "2", numa1Devs),
+					},
+				},
+			},
+		}
+	}
+	// only the NUMA node 1, which has no cpus left, has the device left on "mismatched"
+	aligned := makeNRT("aligned", "1", "2")
+	mismatched := makeNRT("mismatched", "0", "2")
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{aligned, mismatched} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	aliases := []apiconfig.ResourceAliasSpec{
+		{Name: devResourceName, Alias: devV1ResourceName},
+	}
+
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		aliases    []apiconfig.ResourceAliasSpec
+		wantStatus *framework.Status
+	}{
+		{
+			name: "without aliases, the device is considered node-level",
+			nrt:  mismatched,
+		},
+		{
+			name:    "alias fitting the pod",
+			nrt:     aligned,
+			aliases: aliases,
+		},
+		{
+			name:       "alias not fitting the pod",
+			nrt:        mismatched,
+			aliases:    aliases,
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:        nrtcache.NewPassthrough(fakeClient),
+				resourceAliases: newResourceAliases(tt.aliases),
+			}
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+				devResourceName:   resource.MustParse("1"),
+			})
+			node := makeNodeFromNodeResourceTopology(tt.nrt)
+			// the kubelet already reports the device under its new name
+			node.Status.Allocatable[devResourceName] = resource.MustParse("4")
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
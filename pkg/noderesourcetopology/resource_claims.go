@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	v1 "k8s.io/api/core/v1"
+	resourcelisters "k8s.io/client-go/listers/resource/v1alpha2"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+)
+
+// AnnotationClaimNUMANodes is the comma-separated list of the NUMA node IDs the devices allocated to a
+// ResourceClaim are local to, e.g. "1". Expected to be set by the DRA driver when allocating the claim,
+// because the allocation results don't carry the NUMA locality in a structured form yet.
+const AnnotationClaimNUMANodes = AnnotationKeyPrefix + "claim-numa-nodes"
+
+// WithResourceClaimLister makes the filter check that the NUMA locality of the devices allocated to the
+// ResourceClaims of the pod is consistent with its other NUMA-aligned resources. The check is read-only:
+// the claims not allocated yet, or not reporting their NUMA locality, are ignored.
+func WithResourceClaimLister(lister resourcelisters.ResourceClaimLister) Option {
+	return func(tm *TopologyMatch) {
+		tm.claimLister = lister
+	}
+}
+
+// claimedNUMANodes returns the NUMA nodes local to the devices of all the allocated ResourceClaims of the pod
+// usable on the node. Returns false if no such claim reports its NUMA locality.
+func (tm *TopologyMatch) claimedNUMANodes(pod *v1.Pod, node *v1.Node) (map[int]bool, bool) {
+	if tm.claimLister == nil {
+		return nil, false
+	}
+	var numaIDs map[int]bool
+	for _, podClaim := range pod.Spec.ResourceClaims {
+		claimName, ok := resourceClaimName(pod, podClaim)
+		if !ok {
+			continue
+		}
+		claim, err := tm.claimLister.ResourceClaims(pod.Namespace).Get(claimName)
+		if err != nil {
+			klog.V(5).InfoS("cannot get resource claim", "pod", klog.KObj(pod), "claim", claimName, "err", err)
+			continue
+		}
+		if claim.Status.Allocation == nil {
+			continue
+		}
+		if sel := claim.Status.Allocation.AvailableOnNodes; sel != nil {
+			if matches, err := corev1helpers.MatchNodeSelectorTerms(node, sel); err != nil || !matches {
+				continue
+			}
+		}
+		val, ok := claim.Annotations[AnnotationClaimNUMANodes]
+		if !ok {
+			continue
+		}
+		ids, err := parseNUMANodeIDs(val)
+		if err != nil || len(ids) == 0 {
+			klog.V(2).InfoS("ignoring malformed annotation", "claim", klog.KObj(claim), "annotation", AnnotationClaimNUMANodes, "value", val)
+			continue
+		}
+		claimNUMAIDs := make(map[int]bool, len(ids))
+		for _, id := range ids {
+			claimNUMAIDs[id] = true
+		}
+		if numaIDs == nil {
+			numaIDs = claimNUMAIDs
+			continue
+		}
+		// all the claimed devices must be local to the NUMA node the pod is aligned on
+		for id := range numaIDs {
+			if !claimNUMAIDs[id] {
+				delete(numaIDs, id)
+			}
+		}
+	}
+	return numaIDs, numaIDs != nil
+}
+
+// resourceClaimName returns the name of the ResourceClaim object backing the claim of the pod.
+// Returns false if the claim generated from a template doesn't exist yet, or was not needed.
+func resourceClaimName(pod *v1.Pod, podClaim v1.PodResourceClaim) (string, bool) {
+	if podClaim.Source.ResourceClaimName != nil {
+		return *podClaim.Source.ResourceClaimName, true
+	}
+	for _, status := range pod.Status.ResourceClaimStatuses {
+		if status.Name == podClaim.Name && status.ResourceClaimName != nil {
+			return *status.ResourceClaimName, true
+		}
+	}
+	return "", false
+}
+
+// excludeNonLocalNUMANodes adds to the excluded NUMA nodes the ones not local to the devices of the pod, for the given reason.
+func excludeNonLocalNUMANodes(excluded map[int]string, numaNodes NUMANodeList, local map[int]bool, reason string) map[int]string {
+	for _, numaNode := range numaNodes {
+		if local[numaNode.NUMAID] {
+			continue
+		}
+		if _, ok := excluded[numaNode.NUMAID]; ok {
+			continue
+		}
+		if excluded == nil {
+			excluded = make(map[int]string)
+		}
+		excluded[numaNode.NUMAID] = reason
+	}
+	return excluded
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	resourcev1alpha2 "k8s.io/api/resource/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterResourceClaimsNUMALocality(t *testing.T) {
+	makeNRT := func(name, numa1CPUs string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", numa1CPUs),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	// only the NUMA node 0 of "busy" can host the pod
+	roomy := makeNRT("roomy", "4")
+	busy := makeNRT("busy", "1")
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{roomy, busy} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	makeClaim := func(name, numaNodes string, allocated bool) *resourcev1alpha2.ResourceClaim {
+		claim := &resourcev1alpha2.ResourceClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      name,
+			},
+		}
+		if numaNodes != "" {
+			claim.Annotations = map[string]string{AnnotationClaimNUMANodes: numaNodes}
+		}
+		if allocated {
+			claim.Status.Allocation = &resourcev1alpha2.AllocationResult{}
+		}
+		return claim
+	}
+	elsewhere := makeClaim("elsewhere", "1", true)
+	elsewhere.Status.Allocation.AvailableOnNodes = &v1.NodeSelector{
+		NodeSelectorTerms: []v1.NodeSelectorTerm{
+			{
+				MatchFields: []v1.NodeSelectorRequirement{
+					{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"other"}},
+				},
+			},
+		},
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	claimInformer := informerFactory.Resource().V1alpha2().ResourceClaims()
+	for _, claim := range []*resourcev1alpha2.ResourceClaim{
+		makeClaim("numa1", "1", true),
+		makeClaim("numa0", "0", true),
+		makeClaim("pending", "1", false),
+		makeClaim("unannotated", "", true),
+		elsewhere,
+	} {
+		if err := claimInformer.Informer().GetIndexer().Add(claim); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		claims     []string
+		noLister   bool
+		wantStatus *framework.Status
+	}{
+		{
+			name:     "claims not checked",
+			nrt:      busy,
+			claims:   []string{"numa1"},
+			noLister: true,
+		},
+		{
+			name:   "claimed NUMA node fits the pod",
+			nrt:    roomy,
+			claims: []string{"numa1"},
+		},
+		{
+			name:       "claimed NUMA node does not fit the pod",
+			nrt:        busy,
+			claims:     []string{"numa1"},
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:   "claim pinned to the NUMA node fitting the pod",
+			nrt:    busy,
+			claims: []string{"numa0"},
+		},
+		{
+			name:       "claims local to different NUMA nodes",
+			nrt:        roomy,
+			claims:     []string{"numa0", "numa1"},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "claimed devices are local to different NUMA nodes"),
+		},
+		{
+			name:   "claim not allocated yet",
+			nrt:    busy,
+			claims: []string{"pending"},
+		},
+		{
+			name:   "claim without NUMA locality",
+			nrt:    busy,
+			claims: []string{"unannotated"},
+		},
+		{
+			name:   "claim allocated for another node",
+			nrt:    busy,
+			claims: []string{"elsewhere"},
+		},
+		{
+			name:   "claim not found",
+			nrt:    busy,
+			claims: []string{"missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			})
+			pod.Namespace = "ns1"
+			for _, claimName := range tt.claims {
+				claimName := claimName
+				pod.Spec.ResourceClaims = append(pod.Spec.ResourceClaims, v1.PodResourceClaim{
+					Name:   "claim-" + claimName,
+					Source: v1.ClaimSource{ResourceClaimName: &claimName},
+				})
+			}
+
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			if !tt.noLister {
+				WithResourceClaimLister(claimInformer.Lister())(&tm)
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestResourceClaimName(t *testing.T) {
+	claimName := "my-claim"
+	generatedName := "pod-gpu-abcde"
+	pod := &v1.Pod{
+		Status: v1.PodStatus{
+			ResourceClaimStatuses: []v1.PodResourceClaimStatus{
+				{Name: "generated", ResourceClaimName: &generatedName},
+				{Name: "unneeded"},
+			},
+		},
+	}
+
+	tests := []struct {
+		podClaim v1.PodResourceClaim
+		expected string
+		found    bool
+	}{
+		{
+			podClaim: v1.PodResourceClaim{Name: "direct", Source: v1.ClaimSource{ResourceClaimName: &claimName}},
+			expected: claimName,
+			found:    true,
+		},
+		{
+			podClaim: v1.PodResourceClaim{Name: "generated"},
+			expected: generatedName,
+			found:    true,
+		},
+		{
+			podClaim: v1.PodResourceClaim{Name: "unneeded"},
+		},
+		{
+			podClaim: v1.PodResourceClaim{Name: "not-generated-yet"},
+		},
+	}
+
+	for _, tt := range tests {
+		got, found := resourceClaimName(pod, tt.podClaim)
+		if got != tt.expected || found != tt.found {
+			t.Errorf("claim %q: got=%q,%v expected=%q,%v", tt.podClaim.Name, got, found, tt.expected, tt.found)
+		}
+	}
+}
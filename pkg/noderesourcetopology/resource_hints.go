@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// maxResourceNameDistance is the maximum edit distance between the names of two resources for them to be considered similar.
+const maxResourceNameDistance = 2
+
+// similarResourceName returns, in name order, the first resource among the available ones whose name is similar to the
+// requested one, that is either the same resource from a different vendor, like "amd.com/gpu" for "nvidia.com/gpu",
+// or a likely misspelling of it.
+func similarResourceName(resName v1.ResourceName, available v1.ResourceList) (v1.ResourceName, bool) {
+	for _, candidate := range sortedResourceNames(available) {
+		if candidate == resName {
+			continue
+		}
+		if isSameResourceFromOtherVendor(resName, candidate) ||
+			editDistance(strings.ToLower(string(resName)), strings.ToLower(string(candidate))) <= maxResourceNameDistance {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// isSameResourceFromOtherVendor returns true if both names are prefixed by a domain and are otherwise the same.
+func isSameResourceFromOtherVendor(resName, candidate v1.ResourceName) bool {
+	domain, name, ok := strings.Cut(string(resName), "/")
+	if !ok {
+		return false
+	}
+	candidateDomain, candidateName, ok := strings.Cut(string(candidate), "/")
+	if !ok {
+		return false
+	}
+	return domain != candidateDomain && strings.EqualFold(name, candidateName)
+}
+
+// editDistance returns the Levenshtein distance between the given strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	res := values[0]
+	for _, val := range values[1:] {
+		if val < res {
+			res = val
+		}
+	}
+	return res
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestSimilarResourceName(t *testing.T) {
+	available := v1.ResourceList{
+		v1.ResourceCPU:               resource.MustParse("4"),
+		v1.ResourceMemory:            resource.MustParse("8Gi"),
+		"amd.com/gpu":                resource.MustParse("2"),
+		"example.com/infiniband-hca": resource.MustParse("1"),
+	}
+	tests := []struct {
+		name          string
+		resName       v1.ResourceName
+		expected      v1.ResourceName
+		expectedFound bool
+	}{
+		{
+			name:          "same device from another vendor",
+			resName:       "nvidia.com/gpu",
+			expected:      "amd.com/gpu",
+			expectedFound: true,
+		},
+		{
+			name:          "misspelling",
+			resName:       "example.com/infiniband-hcas",
+			expected:      "example.com/infiniband-hca",
+			expectedFound: true,
+		},
+		{
+			name:          "misspelling, different case",
+			resName:       "Example.com/Infiniband-HCA",
+			expected:      "example.com/infiniband-hca",
+			expectedFound: true,
+		},
+		{
+			name:    "unrelated resource",
+			resName: "example.com/fpga",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := similarResourceName(tt.resName, available)
+			if got != tt.expected || found != tt.expectedFound {
+				t.Errorf("got=%q,%v expected=%q,%v", got, found, tt.expected, tt.expectedFound)
+			}
+		})
+	}
+}
+
+func TestFilterResourceNameHints(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo("amd.com/gpu", "2", "2"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+		"nvidia.com/gpu":  resource.MustParse("1"),
+	})
+
+	tests := []struct {
+		name       string
+		hints      bool
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "hints disabled",
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "node has no nvidia.com/gpu resource"),
+		},
+		{
+			name:       "hints enabled",
+			hints:      true,
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "node has no nvidia.com/gpu resource, but has the similar amd.com/gpu resource"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				resourceNameHints: tt.hints,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
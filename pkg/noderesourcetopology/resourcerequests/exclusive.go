@@ -40,6 +40,26 @@ func IncludeNonNative(pod *corev1.Pod) bool {
 	}
 	return false
 }
+
+// AreZeroForPod returns true if neither the containers nor the overhead of the pod request any resource.
+func AreZeroForPod(pod *corev1.Pod) bool {
+	for _, ctr := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if !isZero(ctr.Resources.Requests) {
+			return false
+		}
+	}
+	return isZero(pod.Spec.Overhead)
+}
+
+func isZero(resources corev1.ResourceList) bool {
+	for _, quantity := range resources {
+		if !quantity.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
 func AreExclusiveForPod(pod *corev1.Pod) bool {
 	qos := v1qos.GetPodQOS(pod)
 	return areExclusiveForAnyContainer(qos, append(pod.Spec.InitContainers, pod.Spec.Containers...))
@@ -55,6 +55,85 @@ func TestAreExclusiveForPod(t *testing.T) {
 	}
 }
 
+func TestAreZeroForPod(t *testing.T) {
+	makePod := func(init, app corev1.ResourceList, overhead corev1.ResourceList) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod",
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					{
+						Name:      "init",
+						Resources: corev1.ResourceRequirements{Requests: init},
+					},
+				},
+				Containers: []corev1.Container{
+					{
+						Name: "app",
+						Resources: corev1.ResourceRequirements{
+							Requests: app,
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("2"),
+							},
+						},
+					},
+				},
+				Overhead: overhead,
+			},
+		}
+	}
+
+	tcases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected bool
+	}{
+		{
+			name:     "no requests",
+			pod:      makePod(nil, nil, nil),
+			expected: true,
+		},
+		{
+			name: "zero requests",
+			pod: makePod(nil, corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("0"),
+			}, nil),
+			expected: true,
+		},
+		{
+			name: "init container requests",
+			pod: makePod(corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}, nil, nil),
+			expected: false,
+		},
+		{
+			name: "app container requests",
+			pod: makePod(nil, corev1.ResourceList{
+				"vendor.com/gpu": resource.MustParse("1"),
+			}, nil),
+			expected: false,
+		},
+		{
+			name: "overhead only",
+			pod: makePod(nil, nil, corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("250m"),
+			}),
+			expected: false,
+		},
+	}
+	for _, tt := range tcases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AreZeroForPod(tt.pod)
+			if got != tt.expected {
+				t.Errorf("%s: zero requests detected %v expected %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
 func coreTestCases() []testCase {
 	return []testCase{
 		{
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// AnnotationSameSocketResources is a comma-separated list of resources the pod prefers to get from NUMA nodes of the same
+// socket, e.g. "nvidia.com/gpu,vendor.com/nic" for a workload moving data between its GPU and its NIC.
+const AnnotationSameSocketResources = AnnotationKeyPrefix + "same-socket-resources"
+
+// sameSocketResourcesComponent scores the ability to place the resources the pod lists on NUMA nodes of the same socket.
+// It only applies to the pods listing at least two resources they request.
+func (tm *TopologyMatch) sameSocketResourcesComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	val, ok := pod.Annotations[AnnotationSameSocketResources]
+	if !ok {
+		return 0, false, nil
+	}
+	requests := v1.ResourceList{}
+	podRequests := util.GetPodEffectiveRequest(pod)
+	for _, resName := range parseResourceNames(pod, AnnotationSameSocketResources, val) {
+		if quantity, ok := podRequests[resName]; ok && !quantity.IsZero() {
+			requests[resName] = quantity
+		}
+	}
+	if len(requests) < 2 {
+		return 0, false, nil
+	}
+	return sameSocketResourcesScore(numaNodeSockets(zones), createNUMANodeList(zones), requests), true, nil
+}
+
+// sameSocketResourcesScore gives the maximum score if a socket has, for each of the requests, a NUMA node fitting it,
+// not necessarily the same one, and the minimum score otherwise. The nodes not reporting the sockets of their NUMA
+// nodes get a neutral score. The requests of the resources not reported per NUMA node don't constrain the socket.
+func sameSocketResourcesScore(numaSockets map[int]string, numaNodes NUMANodeList, requests v1.ResourceList) int64 {
+	if numaSockets == nil {
+		return neutralNodeScore
+	}
+	requests = numaAffineResources(requests, numaNodes)
+	// socket name -> resources fitting in one of its NUMA nodes
+	fitting := make(map[string]map[v1.ResourceName]bool)
+	for _, numaNode := range numaNodes {
+		socket, ok := numaSockets[numaNode.NUMAID]
+		if !ok {
+			continue
+		}
+		for resName, quantity := range requests {
+			available, ok := numaNode.Resources[resName]
+			if !ok || available.Cmp(quantity) < 0 {
+				continue
+			}
+			if fitting[socket] == nil {
+				fitting[socket] = make(map[v1.ResourceName]bool)
+			}
+			fitting[socket][resName] = true
+		}
+	}
+	for _, resources := range fitting {
+		if len(resources) == len(requests) {
+			return framework.MaxNodeScore
+		}
+	}
+	return framework.MinNodeScore
+}
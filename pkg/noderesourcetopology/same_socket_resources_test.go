@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestScoreSameSocketResources(t *testing.T) {
+	const gpu = "vendor.com/gpu"
+
+	withDevice := func(zone topologyv1alpha2.Zone, resName string) topologyv1alpha2.Zone {
+		zone.Resources = append(zone.Resources, MakeTopologyResInfo(resName, "1", "1"))
+		return zone
+	}
+	// the GPU is on the NUMA node 0, the NIC on the NUMA node 1 or 2, the NUMA nodes 0 and 1 sharing the socket 0
+	nodes := map[string]topologyv1alpha2.ZoneList{
+		"same-socket": {
+			withDevice(makeSocketZone("node-0", "socket-0", "8", "8"), gpu),
+			withDevice(makeSocketZone("node-1", "socket-0", "8", "8"), nicResourceName),
+			makeSocketZone("node-2", "socket-1", "8", "8"),
+		},
+		"cross-socket": {
+			withDevice(makeSocketZone("node-0", "socket-0", "8", "8"), gpu),
+			makeSocketZone("node-1", "socket-0", "8", "8"),
+			withDevice(makeSocketZone("node-2", "socket-1", "8", "8"), nicResourceName),
+		},
+		"no-sockets": {
+			withDevice(makeSocketZone("node-0", "", "8", "8"), gpu),
+			withDevice(makeSocketZone("node-1", "", "8", "8"), nicResourceName),
+			makeSocketZone("node-2", "", "8", "8"),
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for nodeName, zones := range nodes {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: nodeName},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+			Zones:            zones,
+		}
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		annotation string
+		weight     int64
+		expected   nodeToScoreMap
+	}{
+		{
+			name:       "disabled",
+			annotation: gpu + "," + nicResourceName,
+			expected:   nodeToScoreMap{"same-socket": 6, "cross-socket": 6, "no-sockets": 6},
+		},
+		{
+			name:     "no same-socket resources",
+			weight:   100,
+			expected: nodeToScoreMap{"same-socket": 6, "cross-socket": 6, "no-sockets": 6},
+		},
+		{
+			name:       "single same-socket resource",
+			annotation: gpu,
+			weight:     100,
+			expected:   nodeToScoreMap{"same-socket": 6, "cross-socket": 6, "no-sockets": 6},
+		},
+		{
+			name:       "same-socket resources",
+			annotation: gpu + "," + nicResourceName,
+			weight:     100,
+			expected:   nodeToScoreMap{"same-socket": 100, "cross-socket": 0, "no-sockets": 50},
+		},
+		{
+			name:       "same-socket resources blended",
+			annotation: gpu + "," + nicResourceName,
+			weight:     50,
+			expected:   nodeToScoreMap{"same-socket": 53, "cross-socket": 3, "no-sockets": 28},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceLists(
+				v1.ResourceList{
+					v1.ResourceCPU:       resource.MustParse("1"),
+					v1.ResourceMemory:    resource.MustParse("1Gi"),
+					v1.ResourceName(gpu): resource.MustParse("1"),
+				},
+				v1.ResourceList{
+					v1.ResourceCPU:                   resource.MustParse("1"),
+					v1.ResourceMemory:                resource.MustParse("1Gi"),
+					v1.ResourceName(nicResourceName): resource.MustParse("1"),
+				},
+			)
+			if tt.annotation != "" {
+				pod.Annotations = map[string]string{AnnotationSameSocketResources: tt.annotation}
+			}
+			tm := &TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc: mostAllocatedScoreStrategy,
+				scoreStrategyType: apiconfig.MostAllocated,
+				sameSocketWeight:  tt.weight,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+var alignableReferencePodsTotal = metrics.NewGauge(
+	&metrics.GaugeOpts{
+		Subsystem:      "scheduler_plugins",
+		Name:           "noderesourcetopology_alignable_reference_pods",
+		Help:           "Number of reference pods which could still be aligned on the nodes, as computed by the last schedulability report.",
+		StabilityLevel: metrics.ALPHA,
+	},
+)
+
+var feasibleReferencePods = metrics.NewGaugeVec(
+	&metrics.GaugeOpts{
+		Subsystem:      "scheduler_plugins",
+		Name:           "nrt_feasible_reference_pods",
+		Help:           "Number of reference pods which could still be aligned on each node, as computed by the last schedulability report.",
+		StabilityLevel: metrics.ALPHA,
+	},
+	[]string{"node"},
+)
+
+// nodeSchedulability tells how many reference pods could still be aligned on a node.
+type nodeSchedulability struct {
+	nodeName      string
+	alignablePods int64
+}
+
+// startSchedulabilityReport periodically reports, off the scheduling path, the NUMA-aligned capacity left on the nodes.
+func startSchedulabilityReport(tm *TopologyMatch, report *apiconfig.SchedulabilityReport, handle framework.Handle) {
+	registerMetrics()
+	// the informer and the lister must be requested before the informer factory is started
+	nodes := handle.SharedInformerFactory().Core().V1().Nodes()
+	nodeInformer, nodeLister := nodes.Informer(), nodes.Lister()
+	referencePod := makeReferencePod(report.ReferencePodRequests)
+	period := time.Duration(report.PeriodSeconds) * time.Second
+	klog.InfoS("Enabling the schedulability report", "period", period, "referencePodRequests", report.ReferencePodRequests)
+	go tm.runSchedulabilityReport(nodeInformer, nodeLister, referencePod, period)
+}
+
+// runSchedulabilityReport reports the schedulability of the nodes every period, until the node informer is stopped.
+// The scheduler stops its informers when it exits, so the report doesn't outlive it.
+func (tm *TopologyMatch) runSchedulabilityReport(nodeInformer k8scache.SharedInformer, nodeLister corelisters.NodeLister, referencePod *v1.Pod, period time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if nodeInformer.IsStopped() {
+			klog.V(2).InfoS("Stopping the schedulability report")
+			cancel()
+			return
+		}
+		tm.reportSchedulability(ctx, nodeLister, referencePod)
+	}, period)
+}
+
+func makeReferencePod(requests v1.ResourceList) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "schedulability-report-reference",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "reference",
+					Resources: v1.ResourceRequirements{
+						Requests: requests.DeepCopy(),
+						Limits:   requests.DeepCopy(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (tm *TopologyMatch) reportSchedulability(ctx context.Context, nodeLister corelisters.NodeLister, referencePod *v1.Pod) {
+	nodes, err := nodeLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "cannot list the nodes for the schedulability report")
+		return
+	}
+	nodeNames := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		nodeNames = append(nodeNames, node.Name)
+	}
+
+	var total int64
+	report := tm.schedulabilityReport(ctx, nodeNames, referencePod)
+	// drop the nodes gone or no longer reported since the last report
+	feasibleReferencePods.Reset()
+	for _, item := range report {
+		klog.V(4).InfoS("schedulability report", "node", item.nodeName, "alignableReferencePods", item.alignablePods)
+		feasibleReferencePods.WithLabelValues(item.nodeName).Set(float64(item.alignablePods))
+		total += item.alignablePods
+	}
+	klog.V(2).InfoS("schedulability report", "nodes", len(report), "alignableReferencePods", total)
+	alignableReferencePodsTotal.Set(float64(total))
+}
+
+// schedulabilityReport computes, for the given nodes, how many reference pods could still be aligned on each of them
+// given the cached NRT data. The nodes the plugin doesn't align pods on, because they have no NRT data or don't use
+// the single-numa-node policy, are not part of the report. The result is sorted by node name.
+func (tm *TopologyMatch) schedulabilityReport(ctx context.Context, nodeNames []string, referencePod *v1.Pod) []nodeSchedulability {
+	var report []nodeSchedulability
+	for _, nodeName := range nodeNames {
+		nodeTopology, ok := tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, referencePod)
+		if !ok {
+			// the node is waiting for a resync, so no pod can be placed on it right now
+			report = append(report, nodeSchedulability{nodeName: nodeName})
+			continue
+		}
+		if nodeTopology == nil || tm.isMisconfigured(nodeTopology) {
+			continue
+		}
+		if filterHandlerFromTopologyManagerConfig(tm.topologyManagerConfig(nodeTopology)) == nil {
+			continue
+		}
+		// the filter observes the NRT updates, the report only reads the outcome
+		if tm.quarantine != nil && tm.quarantine.quarantined(nodeName) {
+			report = append(report, nodeSchedulability{nodeName: nodeName})
+			continue
+		}
+
+		numaNodes := createNUMANodeList(nodeTopology.Zones)
+		excluded := untoleratedNUMANodes(referencePod, nodeTopology.Zones)
+		requests := numaAffineResources(referencePod.Spec.Containers[0].Resources.Requests, numaNodes)
+		var alignable int64
+		for _, numaNode := range numaNodes {
+			if _, ok := excluded[numaNode.NUMAID]; ok {
+				continue
+			}
+			alignable += referencePodsFitting(requests, numaNode.Resources)
+		}
+		report = append(report, nodeSchedulability{nodeName: nodeName, alignablePods: alignable})
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].nodeName < report[j].nodeName
+	})
+	return report
+}
+
+// referencePodsFitting returns how many pods with the given requests fit in the available resources of a NUMA node.
+func referencePodsFitting(requests, available v1.ResourceList) int64 {
+	fitting := int64(-1)
+	for resName, quantity := range requests {
+		if quantity.Sign() <= 0 {
+			continue
+		}
+		numaQuantity, ok := available[resName]
+		if !ok || numaQuantity.Sign() <= 0 {
+			return 0
+		}
+		if count := numaQuantity.MilliValue() / quantity.MilliValue(); fitting < 0 || count < fitting {
+			fitting = count
+		}
+	}
+	if fitting < 0 {
+		// none of the requests is NUMA-affine: nothing to align
+		return 0
+	}
+	return fitting
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/metrics/testutil"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestReferencePodsFitting(t *testing.T) {
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	}
+	tests := []struct {
+		name      string
+		available v1.ResourceList
+		expected  int64
+	}{
+		{
+			name: "cpu bound",
+			available: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("5"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			expected: 2,
+		},
+		{
+			name: "memory bound",
+			available: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("8"),
+				v1.ResourceMemory: resource.MustParse("6Gi"),
+			},
+			expected: 1,
+		},
+		{
+			name: "not enough room",
+			available: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("1500m"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			expected: 0,
+		},
+		{
+			name: "resource missing",
+			available: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("8"),
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := referencePodsFitting(requests, tt.available)
+			if got != tt.expected {
+				t.Errorf("fitting=%d expected=%d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSchedulabilityReport(t *testing.T) {
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy, numa0, numa1 []string, numa0Attrs topologyv1alpha2.AttributeList) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, numa0[0], numa0[0]),
+						MakeTopologyResInfo(memory, numa0[1], numa0[1]),
+					},
+					Attributes: numa0Attrs,
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, numa1[0], numa1[0]),
+						MakeTopologyResInfo(memory, numa1[1], numa1[1]),
+					},
+				},
+			},
+		}
+	}
+	cordoned := topologyv1alpha2.AttributeList{
+		{Name: ZoneAttributeUnschedulable, Value: "true"},
+	}
+
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("roomy", topologyv1alpha2.SingleNUMANodePodLevel, []string{"8", "16Gi"}, []string{"5", "4Gi"}, nil),
+		makeNRT("full", topologyv1alpha2.SingleNUMANodeContainerLevel, []string{"1", "16Gi"}, []string{"1", "16Gi"}, nil),
+		makeNRT("cordoned", topologyv1alpha2.SingleNUMANodePodLevel, []string{"8", "16Gi"}, []string{"4", "8Gi"}, cordoned),
+		makeNRT("unaligned", topologyv1alpha2.None, []string{"8", "16Gi"}, []string{"8", "16Gi"}, nil),
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tm := &TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+	}
+	referencePod := makeReferencePod(v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+
+	// "unaligned" doesn't use the single-numa-node policy, "missing" has no NRT data
+	nodeNames := []string{"unaligned", "roomy", "missing", "full", "cordoned"}
+	got := tm.schedulabilityReport(context.Background(), nodeNames, referencePod)
+	expected := []nodeSchedulability{
+		{nodeName: "cordoned", alignablePods: 2},
+		{nodeName: "full", alignablePods: 0},
+		// 4 pods on the first NUMA node, bound by the cpu, and 1 on the second one, bound by the memory
+		{nodeName: "roomy", alignablePods: 5},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("report=%+v expected=%+v", got, expected)
+	}
+}
+
+func TestFeasibleReferencePodsMetric(t *testing.T) {
+	registerMetrics()
+
+	makeNRT := func(name, numa0CPU, numa1CPU string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", numa0CPU),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", numa1CPU),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+			},
+		}
+	}
+	// "fragmented" has 3 free CPUs, enough for a reference pod at node level, but split across its NUMA nodes
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("fragmented", "1500m", "1500m"),
+		makeNRT("roomy", "4", "3"),
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+		if err := indexer.Add(makeNodeFromNodeResourceTopology(nrt)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tm := &TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+	}
+	referencePod := makeReferencePod(v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+	tm.reportSchedulability(context.Background(), corelisters.NewNodeLister(indexer), referencePod)
+
+	expected := map[string]float64{
+		"fragmented": 0,
+		// 2 pods on the first NUMA node and 1 on the second one, bound by the cpu
+		"roomy": 3,
+	}
+	for nodeName, value := range expected {
+		got, err := testutil.GetGaugeMetricValue(feasibleReferencePods.WithLabelValues(nodeName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != value {
+			t.Errorf("feasible reference pods on %s=%v expected=%v", nodeName, got, value)
+		}
+	}
+}
+
+func TestSchedulabilityReportLeavesQuarantine(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "observed"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "8", "8"),
+					MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := &TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+		quarantine: newNodeQuarantine(&apiconfig.NodeQuarantine{
+			Threshold:         1,
+			RecoveryThreshold: 1,
+		}),
+	}
+	referencePod := makeReferencePod(v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+	tm.schedulabilityReport(context.Background(), []string{nrt.Name}, referencePod)
+	if _, ok := tm.quarantine.nodes[nrt.Name]; ok {
+		t.Errorf("the report recorded an observation of the NRT data")
+	}
+
+	tm.quarantine.nodes[nrt.Name] = &quarantineState{quarantined: true}
+	got := tm.schedulabilityReport(context.Background(), []string{nrt.Name}, referencePod)
+	expected := []nodeSchedulability{{nodeName: nrt.Name}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("report=%+v expected=%+v", got, expected)
+	}
+	if state := tm.quarantine.nodes[nrt.Name]; *state != (quarantineState{quarantined: true}) {
+		t.Errorf("the report changed the quarantine state: %+v", state)
+	}
+}
+
+func TestSchedulabilityReportStops(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	nodes := informerFactory.Core().V1().Nodes()
+	nodeInformer, nodeLister := nodes.Informer(), nodes.Lister()
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+
+	tm := &TopologyMatch{}
+	referencePod := makeReferencePod(v1.ResourceList{
+		v1.ResourceCPU: resource.MustParse("2"),
+	})
+	done := make(chan struct{})
+	go func() {
+		tm.runSchedulabilityReport(nodeInformer, nodeLister, referencePod, 10*time.Millisecond)
+		close(done)
+	}()
+
+	close(stopCh)
+	select {
+	case <-done:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Errorf("the report still runs after the informers are stopped")
+	}
+}
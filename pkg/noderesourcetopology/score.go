@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gonum.org/v1/gonum/stat"
 
 	v1 "k8s.io/api/core/v1"
@@ -39,6 +41,109 @@ const (
 
 type scoreStrategyFn func(v1.ResourceList, v1.ResourceList, resourceToWeightMap) int64
 
+// scoreComponentFn computes a component of the node score, from 0 to MaxNodeScore, to blend with the score computed
+// by the scoring strategy. Returns false if the component doesn't apply to the pod or to the node.
+type scoreComponentFn func(tm *TopologyMatch, pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status)
+
+// weightedScoreComponent is a score component, and the percentage of the node score it accounts for.
+type weightedScoreComponent struct {
+	name      string
+	weight    func(tm *TopologyMatch) int64
+	component scoreComponentFn
+	// anyQoS is set if the component applies to the pods of any QoS class, not only to the Guaranteed ones,
+	// which are the only ones the kubelet aligns on NUMA nodes
+	anyQoS bool
+}
+
+// weightedScoreComponents lists the score components, in the order they are blended. The components with zero weight,
+// or not applying to the pod or to the node, leave the score as is.
+var weightedScoreComponents = []weightedScoreComponent{
+	{
+		name:      "nodeHeadroom",
+		weight:    func(tm *TopologyMatch) int64 { return tm.nodeHeadroomWeight },
+		component: (*TopologyMatch).nodeHeadroomComponent,
+		anyQoS:    true,
+	},
+	{
+		name:      "numaSpread",
+		weight:    func(tm *TopologyMatch) int64 { return tm.numaSpreadWeight },
+		component: (*TopologyMatch).numaSpreadComponent,
+	},
+	{
+		name:      "ownerNUMALocality",
+		weight:    func(tm *TopologyMatch) int64 { return tm.ownerNUMALocalityWeight },
+		component: (*TopologyMatch).ownerNUMALocalityComponent,
+	},
+	{
+		name:      "stability",
+		weight:    func(tm *TopologyMatch) int64 { return tm.stabilityWeight },
+		component: (*TopologyMatch).stabilityComponent,
+	},
+	{
+		name:      "deviceCPUBalance",
+		weight:    (*TopologyMatch).deviceCPUBalanceWeight,
+		component: (*TopologyMatch).deviceCPUBalanceComponent,
+	},
+	{
+		name:      "deviceHintConflict",
+		weight:    func(tm *TopologyMatch) int64 { return tm.deviceHintConflictWeight },
+		component: (*TopologyMatch).deviceHintConflictComponent,
+	},
+	{
+		name:      "stickyNUMA",
+		weight:    func(tm *TopologyMatch) int64 { return tm.stickyNUMAWeight },
+		component: (*TopologyMatch).stickyNUMAComponent,
+	},
+	{
+		name:      "gatedNUMAPreference",
+		weight:    func(tm *TopologyMatch) int64 { return tm.gatedNUMAPrefWeight },
+		component: (*TopologyMatch).gatedNUMAPreferenceComponent,
+	},
+	{
+		name:      "socketFreeness",
+		weight:    func(tm *TopologyMatch) int64 { return tm.socketFreenessWeight },
+		component: (*TopologyMatch).socketFreenessComponent,
+	},
+	{
+		name:      "sameSocketResources",
+		weight:    func(tm *TopologyMatch) int64 { return tm.sameSocketWeight },
+		component: (*TopologyMatch).sameSocketResourcesComponent,
+	},
+	{
+		name:      "degradedNUMA",
+		weight:    func(tm *TopologyMatch) int64 { return tm.degradedNUMAWeight },
+		component: (*TopologyMatch).degradedNUMAComponent,
+	},
+}
+
+// blend returns the score with the given percentage of it replaced by the component.
+func blend(score, component, weight int64) int64 {
+	return (score*(100-weight) + component*weight) / 100
+}
+
+// blendScoreComponents blends the score computed by the scoring strategy with the weighted score components.
+// Only the components applying to any QoS class are blended for the pods which are not Guaranteed.
+func (tm *TopologyMatch) blendScoreComponents(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList, score int64) (int64, *framework.Status) {
+	guaranteed := v1qos.GetPodQOS(pod) == v1.PodQOSGuaranteed
+	for _, wc := range weightedScoreComponents {
+		weight := wc.weight(tm)
+		if weight == 0 || (!guaranteed && !wc.anyQoS) {
+			continue
+		}
+		component, ok, status := wc.component(tm, pod, nodeName, zones)
+		if !status.IsSuccess() {
+			return 0, status
+		}
+		if !ok {
+			continue
+		}
+		blended := blend(score, component, weight)
+		klog.V(6).InfoS("score component", "component", wc.name, "pod", klog.KObj(pod), "node", nodeName, "score", score, "componentScore", component, "blendedScore", blended)
+		score = blended
+	}
+	return score, nil
+}
+
 // resourceToWeightMap contains resource name and weight.
 type resourceToWeightMap map[v1.ResourceName]int64
 
@@ -57,10 +162,19 @@ func (rw resourceToWeightMap) weight(r v1.ResourceName) int64 {
 }
 
 func (tm *TopologyMatch) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	ctx, span := tm.startSpan(ctx, "Score", nodeName)
+	defer span.End()
+	score, status := tm.score(ctx, state, pod, nodeName, span)
+	span.SetAttributes(attribute.Int64(spanAttrScore, score))
+	setSpanVerdict(span, status)
+	return score, status
+}
+
+func (tm *TopologyMatch) score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string, span trace.Span) (int64, *framework.Status) {
 	klog.V(6).InfoS("scoring node", "nodeName", nodeName)
-	// if it's a non-guaranteed pod, every node is considered to be a good fit
+	// if it's a non-guaranteed pod, every node is considered to be a good fit, but for the components not about the alignment
 	if v1qos.GetPodQOS(pod) != v1.PodQOSGuaranteed {
-		return framework.MaxNodeScore, nil
+		return tm.nonGuaranteedScore(ctx, pod, nodeName)
 	}
 
 	nodeTopology, ok := tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, pod)
@@ -73,28 +187,72 @@ func (tm *TopologyMatch) Score(ctx context.Context, state *framework.CycleState,
 		klog.V(5).InfoS("noderesourcetopology was not found for node", "node", nodeName)
 		return 0, nil
 	}
+	if tm.isMisconfigured(nodeTopology) {
+		klog.V(4).InfoS("noderesourcetopology has conflicting topology policies for node", "node", nodeName, "policies", nodeTopology.TopologyPolicies)
+		return 0, nil
+	}
 
 	logNRT("noderesourcetopology found", nodeTopology)
 
-	handler := tm.scoringHandlerFromTopologyManagerConfig(topologyManagerConfigFromNodeResourceTopology(nodeTopology))
-	if handler == nil {
-		return 0, nil
+	conf := tm.topologyManagerConfig(nodeTopology)
+	setSpanConfig(span, conf)
+
+	var score int64
+	var status *framework.Status
+	if prefs := tm.numaPreferencesForPod(pod); prefs.requiresMemorySpread() {
+		// this inverts the usual preference for the narrowest placement
+		score, status = memorySpreadScore(pod, nodeTopology.Zones, prefs), nil
+	} else if tm.scoreStrategyType == apiconfig.InterPodNUMAAffinity {
+		// this strategy needs to know the pods running on the node, not only the NRT data
+		score, status = tm.interPodNUMAAffinityScore(pod, nodeName, nodeTopology)
+	} else {
+		handler := tm.scoringHandlerFromTopologyManagerConfig(conf)
+		if handler == nil {
+			return 0, nil
+		}
+		score, status = handler(pod, nodeTopology.Zones)
+	}
+	if !status.IsSuccess() {
+		return score, status
+	}
+	score, status = tm.blendScoreComponents(pod, nodeName, nodeTopology.Zones, score)
+	if !status.IsSuccess() {
+		return score, status
+	}
+	score = tm.applyDeviceAvoidance(pod, nodeTopology.Zones, score)
+	return applyBestEffortHint(state, pod, nodeName, score), nil
+}
+
+// nonGuaranteedScore scores the node for a pod which is not Guaranteed, hence not aligned by the kubelet: all the nodes
+// are a good fit, only the score components applying to any QoS class and the device avoidance lower the score.
+func (tm *TopologyMatch) nonGuaranteedScore(ctx context.Context, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	var zones topologyv1alpha2.ZoneList
+	nodeTopology, ok := tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, pod)
+	if ok && nodeTopology != nil && !tm.isMisconfigured(nodeTopology) {
+		zones = nodeTopology.Zones
+	}
+	score, status := tm.blendScoreComponents(pod, nodeName, zones, framework.MaxNodeScore)
+	if !status.IsSuccess() {
+		return score, status
 	}
-	return handler(pod, nodeTopology.Zones)
+	return tm.applyDeviceAvoidance(pod, zones, score), nil
 }
 
 func (tm *TopologyMatch) ScoreExtensions() framework.ScoreExtensions {
 	return nil
 }
 
-// scoreForEachNUMANode will iterate over all NUMA zones of the node and invoke the scoreStrategyFn func for every zone.
+// numaScoreFn scores the requested resources against a NUMA node.
+type numaScoreFn func(requested v1.ResourceList, numa NUMANode) int64
+
+// scoreForEachNUMANode will iterate over all NUMA zones of the node and invoke the numaScoreFn func for every zone.
 // it will return the minimal score of all the calculated NUMA's score, in order to avoid edge cases.
-func scoreForEachNUMANode(requested v1.ResourceList, numaList NUMANodeList, score scoreStrategyFn, resourceToWeightMap resourceToWeightMap) int64 {
+func scoreForEachNUMANode(requested v1.ResourceList, numaList NUMANodeList, score numaScoreFn) int64 {
 	numaScores := make([]int64, len(numaList))
 	minScore := int64(0)
 
 	for _, numa := range numaList {
-		numaScore := score(requested, numa.Resources, resourceToWeightMap)
+		numaScore := score(requested, numa)
 		// if NUMA's score is 0, i.e. not fit at all, it won't be taken under consideration by Kubelet.
 		if (minScore == 0) || (numaScore != 0 && numaScore < minScore) {
 			minScore = numaScore
@@ -105,6 +263,19 @@ func scoreForEachNUMANode(requested v1.ResourceList, numaList NUMANodeList, scor
 	return minScore
 }
 
+// numaScorer returns the function scoring the requests against the NUMA nodes described by the zones with the configured strategy.
+func (tm *TopologyMatch) numaScorer(zones topologyv1alpha2.ZoneList) numaScoreFn {
+	if tm.normalizedStrategyFunc != nil {
+		capacities := numaCapacities(zones)
+		return func(requested v1.ResourceList, numa NUMANode) int64 {
+			return tm.normalizedStrategyFunc(requested, numa.Resources, capacities[numa.NUMAID], tm.resourceToWeightMap)
+		}
+	}
+	return func(requested v1.ResourceList, numa NUMANode) int64 {
+		return tm.scoreStrategyFunc(requested, numa.Resources, tm.resourceToWeightMap)
+	}
+}
+
 func getScoringStrategyFunction(strategy apiconfig.ScoringStrategyType) (scoreStrategyFn, error) {
 	switch strategy {
 	case apiconfig.MostAllocated:
@@ -113,27 +284,27 @@ func getScoringStrategyFunction(strategy apiconfig.ScoringStrategyType) (scoreSt
 		return leastAllocatedScoreStrategy, nil
 	case apiconfig.BalancedAllocation:
 		return balancedAllocationScoreStrategy, nil
-	case apiconfig.LeastNUMANodes:
-		// this is a special case handled down the flow. We just need to NOT error out.
+	case apiconfig.LeastNUMANodes, apiconfig.InterPodNUMAAffinity:
+		// these are special cases handled down the flow. We just need to NOT error out.
 		return nil, nil
 	default:
-		return nil, fmt.Errorf("illegal scoring strategy found")
+		return nil, fmt.Errorf("illegal scoring strategy found: %q", strategy)
 	}
 }
 
-func podScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, scorerFn scoreStrategyFn, resourceToWeightMap resourceToWeightMap) (int64, *framework.Status) {
+func podScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, scorerFn numaScoreFn) (int64, *framework.Status) {
 	// This code is in Admit implementation of pod scope
 	// https://github.com/kubernetes/kubernetes/blob/9ff3b7e744b34c099c1405d9add192adbef0b6b1/pkg/kubelet/cm/topologymanager/scope_pod.go#L52
 	// but it works with HintProviders, takes into account all possible allocations.
 	resources := util.GetPodEffectiveRequest(pod)
 
 	allocatablePerNUMA := createNUMANodeList(zones)
-	finalScore := scoreForEachNUMANode(resources, allocatablePerNUMA, scorerFn, resourceToWeightMap)
+	finalScore := scoreForEachNUMANode(resources, allocatablePerNUMA, scorerFn)
 	klog.V(5).InfoS("pod scope scoring final node score", "finalScore", finalScore)
 	return finalScore, nil
 }
 
-func containerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, scorerFn scoreStrategyFn, resourceToWeightMap resourceToWeightMap) (int64, *framework.Status) {
+func containerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, scorerFn numaScoreFn) (int64, *framework.Status) {
 	// This code is in Admit implementation of container scope
 	// https://github.com/kubernetes/kubernetes/blob/9ff3b7e744b34c099c1405d9add192adbef0b6b1/pkg/kubelet/cm/topologymanager/scope_container.go#L52
 	containers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
@@ -142,7 +313,7 @@ func containerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, scorerFn
 
 	for i, container := range containers {
 		identifier := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container.Name)
-		contScore[i] = float64(scoreForEachNUMANode(container.Resources.Requests, allocatablePerNUMA, scorerFn, resourceToWeightMap))
+		contScore[i] = float64(scoreForEachNUMANode(container.Resources.Requests, allocatablePerNUMA, scorerFn))
 		klog.V(6).InfoS("container scope scoring", "container", identifier, "score", contScore[i])
 	}
 	finalScore := int64(stat.Mean(contScore, nil))
@@ -153,24 +324,30 @@ func containerScopeScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList, scorerFn
 func (tm *TopologyMatch) scoringHandlerFromTopologyManagerConfig(conf TopologyManagerConfig) scoringFn {
 	if tm.scoreStrategyType == apiconfig.LeastNUMANodes {
 		if conf.Scope == kubeletconfig.PodTopologyManagerScope {
-			return leastNUMAPodScopeScore
+			return func(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+				return leastNUMAPodScopeScore(pod, zones, tm.numaPreferencesForPod(pod), tm.maxCandidateNUMASets)
+			}
 		}
 		if conf.Scope == kubeletconfig.ContainerTopologyManagerScope {
-			return leastNUMAContainerScopeScore
+			return func(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
+				return leastNUMAContainerScopeScore(pod, zones, tm.numaPreferencesForPod(pod), tm.maxCandidateNUMASets)
+			}
 		}
 		return nil // cannot happen
 	}
-	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
+	// the kubelet tries to align the pods with the best-effort policy too, so the same scoring applies, lowered by the
+	// filter hint on the nodes where the alignment fails
+	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy && conf.Policy != kubeletconfig.BestEffortTopologyManagerPolicy {
 		return nil
 	}
 	if conf.Scope == kubeletconfig.PodTopologyManagerScope {
 		return func(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
-			return podScopeScore(pod, zones, tm.scoreStrategyFunc, tm.resourceToWeightMap)
+			return podScopeScore(pod, zones, tm.numaScorer(zones))
 		}
 	}
 	if conf.Scope == kubeletconfig.ContainerTopologyManagerScope {
 		return func(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, *framework.Status) {
-			return containerScopeScore(pod, zones, tm.scoreStrategyFunc, tm.resourceToWeightMap)
+			return containerScopeScore(pod, zones, tm.numaScorer(zones))
 		}
 	}
 	return nil // cannot happen
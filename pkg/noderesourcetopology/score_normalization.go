@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"gonum.org/v1/gonum/stat"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// normalizedScoreStrategyFn scores the requests against a NUMA node, normalizing each resource against its capacity.
+type normalizedScoreStrategyFn func(requested, available, capacity v1.ResourceList, resourceToWeightMap resourceToWeightMap) int64
+
+// normalizedScoringStrategyFunction returns the capacity normalized variant of the scoring strategy, nil if the normalization
+// is disabled or the strategy doesn't score the resources one by one.
+func normalizedScoringStrategyFunction(strategy apiconfig.ScoringStrategyType, enabled bool) normalizedScoreStrategyFn {
+	if !enabled {
+		return nil
+	}
+	switch strategy {
+	case apiconfig.MostAllocated:
+		return normalizedMostAllocatedScoreStrategy
+	case apiconfig.LeastAllocated:
+		return normalizedLeastAllocatedScoreStrategy
+	case apiconfig.BalancedAllocation:
+		return normalizedBalancedAllocationScoreStrategy
+	default:
+		klog.InfoS("Scoring strategy not normalized against the NUMA capacity", "strategy", strategy)
+		return nil
+	}
+}
+
+// numaCapacities returns the capacity of the resources of the NUMA nodes described by the zones, by NUMA ID.
+func numaCapacities(zones topologyv1alpha2.ZoneList) map[int]v1.ResourceList {
+	capacities := make(map[int]v1.ResourceList)
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		numaID, err := getID(zone.Name)
+		if err != nil {
+			continue
+		}
+		capacity := make(v1.ResourceList, len(zone.Resources))
+		for _, resInfo := range zone.Resources {
+			capacity[v1.ResourceName(resInfo.Name)] = resInfo.Capacity
+		}
+		capacities[numaID] = capacity
+	}
+	return capacities
+}
+
+// allocatedFraction returns the fraction, from 0 to 1, of the capacity of the resource allocated once the request is
+// placed on a NUMA node with the given available quantity. Returns false if the request doesn't fit, or the capacity is zero.
+func allocatedFraction(requested, available, capacity resource.Quantity) (float64, bool) {
+	if capacity.Sign() <= 0 || requested.Cmp(available) > 0 {
+		return 0, false
+	}
+	allocated := capacity.AsApproximateFloat64() - available.AsApproximateFloat64() + requested.AsApproximateFloat64()
+	fraction := allocated / capacity.AsApproximateFloat64()
+	if fraction < 0 {
+		// the available quantity may exceed a stale capacity
+		return 0, true
+	}
+	if fraction > 1 {
+		return 1, true
+	}
+	return fraction, true
+}
+
+func normalizedLeastAllocatedScoreStrategy(requested, available, capacity v1.ResourceList, resourceToWeightMap resourceToWeightMap) int64 {
+	var numaNodeScore float64
+	var weightSum int64
+	for resourceName := range requested {
+		// If NUMA zone doesn't have the requested resource, the score for that resource will be 0.
+		weight := resourceToWeightMap.weight(resourceName)
+		weightSum += weight
+		if fraction, ok := allocatedFraction(requested[resourceName], available[resourceName], capacity[resourceName]); ok {
+			numaNodeScore += (1 - fraction) * float64(framework.MaxNodeScore*weight)
+		}
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return int64(numaNodeScore / float64(weightSum))
+}
+
+func normalizedMostAllocatedScoreStrategy(requested, available, capacity v1.ResourceList, resourceToWeightMap resourceToWeightMap) int64 {
+	var numaNodeScore float64
+	var weightSum int64
+	for resourceName := range requested {
+		// If NUMA zone doesn't have the requested resource, the score for that resource will be 0.
+		weight := resourceToWeightMap.weight(resourceName)
+		weightSum += weight
+		if fraction, ok := allocatedFraction(requested[resourceName], available[resourceName], capacity[resourceName]); ok {
+			numaNodeScore += fraction * float64(framework.MaxNodeScore*weight)
+		}
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return int64(numaNodeScore / float64(weightSum))
+}
+
+func normalizedBalancedAllocationScoreStrategy(requested, available, capacity v1.ResourceList, resourceToWeightMap resourceToWeightMap) int64 {
+	resourceFractions := make([]float64, 0, len(requested))
+	for resourceName := range requested {
+		fraction, ok := allocatedFraction(requested[resourceName], available[resourceName], capacity[resourceName])
+		// if the request doesn't fit the corresponding NUMA zone should never be preferred
+		if !ok {
+			return 0
+		}
+		resourceFractions = append(resourceFractions, fraction)
+	}
+	variance := stat.Variance(resourceFractions, nil)
+	return int64((1 - variance) * float64(framework.MaxNodeScore))
+}
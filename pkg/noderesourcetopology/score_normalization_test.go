@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestNormalizedScoreStrategies(t *testing.T) {
+	// half a CPU out of one, one NIC out of two: both resources are half allocated
+	requested := v1.ResourceList{
+		v1.ResourceCPU:  resource.MustParse("500m"),
+		nicResourceName: resource.MustParse("1"),
+	}
+	available := v1.ResourceList{
+		v1.ResourceCPU:  resource.MustParse("1"),
+		nicResourceName: resource.MustParse("2"),
+	}
+	capacity := available.DeepCopy()
+
+	tests := []struct {
+		name     string
+		strategy apiconfig.ScoringStrategyType
+		expected int64
+	}{
+		{
+			name:     "least allocated",
+			strategy: apiconfig.LeastAllocated,
+			expected: 50,
+		},
+		{
+			name:     "most allocated",
+			strategy: apiconfig.MostAllocated,
+			expected: 50,
+		},
+		{
+			name:     "balanced allocation",
+			strategy: apiconfig.BalancedAllocation,
+			expected: framework.MaxNodeScore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := normalizedScoringStrategyFunction(tt.strategy, true)
+			if got := strategy(requested, available, capacity, resourceToWeightMap{}); got != tt.expected {
+				t.Errorf("score: got=%d expected=%d", got, tt.expected)
+			}
+		})
+	}
+
+	if strategy := normalizedScoringStrategyFunction(apiconfig.LeastAllocated, false); strategy != nil {
+		t.Errorf("normalization enabled while disabled")
+	}
+	if strategy := normalizedScoringStrategyFunction(apiconfig.LeastNUMANodes, true); strategy != nil {
+		t.Errorf("normalization enabled for a strategy not scoring the resources one by one")
+	}
+}
+
+func TestCapacityNormalizedScoring(t *testing.T) {
+	makeNRT := func(name, cpuAvailable, memoryAvailable, nicAvailable string) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "64", cpuAvailable),
+						MakeTopologyResInfo(memory, "64Gi", memoryAvailable),
+						MakeTopologyResInfo(nicResourceName, "8", nicAvailable),
+					},
+				},
+			},
+		}
+	}
+	// thousands of millicores free on the first node, but its last NIC
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		makeNRT("busy-nics", "60", "60Gi", "1"),
+		makeNRT("free-nics", "40", "40Gi", "8"),
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tm := &TopologyMatch{
+		nrtCache:               nrtcache.NewPassthrough(fakeClient),
+		scoreStrategyType:      apiconfig.LeastAllocated,
+		scoreStrategyFunc:      leastAllocatedScoreStrategy,
+		normalizedStrategyFunc: normalizedScoringStrategyFunction(apiconfig.LeastAllocated, true),
+	}
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+		nicResourceName:   resource.MustParse("1"),
+	})
+
+	// cpu and memory score (60-2)/64 on the first node and (40-2)/64 on the second, the NIC 0/8 and 7/8
+	expected := map[string]int64{
+		"busy-nics": 60,
+		"free-nics": 68,
+	}
+	for nodeName, want := range expected {
+		got, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+		if !status.IsSuccess() {
+			t.Fatalf("unexpected score status: %v", status)
+		}
+		if got != want {
+			t.Errorf("node %s: score=%d expected=%d", nodeName, got, want)
+		}
+	}
+}
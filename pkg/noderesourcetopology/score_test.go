@@ -19,6 +19,7 @@ package noderesourcetopology
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
@@ -472,6 +473,16 @@ func TestNodeResourceScorePluginLeastNUMA(t *testing.T) {
 	}
 }
 
+func TestGetScoringStrategyFunctionUnknown(t *testing.T) {
+	_, err := getScoringStrategyFunction(apiconfig.ScoringStrategyType("MostFree"))
+	if err == nil {
+		t.Fatalf("expected an error for an unknown scoring strategy")
+	}
+	if !strings.Contains(err.Error(), `"MostFree"`) {
+		t.Errorf("expected the error to name the strategy, got %v", err)
+	}
+}
+
 // when only a subset of nodes has NRT data available[1], prefer the nodes which have the NRT data over the other nodes;
 // IOW, a node without NRT data available should always have score == 0
 func TestNodeResourcePartialDataScorePlugin(t *testing.T) {
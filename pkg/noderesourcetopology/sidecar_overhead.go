@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// AnnotationIgnoreSidecarOverhead, if "true", makes the filter ignore the estimated sidecar overhead for the pod,
+// e.g. for the pods not getting any sidecar injected.
+const AnnotationIgnoreSidecarOverhead = AnnotationKeyPrefix + "ignore-sidecar-overhead"
+
+// sidecarOverheadContainerName is the name of the container standing for the sidecars injected after the scheduling.
+const sidecarOverheadContainerName = "sidecar-overhead-estimate"
+
+// withSidecarOverhead returns a copy of the pod with an additional app container requesting the estimated overhead
+// of the sidecars to be injected after the scheduling, so the NUMA alignment accounts for them. The pod is returned
+// as it is if no overhead is estimated, or if it opts out.
+func (tm *TopologyMatch) withSidecarOverhead(pod *v1.Pod) *v1.Pod {
+	if len(tm.sidecarOverhead) == 0 {
+		return pod
+	}
+	if val, ok := pod.Annotations[AnnotationIgnoreSidecarOverhead]; ok {
+		ignore, err := strconv.ParseBool(val)
+		if err != nil {
+			klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationIgnoreSidecarOverhead, "value", val)
+		} else if ignore {
+			return pod
+		}
+	}
+	podCopy := pod.DeepCopy()
+	// added last, so the NUMA nodes chosen for the actual containers come first
+	podCopy.Spec.Containers = append(podCopy.Spec.Containers, v1.Container{
+		Name: sidecarOverheadContainerName,
+		Resources: v1.ResourceRequirements{
+			Requests: tm.sidecarOverhead.DeepCopy(),
+			Limits:   tm.sidecarOverhead.DeepCopy(),
+		},
+	})
+	return podCopy
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterSidecarOverhead(t *testing.T) {
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy) *topologyv1alpha2.NodeResourceTopology {
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "4"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "4", "2"),
+						MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					},
+				},
+			},
+		}
+	}
+	podLevel := makeNRT("pod-level", topologyv1alpha2.SingleNUMANodePodLevel)
+	containerLevel := makeNRT("container-level", topologyv1alpha2.SingleNUMANodeContainerLevel)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{podLevel, containerLevel} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	overhead := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1500m"),
+		v1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+
+	tests := []struct {
+		name        string
+		nrt         *topologyv1alpha2.NodeResourceTopology
+		overhead    v1.ResourceList
+		annotations map[string]string
+		wantStatus  *framework.Status
+	}{
+		{
+			name: "no overhead estimated",
+			nrt:  podLevel,
+		},
+		{
+			name:       "overhead estimated, pod scope",
+			nrt:        podLevel,
+			overhead:   overhead,
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:        "overhead ignored by the pod",
+			nrt:         podLevel,
+			overhead:    overhead,
+			annotations: map[string]string{AnnotationIgnoreSidecarOverhead: "true"},
+		},
+		{
+			name:        "malformed annotation",
+			nrt:         podLevel,
+			overhead:    overhead,
+			annotations: map[string]string{AnnotationIgnoreSidecarOverhead: "yes please"},
+			wantStatus:  framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+		{
+			// the sidecar goes on the second NUMA node
+			name:     "overhead estimated, container scope",
+			nrt:      containerLevel,
+			overhead: overhead,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("3"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			})
+			pod.Annotations = tt.annotations
+			tm := TopologyMatch{
+				nrtCache:        nrtcache.NewPassthrough(fakeClient),
+				sidecarOverhead: tt.overhead,
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+			if len(pod.Spec.Containers) != 1 {
+				t.Errorf("pod modified: %d containers", len(pod.Spec.Containers))
+			}
+		})
+	}
+}
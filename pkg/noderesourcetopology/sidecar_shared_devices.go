@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	v1 "k8s.io/api/core/v1"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+)
+
+// AnnotationSidecarSharedDevices is a comma-separated list of device resources the restartable init containers
+// (sidecars) of the pod share with its app containers, e.g. a monitoring sidecar using the GPU of the app container.
+const AnnotationSidecarSharedDevices = AnnotationKeyPrefix + "sidecar-shared-devices"
+
+// sidecarConsumedResources returns the resources of the restartable init container to consume from its NUMA node,
+// leaving out the devices it shares with the app containers, which are consumed with the app containers requesting
+// them instead, so they are not counted twice. The devices no app container requests are not shared, and are kept.
+func sidecarConsumedResources(pod *v1.Pod, resources v1.ResourceList) v1.ResourceList {
+	val, ok := pod.Annotations[AnnotationSidecarSharedDevices]
+	if !ok {
+		return resources
+	}
+	var consumed v1.ResourceList
+	for _, resName := range parseResourceNames(pod, AnnotationSidecarSharedDevices, val) {
+		if _, ok := resources[resName]; !ok || v1helper.IsNativeResource(resName) {
+			continue
+		}
+		if !appContainersRequest(pod, resName) {
+			continue
+		}
+		if consumed == nil {
+			consumed = resources.DeepCopy()
+		}
+		delete(consumed, resName)
+	}
+	if consumed == nil {
+		return resources
+	}
+	return consumed
+}
+
+func appContainersRequest(pod *v1.Pod, resName v1.ResourceName) bool {
+	for _, container := range pod.Spec.Containers {
+		if quantity, ok := container.Resources.Requests[resName]; ok && !quantity.IsZero() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterSidecarSharedDevices(t *testing.T) {
+	// a single NIC is left on the node, so it can serve both containers only if they share it
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "host0"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodeContainerLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(nicResourceName, "2", "1"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(nicResourceName, "2", "0"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	containerReq := []map[string]string{
+		{cpu: "1", memory: "1Gi", nicResourceName: "1"},
+	}
+
+	tests := []struct {
+		name         string
+		annotation   string
+		initReq      []map[string]string
+		appReq       []map[string]string
+		expectedCode framework.Code
+	}{
+		{
+			name:         "distinct devices",
+			appReq:       containerReq,
+			expectedCode: framework.Unschedulable,
+		},
+		{
+			name:         "shared devices",
+			annotation:   nicResourceName,
+			appReq:       containerReq,
+			expectedCode: framework.Success,
+		},
+		{
+			name:         "other devices shared",
+			annotation:   "vendor/other",
+			appReq:       containerReq,
+			expectedCode: framework.Unschedulable,
+		},
+		{
+			name:         "shared devices not requested by the app containers",
+			annotation:   nicResourceName,
+			initReq:      []map[string]string{containerReq[0], containerReq[0]},
+			appReq:       []map[string]string{{cpu: "1", memory: "1Gi"}},
+			expectedCode: framework.Unschedulable,
+		},
+	}
+
+	tm := TopologyMatch{
+		nrtCache: nrtcache.NewPassthrough(fakeClient),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initReq := tt.initReq
+			if initReq == nil {
+				initReq = containerReq
+			}
+			pod := makePod("testpod",
+				withMultiInitContainers(parseContainerRes(initReq)),
+				withMultiContainers(parseContainerRes(tt.appReq)),
+			)
+			restartPolicy := v1.ContainerRestartPolicyAlways
+			for idx := range pod.Spec.InitContainers {
+				pod.Spec.InitContainers[idx].RestartPolicy = &restartPolicy
+			}
+			if tt.annotation != "" {
+				pod.Annotations = map[string]string{AnnotationSidecarSharedDevices: tt.annotation}
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if status.Code() != tt.expectedCode {
+				t.Errorf("unexpected status code %v, expected %v: %v", status.Code(), tt.expectedCode, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	reasonFitsSocket     = "fits within a socket but not in a single NUMA node"
+	reasonCrossesSockets = "requires crossing sockets"
+)
+
+// numaNodeSockets maps the NUMA nodes to their socket, which is the parent of their zone.
+// Returns nil if any NUMA zone doesn't report its parent.
+func numaNodeSockets(zones topologyv1alpha2.ZoneList) map[int]string {
+	numaSockets := make(map[int]string)
+	for _, zone := range zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		if zone.Parent == "" {
+			return nil
+		}
+		numaID, err := getID(zone.Name)
+		if err != nil {
+			continue
+		}
+		numaSockets[numaID] = zone.Parent
+	}
+	if len(numaSockets) == 0 {
+		return nil
+	}
+	return numaSockets
+}
+
+// cannotAlignStatus returns the status rejecting the pod because the resources can't be aligned on a single NUMA node,
+// telling, on the nodes reporting the sockets of their NUMA nodes, if the resources would fit within a socket or not.
+func (info *filterInfo) cannotAlignStatus(reason string, resources v1.ResourceList) *framework.Status {
+	status := framework.NewStatus(framework.Unschedulable, reason)
+	if socketReason, ok := info.socketFitReason(resources); ok {
+		status.AppendReason(socketReason)
+	}
+	return status
+}
+
+// socketFitReason tells if the aligned resources would fit in the NUMA nodes of a single socket. The NUMA nodes
+// the pod can't be placed on are not considered. Returns false if the node doesn't report the sockets.
+func (info *filterInfo) socketFitReason(resources v1.ResourceList) (string, bool) {
+	if info.numaSockets == nil {
+		return "", false
+	}
+	requests := v1.ResourceList{}
+	for resName, quantity := range numaAffineResources(resources, info.numaNodes) {
+		if info.qosPolicy.capacityIgnored(info.qos, resName) && !info.preferences.requiresAlignment(resName) {
+			continue
+		}
+		if !info.topologyManager.providesHints(resName) || info.preferences.excludesAlignment(resName) {
+			continue
+		}
+		requests[resName] = info.rounding.roundUp(resName, quantity)
+	}
+
+	socketResources := make(map[string]v1.ResourceList)
+	for _, numaNode := range info.numaNodes {
+		if _, excluded := info.excludedNUMANodes[numaNode.NUMAID]; excluded {
+			continue
+		}
+		socket, ok := info.numaSockets[numaNode.NUMAID]
+		if !ok {
+			continue
+		}
+		available, ok := socketResources[socket]
+		if !ok {
+			available = v1.ResourceList{}
+			socketResources[socket] = available
+		}
+		for resName, quantity := range numaNode.Resources {
+			total := available[resName]
+			total.Add(quantity)
+			available[resName] = total
+		}
+	}
+	for _, available := range socketResources {
+		if numaFitsRequests(requests, available) {
+			return reasonFitsSocket, true
+		}
+	}
+	return reasonCrossesSockets, true
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFilterSocketFitDiagnostics(t *testing.T) {
+	// two sockets of two NUMA nodes, each NUMA node has 4 cpus left
+	makeNRT := func(name string, policy topologyv1alpha2.TopologyManagerPolicy, withSockets bool) *topologyv1alpha2.NodeResourceTopology {
+		socket := func(name string) string {
+			if !withSockets {
+				return ""
+			}
+			return name
+		}
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(policy)},
+			Zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", socket("socket-0"), "8", "4"),
+				makeSocketZone("node-1", socket("socket-0"), "8", "4"),
+				makeSocketZone("node-2", socket("socket-1"), "8", "4"),
+				makeSocketZone("node-3", socket("socket-1"), "8", "4"),
+			},
+		}
+	}
+	podLevel := makeNRT("pod-level", topologyv1alpha2.SingleNUMANodePodLevel, true)
+	containerLevel := makeNRT("container-level", topologyv1alpha2.SingleNUMANodeContainerLevel, true)
+	noSockets := makeNRT("no-sockets", topologyv1alpha2.SingleNUMANodePodLevel, false)
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range []*topologyv1alpha2.NodeResourceTopology{podLevel, containerLevel, noSockets} {
+		if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		cpus       string
+		wantStatus *framework.Status
+	}{
+		{
+			name: "fits in a single NUMA node",
+			nrt:  podLevel,
+			cpus: "4",
+		},
+		{
+			name:       "fits within a socket",
+			nrt:        podLevel,
+			cpus:       "6",
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod", reasonFitsSocket),
+		},
+		{
+			name:       "requires crossing sockets",
+			nrt:        podLevel,
+			cpus:       "10",
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod", reasonCrossesSockets),
+		},
+		{
+			name:       "container scope, fits within a socket",
+			nrt:        containerLevel,
+			cpus:       "6",
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align container", reasonFitsSocket),
+		},
+		{
+			name:       "sockets not reported",
+			nrt:        noSockets,
+			cpus:       "10",
+			wantStatus: framework.NewStatus(framework.Unschedulable, "cannot align pod"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(tt.cpus),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			})
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo)
+			if !reflect.DeepEqual(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// socketFreenessComponent scores the share of the sockets of the node left free once the pod is placed. It doesn't
+// apply to the nodes not reporting the sockets of their NUMA nodes, nor to the pods fitting in no single NUMA node.
+func (tm *TopologyMatch) socketFreenessComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	freeness, ok := socketFreenessScore(pod, zones)
+	return freeness, ok, nil
+}
+
+// socketFreenessScore scores the share of the sockets with all their NUMA nodes unused left once the pod is placed,
+// assuming it lands on a socket already in use whenever it fits there. The socket of a NUMA zone is its parent zone.
+func socketFreenessScore(pod *v1.Pod, zones topologyv1alpha2.ZoneList) (int64, bool) {
+	numaSockets := numaNodeSockets(zones)
+	if numaSockets == nil {
+		return 0, false
+	}
+	// socket name -> true if none of its NUMA nodes is in use
+	sockets := make(map[string]bool)
+	for _, zone := range zones {
+		numaID, err := getID(zone.Name)
+		if zone.Type != "Node" || err != nil {
+			continue
+		}
+		socket := numaSockets[numaID]
+		idle, ok := sockets[socket]
+		sockets[socket] = isZoneUnused(zone) && (idle || !ok)
+	}
+
+	numaNodes := createNUMANodeList(zones)
+	requests := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	fits, fitsInUse := false, false
+	for _, numaNode := range numaNodes {
+		if !numaFitsRequests(requests, numaNode.Resources) {
+			continue
+		}
+		fits = true
+		if !sockets[numaSockets[numaNode.NUMAID]] {
+			fitsInUse = true
+			break
+		}
+	}
+	if !fits {
+		return 0, false
+	}
+
+	free := int64(0)
+	for _, idle := range sockets {
+		if idle {
+			free++
+		}
+	}
+	if !fitsInUse {
+		// the pod takes a free socket
+		free--
+	}
+	return framework.MaxNodeScore * free / int64(len(sockets)), true
+}
+
+// isZoneUnused returns true if all the allocatable resources of the zone, or the capacity if the allocatable
+// resources are not reported, are available.
+func isZoneUnused(zone topologyv1alpha2.Zone) bool {
+	for _, res := range zone.Resources {
+		allocatable := res.Allocatable
+		if allocatable.IsZero() {
+			allocatable = res.Capacity
+		}
+		if res.Available.Cmp(allocatable) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makeSocketZone(numaID, socket string, cpuAllocatable, cpuAvailable string) topologyv1alpha2.Zone {
+	return topologyv1alpha2.Zone{
+		Name:   numaID,
+		Type:   "Node",
+		Parent: socket,
+		Resources: topologyv1alpha2.ResourceInfoList{
+			MakeTopologyResInfo(cpu, cpuAllocatable, cpuAvailable),
+			MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+		},
+	}
+}
+
+func TestSocketFreenessScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		zones    topologyv1alpha2.ZoneList
+		expected int64
+		ok       bool
+	}{
+		{
+			name: "sockets not reported",
+			zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", "", "8", "8"),
+				makeSocketZone("node-1", "", "8", "8"),
+			},
+		},
+		{
+			name: "pod does not fit",
+			zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", "socket-0", "8", "2"),
+				makeSocketZone("node-1", "socket-1", "8", "2"),
+			},
+		},
+		{
+			name: "pod fits on the socket in use",
+			zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", "socket-0", "8", "8"),
+				makeSocketZone("node-1", "socket-1", "8", "6"),
+			},
+			expected: 50,
+			ok:       true,
+		},
+		{
+			name: "pod takes a free socket",
+			zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", "socket-0", "8", "8"),
+				makeSocketZone("node-1", "socket-1", "8", "2"),
+			},
+			expected: 0,
+			ok:       true,
+		},
+		{
+			name: "socket in use by one of its NUMA nodes",
+			zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", "socket-0", "8", "8"),
+				makeSocketZone("node-1", "socket-0", "8", "6"),
+				makeSocketZone("node-2", "socket-1", "8", "8"),
+				makeSocketZone("node-3", "socket-1", "8", "8"),
+			},
+			expected: 50,
+			ok:       true,
+		},
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := socketFreenessScore(pod, tt.zones)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("score=%d,%v expected=%d,%v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestScoreSocketFreeness(t *testing.T) {
+	nrts := []*topologyv1alpha2.NodeResourceTopology{
+		{
+			// a tight fit for the pod, but on its only socket
+			ObjectMeta:       metav1.ObjectMeta{Name: "compact"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", "socket-0", "4", "4"),
+				makeSocketZone("node-1", "socket-0", "4", "4"),
+			},
+		},
+		{
+			// a loose fit for the pod, on the socket already in use
+			ObjectMeta:       metav1.ObjectMeta{Name: "roomy"},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				makeSocketZone("node-0", "socket-0", "16", "12"),
+				makeSocketZone("node-1", "socket-1", "16", "16"),
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	for _, nrt := range nrts {
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name                 string
+		socketFreenessWeight int64
+		expected             nodeToScoreMap
+	}{
+		{
+			name:     "disabled",
+			expected: nodeToScoreMap{"compact": 75, "roomy": 37},
+		},
+		{
+			name:                 "socket freeness preferred",
+			socketFreenessWeight: 50,
+			expected:             nodeToScoreMap{"compact": 37, "roomy": 43},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			})
+			tm := &TopologyMatch{
+				nrtCache:             nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc:    mostAllocatedScoreStrategy,
+				scoreStrategyType:    apiconfig.MostAllocated,
+				socketFreenessWeight: tt.socketFreenessWeight,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
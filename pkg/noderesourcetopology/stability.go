@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// stabilityComponent scores the stability of the most stable NUMA node of the node fitting the pod. It doesn't apply
+// to the pods fitting in no single NUMA node.
+func (tm *TopologyMatch) stabilityComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	requests := util.GetPodEffectiveRequest(pod)
+	stability := int64(-1)
+	for _, numa := range createNUMANodeList(zones) {
+		if !numaFitsRequests(requests, numa.Resources) {
+			continue
+		}
+		if numaStability := stabilityScore(requests, numa.Resources, tm.resourceToWeightMap); numaStability > stability {
+			stability = numaStability
+		}
+	}
+	return stability, stability >= 0, nil
+}
+
+// stabilityScore scores how likely the placement of the pod on a NUMA node is to last: half of the score is given
+// by the resources left on the NUMA node once the pod is placed, the other half is given only if another pod of
+// the same size would still fit, that is if the pod does not take the last free slot of the NUMA node.
+func stabilityScore(requests, available v1.ResourceList, resourceToWeightMap resourceToWeightMap) int64 {
+	headroom := leastAllocatedScoreStrategy(requests, available, resourceToWeightMap)
+
+	remaining := available.DeepCopy()
+	for resName, quantity := range requests {
+		if numaQuantity, ok := remaining[resName]; ok {
+			numaQuantity.Sub(quantity)
+			remaining[resName] = numaQuantity
+		}
+	}
+	var slot int64
+	if numaFitsRequests(requests, remaining) {
+		slot = framework.MaxNodeScore
+	}
+	return (headroom + slot) / 2
+}
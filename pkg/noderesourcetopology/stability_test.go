@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestStabilityScore(t *testing.T) {
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+	}
+	tests := []struct {
+		name      string
+		available v1.ResourceList
+		expected  int64
+	}{
+		{
+			name: "exact fit",
+			available: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+			expected: 0,
+		},
+		{
+			name: "last free slot",
+			available: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("12Gi"),
+			},
+			// (33 + 0) / 2
+			expected: 16,
+		},
+		{
+			name: "room for another pod",
+			available: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("8"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			// (50 + 100) / 2
+			expected: 75,
+		},
+		{
+			name: "room for another pod, except memory",
+			available: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("16"),
+				v1.ResourceMemory: resource.MustParse("12Gi"),
+			},
+			// ((75 + 33) / 2 + 0) / 2
+			expected: 27,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := stabilityScore(requests, tt.available, resourceToWeightMap{})
+			if score != tt.expected {
+				t.Errorf("score=%d expected=%d", score, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStabilityBlendedScore(t *testing.T) {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	nodes := map[string][]string{
+		"tight":     {"4", "8Gi"},
+		"last-slot": {"6", "12Gi"},
+		"roomy":     {"8", "16Gi"},
+	}
+	for nodeName, available := range nodes {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: nodeName},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, available[0], available[0]),
+						MakeTopologyResInfo(memory, available[1], available[1]),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "2", "2"),
+						MakeTopologyResInfo(memory, "4Gi", "4Gi"),
+					},
+				},
+			},
+		}
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		v1.ResourceMemory: resource.MustParse("8Gi"),
+	})
+
+	tests := []struct {
+		name            string
+		stabilityWeight int64
+		expected        nodeToScoreMap
+	}{
+		{
+			name:     "disabled",
+			expected: nodeToScoreMap{"tight": 100, "last-slot": 66, "roomy": 50},
+		},
+		{
+			name:            "more stable placement preferred",
+			stabilityWeight: 60,
+			// stability scores: 0, 16 and 75
+			expected: nodeToScoreMap{"tight": 40, "last-slot": 36, "roomy": 65},
+		},
+		{
+			name:            "stability only",
+			stabilityWeight: 100,
+			expected:        nodeToScoreMap{"tight": 0, "last-slot": 16, "roomy": 75},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc: mostAllocatedScoreStrategy,
+				scoreStrategyType: apiconfig.MostAllocated,
+				stabilityWeight:   tt.stabilityWeight,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+	"strings"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// AnnotationStickyNUMAPlacement is the NUMA placement the pod wants to return to, as "<node name>:<NUMA node IDs>",
+// e.g. "worker-0:1" or "worker-0:0,1". Expected to be copied by the workload controller from the
+// assigned-numa-nodes annotation of the previous incarnation of the pod.
+const AnnotationStickyNUMAPlacement = AnnotationKeyPrefix + "sticky-numa-placement"
+
+type stickyNUMAPlacement struct {
+	nodeName string
+	numaIDs  map[int]bool
+}
+
+// stickyNUMAComponent scores the match of the node against the sticky NUMA placement of the pod. It doesn't apply
+// to the pods without a well-formed sticky NUMA placement.
+func (tm *TopologyMatch) stickyNUMAComponent(pod *v1.Pod, nodeName string, zones topologyv1alpha2.ZoneList) (int64, bool, *framework.Status) {
+	val, ok := pod.Annotations[AnnotationStickyNUMAPlacement]
+	if !ok {
+		return 0, false, nil
+	}
+	placement, err := parseStickyNUMAPlacement(val)
+	if err != nil {
+		klog.V(2).InfoS("ignoring malformed annotation", "pod", klog.KObj(pod), "annotation", AnnotationStickyNUMAPlacement, "value", val, "err", err)
+		return 0, false, nil
+	}
+	return stickyNUMAScore(pod, nodeName, createNUMANodeList(zones), placement), true, nil
+}
+
+// stickyNUMAScore gives the maximum score if the pod fits on one of the NUMA nodes of its sticky placement,
+// a neutral score if the node is the sticky one but the pod does not fit on those NUMA nodes anymore,
+// and the minimum score to the other nodes.
+func stickyNUMAScore(pod *v1.Pod, nodeName string, numaNodes NUMANodeList, placement stickyNUMAPlacement) int64 {
+	if nodeName != placement.nodeName {
+		return framework.MinNodeScore
+	}
+	requests := numaAffineResources(util.GetPodEffectiveRequest(pod), numaNodes)
+	for _, numaNode := range numaNodes {
+		if placement.numaIDs[numaNode.NUMAID] && numaFitsRequests(requests, numaNode.Resources) {
+			return framework.MaxNodeScore
+		}
+	}
+	return neutralNodeScore
+}
+
+func parseStickyNUMAPlacement(val string) (stickyNUMAPlacement, error) {
+	nodeName, numaIDs, ok := strings.Cut(val, ":")
+	nodeName = strings.TrimSpace(nodeName)
+	if !ok || nodeName == "" {
+		return stickyNUMAPlacement{}, fmt.Errorf("missing node name")
+	}
+	ids, err := parseNUMANodeIDs(numaIDs)
+	if err != nil {
+		return stickyNUMAPlacement{}, err
+	}
+	if len(ids) == 0 {
+		return stickyNUMAPlacement{}, fmt.Errorf("missing NUMA node IDs")
+	}
+	placement := stickyNUMAPlacement{
+		nodeName: nodeName,
+		numaIDs:  make(map[int]bool, len(ids)),
+	}
+	for _, id := range ids {
+		placement.numaIDs[id] = true
+	}
+	return placement, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestParseStickyNUMAPlacement(t *testing.T) {
+	tests := []struct {
+		value    string
+		nodeName string
+		numaIDs  map[int]bool
+		wantErr  bool
+	}{
+		{value: "worker-0:1", nodeName: "worker-0", numaIDs: map[int]bool{1: true}},
+		{value: "worker-0:0, 1", nodeName: "worker-0", numaIDs: map[int]bool{0: true, 1: true}},
+		{value: "worker-0", wantErr: true},
+		{value: ":1", wantErr: true},
+		{value: "worker-0:", wantErr: true},
+		{value: "worker-0:a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseStickyNUMAPlacement(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("value=%q unexpected error: %v", tt.value, err)
+			continue
+		}
+		if got.nodeName != tt.nodeName || len(got.numaIDs) != len(tt.numaIDs) {
+			t.Errorf("value=%q got=%+v expected node=%q NUMA nodes=%v", tt.value, got, tt.nodeName, tt.numaIDs)
+			continue
+		}
+		for id := range tt.numaIDs {
+			if !got.numaIDs[id] {
+				t.Errorf("value=%q got=%+v expected NUMA nodes=%v", tt.value, got, tt.numaIDs)
+			}
+		}
+	}
+}
+
+func TestScoreStickyNUMA(t *testing.T) {
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	// the NUMA node 1 of worker-2 has not enough room left for the pod
+	nodes := map[string]string{
+		"worker-0": "8",
+		"worker-1": "8",
+		"worker-2": "2",
+	}
+	for nodeName, numa1CPUs := range nodes {
+		nrt := &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: nodeName},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones: topologyv1alpha2.ZoneList{
+				{
+					Name: "node-0",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", "8"),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+				{
+					Name: "node-1",
+					Type: "Node",
+					Resources: topologyv1alpha2.ResourceInfoList{
+						MakeTopologyResInfo(cpu, "8", numa1CPUs),
+						MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+					},
+				},
+			},
+		}
+		if err := fakeClient.Create(context.Background(), nrt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name             string
+		annotation       string
+		stickyNUMAWeight int64
+		expected         nodeToScoreMap
+	}{
+		{
+			name:       "disabled",
+			annotation: "worker-1:1",
+			expected:   nodeToScoreMap{"worker-0": 50, "worker-1": 50, "worker-2": 25},
+		},
+		{
+			name:             "no sticky placement",
+			stickyNUMAWeight: 50,
+			expected:         nodeToScoreMap{"worker-0": 50, "worker-1": 50, "worker-2": 25},
+		},
+		{
+			name:             "malformed sticky placement",
+			annotation:       "worker-1",
+			stickyNUMAWeight: 50,
+			expected:         nodeToScoreMap{"worker-0": 50, "worker-1": 50, "worker-2": 25},
+		},
+		{
+			name:             "sticky node preferred",
+			annotation:       "worker-1:1",
+			stickyNUMAWeight: 50,
+			expected:         nodeToScoreMap{"worker-0": 25, "worker-1": 75, "worker-2": 12},
+		},
+		{
+			name:             "sticky NUMA node without room",
+			annotation:       "worker-2:1",
+			stickyNUMAWeight: 50,
+			expected:         nodeToScoreMap{"worker-0": 25, "worker-1": 25, "worker-2": 37},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("8Gi"),
+			})
+			if tt.annotation != "" {
+				pod.Annotations = map[string]string{AnnotationStickyNUMAPlacement: tt.annotation}
+			}
+			tm := &TopologyMatch{
+				nrtCache:          nrtcache.NewPassthrough(fakeClient),
+				scoreStrategyFunc: mostAllocatedScoreStrategy,
+				scoreStrategyType: apiconfig.MostAllocated,
+				stickyNUMAWeight:  tt.stickyNUMAWeight,
+			}
+			for nodeName, expectedScore := range tt.expected {
+				score, status := tm.Score(context.Background(), framework.NewCycleState(), pod, nodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected status on node %q: %v", nodeName, status)
+				}
+				if score != expectedScore {
+					t.Errorf("node %q: score=%d expected=%d", nodeName, score, expectedScore)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// AttributeUpdateTime is the NodeResourceTopology attribute holding, in RFC 3339 format, the time its producer last
+// refreshed the data.
+const AttributeUpdateTime = "updateTime"
+
+// topologyAge returns how old the NodeResourceTopology data is, from the update time attribute if reported, or else from
+// the time of the last update of the object recorded in its managed fields. ok is false if the age can't be derived.
+func topologyAge(nodeTopology *topologyv1alpha2.NodeResourceTopology, now time.Time) (time.Duration, bool) {
+	for _, attr := range nodeTopology.Attributes {
+		if attr.Name != AttributeUpdateTime {
+			continue
+		}
+		updateTime, err := time.Parse(time.RFC3339, attr.Value)
+		if err != nil {
+			klog.V(5).InfoS("malformed update time attribute", "node", nodeTopology.Name, "value", attr.Value, "error", err)
+			break
+		}
+		return now.Sub(updateTime), true
+	}
+	var lastUpdate time.Time
+	for _, entry := range nodeTopology.ManagedFields {
+		if entry.Time != nil && entry.Time.After(lastUpdate) {
+			lastUpdate = entry.Time.Time
+		}
+	}
+	if lastUpdate.IsZero() {
+		return 0, false
+	}
+	return now.Sub(lastUpdate), true
+}
+
+// logFilterDecisionTopologyAge logs the filter decision along with the age of the NodeResourceTopology data it was made against.
+func logFilterDecisionTopologyAge(pod *v1.Pod, nodeName string, nodeTopology *topologyv1alpha2.NodeResourceTopology, status *framework.Status) {
+	age := "unknown"
+	if elapsed, ok := topologyAge(nodeTopology, time.Now()); ok {
+		age = elapsed.Truncate(time.Second).String()
+	}
+	klog.V(4).InfoS("filter decision", "pod", klog.KObj(pod), "node", nodeName, "verdict", status.Code().String(), "reason", status.Message(), "topologyAge", age)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestTopologyAge(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	managedFields := []metav1.ManagedFieldsEntry{
+		{Manager: "updater", Time: &metav1.Time{Time: now.Add(-90 * time.Second)}},
+		{Manager: "updater", Time: &metav1.Time{Time: now.Add(-30 * time.Second)}},
+	}
+
+	tests := []struct {
+		name          string
+		attributes    topologyv1alpha2.AttributeList
+		managedFields []metav1.ManagedFieldsEntry
+		expected      time.Duration
+		expectedOK    bool
+	}{
+		{
+			name:       "update time attribute",
+			attributes: topologyv1alpha2.AttributeList{{Name: AttributeUpdateTime, Value: "2024-03-01T11:58:00Z"}},
+			// the attribute wins over the managed fields
+			managedFields: managedFields,
+			expected:      2 * time.Minute,
+			expectedOK:    true,
+		},
+		{
+			name:          "last update of the object",
+			managedFields: managedFields,
+			expected:      30 * time.Second,
+			expectedOK:    true,
+		},
+		{
+			name:          "malformed update time attribute",
+			attributes:    topologyv1alpha2.AttributeList{{Name: AttributeUpdateTime, Value: "yesterday"}},
+			managedFields: managedFields,
+			expected:      30 * time.Second,
+			expectedOK:    true,
+		},
+		{
+			name: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nrt := &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1", ManagedFields: tt.managedFields},
+				Attributes: tt.attributes,
+			}
+			got, ok := topologyAge(nrt, now)
+			if ok != tt.expectedOK || got != tt.expected {
+				t.Errorf("got age=%v ok=%v, expected age=%v ok=%v", got, ok, tt.expected, tt.expectedOK)
+			}
+		})
+	}
+}
+
+func TestFilterLogsTopologyAge(t *testing.T) {
+	var fs flag.FlagSet
+	klog.InitFlags(&fs)
+	for name, value := range map[string]string{"v": "4", "logtostderr": "false", "alsologtostderr": "false"} {
+		if err := fs.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	klog.SetOutput(&buf)
+	defer func() {
+		klog.SetOutput(os.Stderr)
+		_ = fs.Set("v", "0")
+		_ = fs.Set("logtostderr", "true")
+	}()
+
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Attributes: topologyv1alpha2.AttributeList{
+			{Name: AttributeUpdateTime, Value: time.Now().Add(-10 * time.Minute).Format(time.RFC3339)},
+		},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := &TopologyMatch{
+		nrtCache:       nrtcache.NewPassthrough(fakeClient),
+		logTopologyAge: true,
+	}
+	pod := makePod("testpod", withMultiContainers(parseContainerRes([]map[string]string{{cpu: "2", memory: "1Gi"}})))
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+	if status := tm.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo); status != nil {
+		t.Fatalf("unexpected filter status: %v", status)
+	}
+	klog.Flush()
+
+	logged := buf.String()
+	if !strings.Contains(logged, `"filter decision"`) || !strings.Contains(logged, `topologyAge="10m`) {
+		t.Errorf("filter decision with the topology age not logged:\n%s", logged)
+	}
+}
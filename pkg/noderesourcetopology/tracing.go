@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	instrumentationName = "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology"
+
+	spanAttrNode      = "node"
+	spanAttrPolicy    = "policy"
+	spanAttrScope     = "scope"
+	spanAttrNUMANodes = "numa.nodes"
+	spanAttrScore     = "score"
+	spanAttrVerdict   = "verdict"
+	spanAttrReason    = "reason"
+)
+
+// WithTracerProvider makes the plugin create a span for each Filter and Score call, using a tracer
+// from the given provider. Without a provider, no spans are created. The framework handle exposes no tracer,
+// so the provider can't be taken from the scheduler and must be passed explicitly.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(tm *TopologyMatch) {
+		tm.tracer = tp.Tracer(instrumentationName)
+	}
+}
+
+// startSpan returns a span which does nothing if the plugin has no tracer.
+func (tm *TopologyMatch) startSpan(ctx context.Context, name, nodeName string) (context.Context, trace.Span) {
+	if tm.tracer == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return tm.tracer.Start(ctx, Name+"/"+name, trace.WithAttributes(attribute.String(spanAttrNode, nodeName)))
+}
+
+func setSpanConfig(span trace.Span, conf TopologyManagerConfig) {
+	span.SetAttributes(attribute.String(spanAttrPolicy, conf.Policy), attribute.String(spanAttrScope, conf.Scope))
+}
+
+func setSpanNUMANodes(span trace.Span, numaIDs []int) {
+	if len(numaIDs) == 0 {
+		return
+	}
+	seen := make(map[int]bool, len(numaIDs))
+	unique := make([]int, 0, len(numaIDs))
+	for _, numaID := range numaIDs {
+		if seen[numaID] {
+			continue
+		}
+		seen[numaID] = true
+		unique = append(unique, numaID)
+	}
+	sort.Ints(unique)
+	span.SetAttributes(attribute.IntSlice(spanAttrNUMANodes, unique))
+}
+
+func setSpanVerdict(span trace.Span, status *framework.Status) {
+	span.SetAttributes(attribute.String(spanAttrVerdict, status.Code().String()))
+	if msg := status.Message(); msg != "" {
+		span.SetAttributes(attribute.String(spanAttrReason, msg))
+	}
+}
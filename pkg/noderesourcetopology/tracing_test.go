@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestTracingSpans(t *testing.T) {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "1"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", "4"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatalf("failed to create fake client: %v", err)
+	}
+	if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tm := &TopologyMatch{
+		nrtCache:          nrtcache.NewPassthrough(fakeClient),
+		scoreStrategyFunc: leastAllocatedScoreStrategy,
+		scoreStrategyType: apiconfig.LeastAllocated,
+	}
+	WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))(tm)
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+
+	fittingPod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+	if status := tm.Filter(context.Background(), framework.NewCycleState(), fittingPod, nodeInfo); status != nil {
+		t.Fatalf("unexpected status: %v", status)
+	}
+	unalignablePod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("5"),
+		v1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+	if status := tm.Filter(context.Background(), framework.NewCycleState(), unalignablePod, nodeInfo); status.IsSuccess() {
+		t.Fatalf("unexpected success")
+	}
+	score, status := tm.Score(context.Background(), framework.NewCycleState(), fittingPod, "node1")
+	if status != nil {
+		t.Fatalf("unexpected status: %v", status)
+	}
+
+	expected := []struct {
+		name  string
+		attrs []attribute.KeyValue
+	}{
+		{
+			name: Name + "/Filter",
+			attrs: []attribute.KeyValue{
+				attribute.String(spanAttrNode, "node1"),
+				attribute.String(spanAttrPolicy, "single-numa-node"),
+				attribute.String(spanAttrScope, "pod"),
+				attribute.IntSlice(spanAttrNUMANodes, []int{1}),
+				attribute.String(spanAttrVerdict, framework.Success.String()),
+			},
+		},
+		{
+			name: Name + "/Filter",
+			attrs: []attribute.KeyValue{
+				attribute.String(spanAttrNode, "node1"),
+				attribute.String(spanAttrPolicy, "single-numa-node"),
+				attribute.String(spanAttrScope, "pod"),
+				attribute.String(spanAttrVerdict, framework.Unschedulable.String()),
+				attribute.String(spanAttrReason, "cannot align pod"),
+			},
+		},
+		{
+			name: Name + "/Score",
+			attrs: []attribute.KeyValue{
+				attribute.String(spanAttrNode, "node1"),
+				attribute.String(spanAttrPolicy, "single-numa-node"),
+				attribute.String(spanAttrScope, "pod"),
+				attribute.Int64(spanAttrScore, score),
+				attribute.String(spanAttrVerdict, framework.Success.String()),
+			},
+		},
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != len(expected) {
+		t.Fatalf("got %d spans, expected %d", len(spans), len(expected))
+	}
+	for i, span := range spans {
+		if span.Name() != expected[i].name {
+			t.Errorf("span %d: name=%q expected=%q", i, span.Name(), expected[i].name)
+		}
+		if !reflect.DeepEqual(span.Attributes(), expected[i].attrs) {
+			t.Errorf("span %d: attributes=%v expected=%v", i, span.Attributes(), expected[i].attrs)
+		}
+	}
+}
+
+func TestTracingDisabled(t *testing.T) {
+	tm := &TopologyMatch{}
+	ctx := context.Background()
+	gotCtx, span := tm.startSpan(ctx, "Filter", "node1")
+	if gotCtx != ctx {
+		t.Errorf("context changed without tracer")
+	}
+	if span.IsRecording() {
+		t.Errorf("span recording without tracer")
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// unalignedResourceState records in the CycleState that the pod could not be aligned on a node, and the resource
+// which left no candidate NUMA node, if known.
+type unalignedResourceState struct {
+	resource v1.ResourceName
+}
+
+func (s *unalignedResourceState) Clone() framework.StateData {
+	return s
+}
+
+func unalignedResourceStateKey(nodeName string) framework.StateKey {
+	return framework.StateKey(Name + "/unalignedResource/" + nodeName)
+}
+
+// recordUnalignedResource records that the filter rejected the node for the NUMA alignment, and the resource to blame, if known.
+// The filter runs concurrently on many nodes, so each node gets its own key.
+func recordUnalignedResource(cycleState *framework.CycleState, nodeName string, resName v1.ResourceName) {
+	cycleState.Write(unalignedResourceStateKey(nodeName), &unalignedResourceState{resource: resName})
+}
+
+// PostFilter doesn't try to make the pod schedulable in this cycle: it summarizes, across the rejected nodes, the resources
+// which could not be NUMA-aligned, so the scheduling events of the pod tell what is lacking cluster-wide, and downgrades
+// the pods accepting it to the best-effort alignment for their next attempts.
+func (tm *TopologyMatch) PostFilter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	nodesByResource, unaligned := unalignedResources(cycleState, filteredNodeStatusMap)
+	if !unaligned {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+	var reasons []string
+	if msg := unalignedResourcesMessage(nodesByResource); msg != "" {
+		klog.V(4).InfoS("no node can align the pod", "pod", klog.KObj(pod), "diagnosis", msg)
+		reasons = append(reasons, msg)
+	}
+	if wantsDowngrade(pod) && tm.downgrades.downgrade(pod) {
+		reasons = append(reasons, "downgraded to best-effort NUMA alignment")
+	}
+	return nil, framework.NewStatus(framework.Unschedulable, reasons...)
+}
+
+// unalignedResources returns how many of the rejected nodes lacked each NUMA-local resource, and true if any node
+// was rejected because the pod could not be aligned, even with no resource to blame.
+func unalignedResources(cycleState *framework.CycleState, filteredNodeStatusMap framework.NodeToStatusMap) (map[v1.ResourceName]int, bool) {
+	nodesByResource := make(map[v1.ResourceName]int)
+	unaligned := false
+	for nodeName := range filteredNodeStatusMap {
+		data, err := cycleState.Read(unalignedResourceStateKey(nodeName))
+		if err != nil {
+			continue
+		}
+		state, ok := data.(*unalignedResourceState)
+		if !ok {
+			continue
+		}
+		unaligned = true
+		if state.resource != "" {
+			nodesByResource[state.resource]++
+		}
+	}
+	return nodesByResource, unaligned
+}
+
+// unalignedResourcesMessage returns how many nodes lacked each NUMA-local resource, most common first,
+// or the empty string if no resource is to blame.
+func unalignedResourcesMessage(nodesByResource map[v1.ResourceName]int) string {
+	if len(nodesByResource) == 0 {
+		return ""
+	}
+
+	resNames := make([]v1.ResourceName, 0, len(nodesByResource))
+	for resName := range nodesByResource {
+		resNames = append(resNames, resName)
+	}
+	sort.Slice(resNames, func(i, j int) bool {
+		if nodesByResource[resNames[i]] != nodesByResource[resNames[j]] {
+			return nodesByResource[resNames[i]] > nodesByResource[resNames[j]]
+		}
+		return resNames[i] < resNames[j]
+	})
+
+	items := make([]string, 0, len(resNames))
+	for _, resName := range resNames {
+		count := nodesByResource[resName]
+		noun, verb := "nodes", "lack"
+		if count == 1 {
+			noun, verb = "node", "lacks"
+		}
+		items = append(items, fmt.Sprintf("%d %s %s NUMA-local %s", count, noun, verb, resName))
+	}
+	return "cannot align pod: " + strings.Join(items, ", ")
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestPostFilterUnalignedResources(t *testing.T) {
+	const gpu = "nvidia.com/gpu"
+
+	makeNRT := func(name, numaCPUs, numaGPUs string) *topologyv1alpha2.NodeResourceTopology {
+		zone := func(zoneName string) topologyv1alpha2.Zone {
+			return topologyv1alpha2.Zone{
+				Name: zoneName,
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "4", numaCPUs),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+					MakeTopologyResInfo(gpu, "2", numaGPUs),
+				},
+			}
+		}
+		return &topologyv1alpha2.NodeResourceTopology{
+			ObjectMeta:       metav1.ObjectMeta{Name: name},
+			TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+			Zones:            topologyv1alpha2.ZoneList{zone("node-0"), zone("node-1")},
+		}
+	}
+
+	// the pod fits each node as a whole, but no NUMA node
+	pod := makePodByResourceList(&v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("2"),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+		gpu:               resource.MustParse("2"),
+	})
+
+	tests := []struct {
+		name        string
+		nrts        []*topologyv1alpha2.NodeResourceTopology
+		wantMessage string
+	}{
+		{
+			name: "uniform GPU shortage",
+			nrts: []*topologyv1alpha2.NodeResourceTopology{
+				makeNRT("node1", "4", "1"),
+				makeNRT("node2", "4", "1"),
+				makeNRT("node3", "4", "1"),
+			},
+			wantMessage: "cannot align pod: 3 nodes lack NUMA-local nvidia.com/gpu",
+		},
+		{
+			name: "mixed shortages",
+			nrts: []*topologyv1alpha2.NodeResourceTopology{
+				makeNRT("node1", "4", "1"),
+				makeNRT("node2", "1", "2"),
+				makeNRT("node3", "4", "1"),
+			},
+			wantMessage: "cannot align pod: 2 nodes lack NUMA-local nvidia.com/gpu, 1 node lacks NUMA-local cpu",
+		},
+		{
+			name: "no alignment failure",
+			nrts: []*topologyv1alpha2.NodeResourceTopology{
+				makeNRT("node1", "4", "2"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient, err := tu.NewFakeClient()
+			if err != nil {
+				t.Fatalf("failed to create fake client: %v", err)
+			}
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(fakeClient),
+			}
+
+			cycleState := framework.NewCycleState()
+			statuses := make(framework.NodeToStatusMap)
+			for _, nrt := range tt.nrts {
+				if err := fakeClient.Create(context.Background(), nrt.DeepCopy()); err != nil {
+					t.Fatal(err)
+				}
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+				if status := tm.Filter(context.Background(), cycleState, pod, nodeInfo); status != nil {
+					statuses[nrt.Name] = status.WithFailedPlugin(Name)
+				}
+			}
+
+			result, status := tm.PostFilter(context.Background(), cycleState, pod, statuses)
+			if result != nil {
+				t.Errorf("unexpected result: %v", result)
+			}
+			if status.Code() != framework.Unschedulable {
+				t.Errorf("unexpected status code: %v", status.Code())
+			}
+			if got := status.Message(); got != tt.wantMessage {
+				t.Errorf("message does not match: %q, want: %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
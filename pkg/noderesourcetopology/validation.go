@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+)
+
+var knownTopologyPolicies = map[topologyv1alpha2.TopologyManagerPolicy]bool{
+	topologyv1alpha2.SingleNUMANodeContainerLevel: true,
+	topologyv1alpha2.SingleNUMANodePodLevel:       true,
+	topologyv1alpha2.Restricted:                   true,
+	topologyv1alpha2.RestrictedContainerLevel:     true,
+	topologyv1alpha2.RestrictedPodLevel:           true,
+	topologyv1alpha2.BestEffort:                   true,
+	topologyv1alpha2.BestEffortContainerLevel:     true,
+	topologyv1alpha2.BestEffortPodLevel:           true,
+	topologyv1alpha2.None:                         true,
+}
+
+// ValidateNRT checks the NRT object for the issues which affect the decisions of the plugin, and returns all of them.
+// Meant to be used by the NRT producers in their tests. The checks are:
+// - the topology manager policy and scope are known, both in the attributes and in the deprecated topologyPolicies,
+// and listed at most once in the attributes
+// - the hint providers listed in the attributes are known resource managers
+// - the NUMA zones have names like "node-<ID>", with the IDs in range and unique
+// - the resource quantities are not negative, and the available and allocatable quantities don't exceed
+// the capacity, nor the available quantities exceed the allocatable, if reported
+// - the costs of the NUMA zones only refer to NUMA zones of the same object
+func ValidateNRT(nrt *topologyv1alpha2.NodeResourceTopology) []error {
+	var errs []error
+
+	for _, policy := range nrt.TopologyPolicies {
+		if !knownTopologyPolicies[topologyv1alpha2.TopologyManagerPolicy(policy)] {
+			errs = append(errs, fmt.Errorf("unknown topology policy %q", policy))
+		}
+	}
+	seenAttrs := make(map[string]bool)
+	for _, attr := range nrt.Attributes {
+		if attr.Name == AttributePolicy || attr.Name == AttributeScope {
+			if seenAttrs[attr.Name] {
+				errs = append(errs, fmt.Errorf("duplicate attribute %q", attr.Name))
+			}
+			seenAttrs[attr.Name] = true
+		}
+		if attr.Name == AttributePolicy && !IsValidPolicy(attr.Value) {
+			errs = append(errs, fmt.Errorf("unknown topology manager policy %q", attr.Value))
+		}
+		if attr.Name == AttributeScope && !IsValidScope(attr.Value) {
+			errs = append(errs, fmt.Errorf("unknown topology manager scope %q", attr.Value))
+		}
+		if attr.Name == AttributeHintProviders {
+			for _, provider := range strings.Split(attr.Value, ",") {
+				if !IsValidHintProvider(strings.TrimSpace(provider)) {
+					errs = append(errs, fmt.Errorf("unknown hint provider %q", provider))
+				}
+			}
+		}
+	}
+
+	numaZones := make(map[string]bool)
+	numaIDs := make(map[int]string)
+	for _, zone := range nrt.Zones {
+		if zone.Type != "Node" {
+			continue
+		}
+		numaZones[zone.Name] = true
+		numaID, err := getID(zone.Name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if name, ok := numaIDs[numaID]; ok {
+			errs = append(errs, fmt.Errorf("zone %q: NUMA ID %d already used by zone %q", zone.Name, numaID, name))
+			continue
+		}
+		numaIDs[numaID] = zone.Name
+	}
+
+	for _, zone := range nrt.Zones {
+		errs = append(errs, validateZoneResources(zone)...)
+		if zone.Type != "Node" {
+			continue
+		}
+		for _, cost := range zone.Costs {
+			if !numaZones[cost.Name] {
+				errs = append(errs, fmt.Errorf("zone %q: cost refers to unknown NUMA zone %q", zone.Name, cost.Name))
+			}
+		}
+	}
+	return errs
+}
+
+func validateZoneResources(zone topologyv1alpha2.Zone) []error {
+	var errs []error
+	for _, resInfo := range zone.Resources {
+		quantities := []struct {
+			name     string
+			quantity resource.Quantity
+		}{
+			{name: "capacity", quantity: resInfo.Capacity},
+			{name: "allocatable", quantity: resInfo.Allocatable},
+			{name: "available", quantity: resInfo.Available},
+		}
+		negative := false
+		for _, q := range quantities {
+			if q.quantity.Sign() < 0 {
+				errs = append(errs, fmt.Errorf("zone %q: resource %q: negative %s %s", zone.Name, resInfo.Name, q.name, q.quantity.String()))
+				negative = true
+			}
+		}
+		if negative {
+			continue
+		}
+		if resInfo.Allocatable.Cmp(resInfo.Capacity) > 0 {
+			errs = append(errs, fmt.Errorf("zone %q: resource %q: allocatable %s exceeds capacity %s", zone.Name, resInfo.Name, resInfo.Allocatable.String(), resInfo.Capacity.String()))
+		}
+		if resInfo.Available.Cmp(resInfo.Capacity) > 0 {
+			errs = append(errs, fmt.Errorf("zone %q: resource %q: available %s exceeds capacity %s", zone.Name, resInfo.Name, resInfo.Available.String(), resInfo.Capacity.String()))
+		}
+		// some producers don't report the allocatable quantity
+		if !resInfo.Allocatable.IsZero() && resInfo.Available.Cmp(resInfo.Allocatable) > 0 {
+			errs = append(errs, fmt.Errorf("zone %q: resource %q: available %s exceeds allocatable %s", zone.Name, resInfo.Name, resInfo.Available.String(), resInfo.Allocatable.String()))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"strings"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateNRT(t *testing.T) {
+	validZone := func(name string) topologyv1alpha2.Zone {
+		return topologyv1alpha2.Zone{
+			Name: name,
+			Type: "Node",
+			Resources: topologyv1alpha2.ResourceInfoList{
+				{
+					Name:        cpu,
+					Capacity:    resource.MustParse("4"),
+					Allocatable: resource.MustParse("3"),
+					Available:   resource.MustParse("2"),
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		nrt          *topologyv1alpha2.NodeResourceTopology
+		expectedErrs []string
+	}{
+		{
+			name: "valid",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Attributes: topologyv1alpha2.AttributeList{
+					{Name: AttributePolicy, Value: "single-numa-node"},
+					{Name: AttributeScope, Value: "pod"},
+				},
+				Zones: topologyv1alpha2.ZoneList{
+					func() topologyv1alpha2.Zone {
+						z := validZone("node-0")
+						z.Costs = topologyv1alpha2.CostList{{Name: "node-0", Value: 10}, {Name: "node-1", Value: 20}}
+						return z
+					}(),
+					validZone("node-1"),
+					{Name: "socket-0", Type: "Socket"},
+				},
+			},
+		},
+		{
+			name: "unknown policies and scope",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+				TopologyPolicies: []string{"SingleNUMANode"},
+				Attributes: topologyv1alpha2.AttributeList{
+					{Name: AttributePolicy, Value: "single-numa"},
+					{Name: AttributeScope, Value: "node"},
+				},
+			},
+			expectedErrs: []string{
+				`unknown topology policy "SingleNUMANode"`,
+				`unknown topology manager policy "single-numa"`,
+				`unknown topology manager scope "node"`,
+			},
+		},
+		{
+			name: "unknown hint providers",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Attributes: topologyv1alpha2.AttributeList{
+					{Name: AttributeHintProviders, Value: "device,cpumanager"},
+				},
+			},
+			expectedErrs: []string{
+				`unknown hint provider "cpumanager"`,
+			},
+		},
+		{
+			name: "duplicate attributes",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Attributes: topologyv1alpha2.AttributeList{
+					{Name: AttributePolicy, Value: "single-numa-node"},
+					{Name: AttributeScope, Value: "pod"},
+					{Name: AttributePolicy, Value: "restricted"},
+				},
+			},
+			expectedErrs: []string{
+				`duplicate attribute "topologyManagerPolicy"`,
+			},
+		},
+		{
+			name: "NUMA IDs out of range, malformed and duplicated",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Zones: topologyv1alpha2.ZoneList{
+					validZone("node-0"),
+					validZone("node-00"),
+					validZone("node-64"),
+					validZone("numa-1"),
+				},
+			},
+			expectedErrs: []string{
+				`zone "node-00": NUMA ID 0 already used by zone "node-0"`,
+				"invalid NUMA id range numaID: 64",
+				"invalid zone format zone: numa-1",
+			},
+		},
+		{
+			name: "negative resources",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Zones: topologyv1alpha2.ZoneList{
+					{
+						Name: "node-0",
+						Type: "Node",
+						Resources: topologyv1alpha2.ResourceInfoList{
+							{
+								Name:        cpu,
+								Capacity:    resource.MustParse("4"),
+								Allocatable: resource.MustParse("-1"),
+								Available:   resource.MustParse("-2"),
+							},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{
+				`zone "node-0": resource "cpu": negative allocatable -1`,
+				`zone "node-0": resource "cpu": negative available -2`,
+			},
+		},
+		{
+			name: "inconsistent quantities",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Zones: topologyv1alpha2.ZoneList{
+					{
+						Name: "node-0",
+						Type: "Node",
+						Resources: topologyv1alpha2.ResourceInfoList{
+							{
+								Name:        cpu,
+								Capacity:    resource.MustParse("4"),
+								Allocatable: resource.MustParse("5"),
+								Available:   resource.MustParse("2"),
+							},
+							{
+								Name:        memory,
+								Capacity:    resource.MustParse("8Gi"),
+								Allocatable: resource.MustParse("6Gi"),
+								Available:   resource.MustParse("7Gi"),
+							},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{
+				`zone "node-0": resource "cpu": allocatable 5 exceeds capacity 4`,
+				`zone "node-0": resource "memory": available 7Gi exceeds allocatable 6Gi`,
+			},
+		},
+		{
+			name: "costs referring to unknown NUMA zones",
+			nrt: &topologyv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+				Zones: topologyv1alpha2.ZoneList{
+					func() topologyv1alpha2.Zone {
+						z := validZone("node-0")
+						z.Costs = topologyv1alpha2.CostList{{Name: "node-0", Value: 10}, {Name: "node-1", Value: 20}}
+						return z
+					}(),
+				},
+			},
+			expectedErrs: []string{
+				`zone "node-0": cost refers to unknown NUMA zone "node-1"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateNRT(tt.nrt)
+			if len(errs) != len(tt.expectedErrs) {
+				t.Fatalf("got %d errors %v, expected %d", len(errs), errs, len(tt.expectedErrs))
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), tt.expectedErrs[i]) {
+					t.Errorf("error %d: %q does not contain %q", i, err.Error(), tt.expectedErrs[i])
+				}
+			}
+		})
+	}
+}